@@ -2,7 +2,9 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	goErrors "errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,45 +31,63 @@ var (
 	Routes = endpoints{
 		version: "/v1/",
 		auth: authEndpoints{
-			signInOTP:                "auth/otp/signin",
-			signUpOTP:                "auth/otp/signup",
-			signUpOrInOTP:            "auth/otp/signup-in",
-			signUpTOTP:               "auth/totp/signup",
-			updateTOTP:               "auth/totp/update",
-			verifyTOTPCode:           "auth/totp/verify",
-			verifyCode:               "auth/otp/verify",
-			signInMagicLink:          "auth/magiclink/signin",
-			signUpMagicLink:          "auth/magiclink/signup",
-			signUpOrInMagicLink:      "auth/magiclink/signup-in",
-			verifyMagicLink:          "auth/magiclink/verify",
-			oauthStart:               "auth/oauth/authorize",
-			exchangeTokenOAuth:       "auth/oauth/exchange",
-			samlStart:                "auth/saml/authorize",
-			exchangeTokenSAML:        "auth/saml/exchange",
-			webauthnSignUpStart:      "auth/webauthn/signup/start",
-			webauthnSignUpFinish:     "auth/webauthn/signup/finish",
-			webauthnSignInStart:      "auth/webauthn/signin/start",
-			webauthnSignInFinish:     "auth/webauthn/signin/finish",
-			webauthnSignUpOrInStart:  "auth/webauthn/signup-in/start",
-			webauthnUpdateStart:      "auth/webauthn/update/start",
-			webauthnUpdateFinish:     "auth/webauthn/update/finish",
-			getMagicLinkSession:      "auth/magiclink/pending-session",
-			updateUserEmailMagicLink: "auth/magiclink/update/email",
-			updateUserEmailOTP:       "auth/otp/update/email",
-			updateUserPhoneMagicLink: "auth/magiclink/update/phone",
-			updateUserPhoneOTP:       "auth/otp/update/phone",
-			exchangeAccessKey:        "auth/accesskey/exchange",
+			signInOTP:                    "auth/otp/signin",
+			signUpOTP:                    "auth/otp/signup",
+			signUpOrInOTP:                "auth/otp/signup-in",
+			signUpTOTP:                   "auth/totp/signup",
+			updateTOTP:                   "auth/totp/update",
+			verifyTOTPCode:               "auth/totp/verify",
+			verifyCode:                   "auth/otp/verify",
+			signInMagicLink:              "auth/magiclink/signin",
+			signUpMagicLink:              "auth/magiclink/signup",
+			signUpOrInMagicLink:          "auth/magiclink/signup-in",
+			verifyMagicLink:              "auth/magiclink/verify",
+			signInEnchantedLink:          "auth/enchantedlink/signin",
+			signUpEnchantedLink:          "auth/enchantedlink/signup",
+			signUpOrInEnchantedLink:      "auth/enchantedlink/signup-in",
+			verifyEnchantedLink:          "auth/enchantedlink/verify",
+			getEnchantedLinkSession:      "auth/enchantedlink/pending-session",
+			updateUserEmailEnchantedLink: "auth/enchantedlink/update/email",
+			oauthStart:                   "auth/oauth/authorize",
+			exchangeTokenOAuth:           "auth/oauth/exchange",
+			samlStart:                    "auth/saml/authorize",
+			exchangeTokenSAML:            "auth/saml/exchange",
+			webauthnSignUpStart:          "auth/webauthn/signup/start",
+			webauthnSignUpFinish:         "auth/webauthn/signup/finish",
+			webauthnSignInStart:          "auth/webauthn/signin/start",
+			webauthnSignInFinish:         "auth/webauthn/signin/finish",
+			webauthnSignUpOrInStart:      "auth/webauthn/signup-in/start",
+			webauthnUpdateStart:          "auth/webauthn/update/start",
+			webauthnUpdateFinish:         "auth/webauthn/update/finish",
+			getMagicLinkSession:          "auth/magiclink/pending-session",
+			updateUserEmailMagicLink:     "auth/magiclink/update/email",
+			updateUserEmailOTP:           "auth/otp/update/email",
+			updateUserPhoneMagicLink:     "auth/magiclink/update/phone",
+			updateUserPhoneOTP:           "auth/otp/update/phone",
+			exchangeAccessKey:            "auth/accesskey/exchange",
+			signUpPassword:               "auth/password/signup",
+			signInPassword:               "auth/password/signin",
+			sendPasswordReset:            "auth/password/reset",
+			updateUserPassword:           "auth/password/update",
+			replaceUserPassword:          "auth/password/replace",
+			passwordPolicy:               "auth/password/policy",
 		},
 		mgmt: mgmtEndpoints{
-			tenantCreate:   "mgmt/tenant/create",
-			tenantUpdate:   "mgmt/tenant/update",
-			tenantDelete:   "mgmt/tenant/delete",
-			userCreate:     "mgmt/user/create",
-			userUpdate:     "mgmt/user/update",
-			userDelete:     "mgmt/user/delete",
-			ssoConfigure:   "mgmt/sso/settings",
-			ssoMetadata:    "mgmt/sso/metadata",
-			ssoRoleMapping: "mgmt/sso/roles",
+			tenantCreate:                 "mgmt/tenant/create",
+			tenantUpdate:                 "mgmt/tenant/update",
+			tenantDelete:                 "mgmt/tenant/delete",
+			tenantLoadAll:                "mgmt/tenant/all",
+			userCreate:                   "mgmt/user/create",
+			userUpdate:                   "mgmt/user/update",
+			userDelete:                   "mgmt/user/delete",
+			userDeleteAllTestUsers:       "mgmt/user/test/delete/all",
+			userGenerateEmbeddedLink:     "mgmt/user/signin/embeddedlink",
+			userGenerateOTPForTest:       "mgmt/tests/generate/otp",
+			userGenerateMagicLinkForTest: "mgmt/tests/generate/magiclink",
+			ssoConfigure:                 "mgmt/sso/settings",
+			ssoLoadSettings:              "mgmt/sso/settings",
+			ssoMetadata:                  "mgmt/sso/metadata",
+			ssoRoleMapping:               "mgmt/sso/roles",
 		},
 		logout:    "auth/logout",
 		logoutAll: "auth/logoutall",
@@ -89,46 +109,64 @@ type endpoints struct {
 }
 
 type authEndpoints struct {
-	signInOTP                string
-	signUpOTP                string
-	signUpOrInOTP            string
-	signUpTOTP               string
-	updateTOTP               string
-	verifyTOTPCode           string
-	verifyCode               string
-	signInMagicLink          string
-	signUpMagicLink          string
-	signUpOrInMagicLink      string
-	verifyMagicLink          string
-	oauthStart               string
-	exchangeTokenOAuth       string
-	samlStart                string
-	exchangeTokenSAML        string
-	webauthnSignUpStart      string
-	webauthnSignUpFinish     string
-	webauthnSignInStart      string
-	webauthnSignInFinish     string
-	webauthnSignUpOrInStart  string
-	webauthnUpdateStart      string
-	webauthnUpdateFinish     string
-	getMagicLinkSession      string
-	updateUserEmailMagicLink string
-	updateUserEmailOTP       string
-	updateUserPhoneMagicLink string
-	updateUserPhoneOTP       string
-	exchangeAccessKey        string
+	signInOTP                    string
+	signUpOTP                    string
+	signUpOrInOTP                string
+	signUpTOTP                   string
+	updateTOTP                   string
+	verifyTOTPCode               string
+	verifyCode                   string
+	signInMagicLink              string
+	signUpMagicLink              string
+	signUpOrInMagicLink          string
+	verifyMagicLink              string
+	signInEnchantedLink          string
+	signUpEnchantedLink          string
+	signUpOrInEnchantedLink      string
+	verifyEnchantedLink          string
+	getEnchantedLinkSession      string
+	updateUserEmailEnchantedLink string
+	oauthStart                   string
+	exchangeTokenOAuth           string
+	samlStart                    string
+	exchangeTokenSAML            string
+	webauthnSignUpStart          string
+	webauthnSignUpFinish         string
+	webauthnSignInStart          string
+	webauthnSignInFinish         string
+	webauthnSignUpOrInStart      string
+	webauthnUpdateStart          string
+	webauthnUpdateFinish         string
+	getMagicLinkSession          string
+	updateUserEmailMagicLink     string
+	updateUserEmailOTP           string
+	updateUserPhoneMagicLink     string
+	updateUserPhoneOTP           string
+	exchangeAccessKey            string
+	signUpPassword               string
+	signInPassword               string
+	sendPasswordReset            string
+	updateUserPassword           string
+	replaceUserPassword          string
+	passwordPolicy               string
 }
 
 type mgmtEndpoints struct {
-	tenantCreate   string
-	tenantUpdate   string
-	tenantDelete   string
-	userCreate     string
-	userUpdate     string
-	userDelete     string
-	ssoConfigure   string
-	ssoMetadata    string
-	ssoRoleMapping string
+	tenantCreate                 string
+	tenantUpdate                 string
+	tenantDelete                 string
+	tenantLoadAll                string
+	userCreate                   string
+	userUpdate                   string
+	userDelete                   string
+	userDeleteAllTestUsers       string
+	userGenerateEmbeddedLink     string
+	userGenerateOTPForTest       string
+	userGenerateMagicLinkForTest string
+	ssoConfigure                 string
+	ssoLoadSettings              string
+	ssoMetadata                  string
+	ssoRoleMapping               string
 }
 
 func (e *endpoints) SignInOTP() string {
@@ -164,6 +202,24 @@ func (e *endpoints) SignUpOrInMagicLink() string {
 func (e *endpoints) VerifyMagicLink() string {
 	return path.Join(e.version, e.auth.verifyMagicLink)
 }
+func (e *endpoints) SignInEnchantedLink() string {
+	return path.Join(e.version, e.auth.signInEnchantedLink)
+}
+func (e *endpoints) SignUpEnchantedLink() string {
+	return path.Join(e.version, e.auth.signUpEnchantedLink)
+}
+func (e *endpoints) SignUpOrInEnchantedLink() string {
+	return path.Join(e.version, e.auth.signUpOrInEnchantedLink)
+}
+func (e *endpoints) VerifyEnchantedLink() string {
+	return path.Join(e.version, e.auth.verifyEnchantedLink)
+}
+func (e *endpoints) GetEnchantedLinkSession() string {
+	return path.Join(e.version, e.auth.getEnchantedLinkSession)
+}
+func (e *endpoints) UpdateUserEmailEnchantedLink() string {
+	return path.Join(e.version, e.auth.updateUserEmailEnchantedLink)
+}
 func (e *endpoints) OAuthStart() string {
 	return path.Join(e.version, e.auth.oauthStart)
 }
@@ -236,6 +292,30 @@ func (e *endpoints) ExchangeAccessKey() string {
 	return path.Join(e.version, e.auth.exchangeAccessKey)
 }
 
+func (e *endpoints) SignUpPassword() string {
+	return path.Join(e.version, e.auth.signUpPassword)
+}
+
+func (e *endpoints) SignInPassword() string {
+	return path.Join(e.version, e.auth.signInPassword)
+}
+
+func (e *endpoints) SendPasswordReset() string {
+	return path.Join(e.version, e.auth.sendPasswordReset)
+}
+
+func (e *endpoints) UpdateUserPassword() string {
+	return path.Join(e.version, e.auth.updateUserPassword)
+}
+
+func (e *endpoints) ReplaceUserPassword() string {
+	return path.Join(e.version, e.auth.replaceUserPassword)
+}
+
+func (e *endpoints) PasswordPolicy() string {
+	return path.Join(e.version, e.auth.passwordPolicy)
+}
+
 func (e *endpoints) ManagementTenantCreate() string {
 	return path.Join(e.version, e.mgmt.tenantCreate)
 }
@@ -248,6 +328,10 @@ func (e *endpoints) ManagementTenantDelete() string {
 	return path.Join(e.version, e.mgmt.tenantDelete)
 }
 
+func (e *endpoints) ManagementTenantLoadAll() string {
+	return path.Join(e.version, e.mgmt.tenantLoadAll)
+}
+
 func (e *endpoints) ManagementUserCreate() string {
 	return path.Join(e.version, e.mgmt.userCreate)
 }
@@ -260,10 +344,30 @@ func (e *endpoints) ManagementUserDelete() string {
 	return path.Join(e.version, e.mgmt.userDelete)
 }
 
+func (e *endpoints) ManagementUserDeleteAllTestUsers() string {
+	return path.Join(e.version, e.mgmt.userDeleteAllTestUsers)
+}
+
+func (e *endpoints) ManagementUserGenerateEmbeddedLink() string {
+	return path.Join(e.version, e.mgmt.userGenerateEmbeddedLink)
+}
+
+func (e *endpoints) ManagementUserGenerateOTPForTest() string {
+	return path.Join(e.version, e.mgmt.userGenerateOTPForTest)
+}
+
+func (e *endpoints) ManagementUserGenerateMagicLinkForTest() string {
+	return path.Join(e.version, e.mgmt.userGenerateMagicLinkForTest)
+}
+
 func (e *endpoints) ManagementSSOConfigure() string {
 	return path.Join(e.version, e.mgmt.ssoConfigure)
 }
 
+func (e *endpoints) ManagementSSOLoadSettings() string {
+	return path.Join(e.version, e.mgmt.ssoLoadSettings)
+}
+
 func (e *endpoints) ManagementSSOMetadata() string {
 	return path.Join(e.version, e.mgmt.ssoMetadata)
 }
@@ -283,6 +387,10 @@ type ClientParams struct {
 	BaseURL              string
 	DefaultClient        IHttpClient
 	CustomDefaultHeaders map[string]string
+	AuthRateLimit        RateLimit
+	ManagementRateLimit  RateLimit
+	Middlewares          []Middleware
+	Observer             RequestObserver
 
 	ProjectID string
 }
@@ -292,11 +400,14 @@ type IHttpClient interface {
 }
 
 type Client struct {
-	httpClient IHttpClient
-	uri        string
-	headers    map[string]string
-	conf       ClientParams
-	sdkInfo    *sdkInfo
+	httpClient  IHttpClient
+	uri         string
+	headers     map[string]string
+	conf        ClientParams
+	sdkInfo     *sdkInfo
+	ctx         context.Context
+	authLimiter *limiter
+	mgmtLimiter *limiter
 }
 type HTTPResponse struct {
 	Req     *http.Request
@@ -312,21 +423,30 @@ type HTTPRequest struct {
 	Cookies     []*http.Cookie
 }
 
+// NewDefaultHttpClient - creates the http client used by clients that are not given a DefaultClient, with its own pool
+// of connections. Clients of several projects can share one by setting it as their DefaultClient.
+func NewDefaultHttpClient() *http.Client {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = 100
+	t.MaxConnsPerHost = 100
+	t.MaxIdleConnsPerHost = 100
+
+	return &http.Client{
+		Timeout:   time.Second * 10,
+		Transport: t,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
 func NewClient(conf ClientParams) *Client {
 	httpClient := conf.DefaultClient
 	if httpClient == nil {
-		t := http.DefaultTransport.(*http.Transport).Clone()
-		t.MaxIdleConns = 100
-		t.MaxConnsPerHost = 100
-		t.MaxIdleConnsPerHost = 100
-
-		httpClient = &http.Client{
-			Timeout:   time.Second * 10,
-			Transport: t,
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		}
+		httpClient = NewDefaultHttpClient()
+	}
+	for i := len(conf.Middlewares) - 1; i >= 0; i-- {
+		httpClient = conf.Middlewares[i](httpClient)
 	}
 	defaultHeaders := map[string]string{}
 
@@ -339,18 +459,36 @@ func NewClient(conf ClientParams) *Client {
 	}
 
 	return &Client{
-		uri:        conf.BaseURL,
-		httpClient: httpClient,
-		headers:    defaultHeaders,
-		conf:       conf,
-		sdkInfo:    getSDKInfo(),
+		uri:         conf.BaseURL,
+		httpClient:  httpClient,
+		headers:     defaultHeaders,
+		conf:        conf,
+		sdkInfo:     getSDKInfo(),
+		ctx:         context.Background(),
+		authLimiter: newLimiter(conf.AuthRateLimit),
+		mgmtLimiter: newLimiter(conf.ManagementRateLimit),
 	}
 }
 
+// WithContext - returns a copy of the client that sends its requests with the given context, which also ends waiting
+// for the rate limits when done. The copy shares the http client and the rate limits of the client.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
 func (c *Client) DoGetRequest(uri string, options *HTTPRequest, pswd string) (*HTTPResponse, error) {
 	return c.DoRequest(http.MethodGet, uri, nil, options, pswd)
 }
 
+func (c *Client) DoDeleteRequest(uri string, options *HTTPRequest, pswd string) (*HTTPResponse, error) {
+	return c.DoRequest(http.MethodDelete, uri, nil, options, pswd)
+}
+
 func (c *Client) DoPostRequest(uri string, body interface{}, options *HTTPRequest, pswd string) (*HTTPResponse, error) {
 	if options == nil {
 		options = &HTTPRequest{}
@@ -380,6 +518,21 @@ func (c *Client) DoPostRequest(uri string, body interface{}, options *HTTPReques
 }
 
 func (c *Client) DoRequest(method, uriPath string, body io.Reader, options *HTTPRequest, pswd string) (*HTTPResponse, error) {
+	event := RequestEvent{Context: c.ctx, ProjectID: c.conf.ProjectID, Method: method, Route: strings.TrimSuffix(path.Join("/", uriPath), "/"+c.conf.ProjectID), Start: time.Now()}
+	res, err := c.doRequest(method, uriPath, body, options, pswd, &event)
+	if observer := c.conf.Observer; observer != nil {
+		event.Duration = time.Since(event.Start)
+		event.Err = err
+		var webErr *errors.WebError
+		if goErrors.As(err, &webErr) {
+			event.ErrorCode = webErr.Code
+		}
+		observer.ObserveRequest(event)
+	}
+	return res, err
+}
+
+func (c *Client) doRequest(method, uriPath string, body io.Reader, options *HTTPRequest, pswd string, event *RequestEvent) (*HTTPResponse, error) {
 	if options == nil {
 		options = &HTTPRequest{}
 	}
@@ -393,7 +546,7 @@ func (c *Client) DoRequest(method, uriPath string, body io.Reader, options *HTTP
 	req := options.Request
 	if req == nil {
 		var err error
-		req, err = http.NewRequest(method, url, body)
+		req, err = http.NewRequestWithContext(c.ctx, method, url, body)
 		if err != nil {
 			return nil, err
 		}
@@ -432,6 +585,17 @@ func (c *Client) DoRequest(method, uriPath string, body io.Reader, options *HTTP
 	}
 	req.Header.Set(AuthorizationHeaderName, BearerAuthorizationPrefix+bearer)
 	c.addDescopeHeaders(req)
+	event.Context = req.Context()
+
+	limiter := c.authLimiter
+	if isManagementPath(uriPath) {
+		limiter = c.mgmtLimiter
+	}
+	if err := limiter.acquire(req.Context()); err != nil {
+		logger.LogInfo("failed waiting to send request to [%s]", url)
+		return nil, err
+	}
+	defer limiter.release()
 
 	logger.LogDebug("sending request to [%s]", url)
 	response, err := c.httpClient.Do(req)
@@ -439,6 +603,7 @@ func (c *Client) DoRequest(method, uriPath string, body io.Reader, options *HTTP
 		logger.LogInfo("failed sending request to [%s]", url)
 		return nil, err
 	}
+	event.StatusCode = response.StatusCode
 
 	if response.Body != nil {
 		defer response.Body.Close()
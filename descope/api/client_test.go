@@ -65,6 +65,20 @@ func TestGetRequest(t *testing.T) {
 	assert.EqualValues(t, expectedResponse, res.BodyStr)
 }
 
+func TestDeleteRequest(t *testing.T) {
+	projectID := "test"
+	c := NewClient(ClientParams{ProjectID: projectID, DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+		assert.Nil(t, r.Body)
+		assert.EqualValues(t, http.MethodDelete, r.Method)
+		assert.EqualValues(t, "/path", r.URL.Path)
+		_, actualKey := getProjectAndJwt(r)
+		assert.EqualValues(t, "key", actualKey)
+		return &http.Response{Body: io.NopCloser(strings.NewReader("{}")), StatusCode: http.StatusOK}, nil
+	})})
+	_, err := c.DoDeleteRequest("path", nil, "key")
+	require.NoError(t, err)
+}
+
 func TestPostRequest(t *testing.T) {
 	type dummy struct {
 		Test string
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"math"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit - limits the requests a client sends to Descope, such as to avoid being rate limited by Descope during batch jobs.
+// The zero value does not limit requests.
+type RateLimit struct {
+	// RequestsPerSecond (optional, 0) - the sustained rate of requests, requests are not rate limited when 0.
+	RequestsPerSecond float64
+	// Burst (optional, 1) - the number of requests that can be sent at once when no requests were sent for a while.
+	Burst int
+	// MaxInFlight (optional, 0) - the maximum number of requests sent concurrently, not limited when 0.
+	MaxInFlight int
+}
+
+// limiter is a token bucket rate limiter and a semaphore, waits end when the context of the request is done
+type limiter struct {
+	rate   float64
+	burst  float64
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	sem    chan struct{}
+	now    func() time.Time
+}
+
+func newLimiter(limit RateLimit) *limiter {
+	if limit.RequestsPerSecond <= 0 && limit.MaxInFlight <= 0 {
+		return nil
+	}
+	l := &limiter{now: time.Now}
+	if limit.RequestsPerSecond > 0 {
+		l.rate = limit.RequestsPerSecond
+		l.burst = math.Max(float64(limit.Burst), 1)
+		l.tokens = l.burst
+		l.last = l.now()
+	}
+	if limit.MaxInFlight > 0 {
+		l.sem = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// acquire waits until a request can be sent, release must be called when it completes unless an error is returned
+func (l *limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if err := l.wait(ctx); err != nil {
+		return err
+	}
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (l *limiter) release() {
+	if l != nil && l.sem != nil {
+		<-l.sem
+	}
+}
+
+// wait takes a token from the bucket, waiting for it to be refilled when empty
+func (l *limiter) wait(ctx context.Context) error {
+	if l.rate == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	delay := time.Duration(0)
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// return the reserved token so waiting requests are not delayed by a canceled one
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// isManagementPath reports whether the given path is of a management API route
+func isManagementPath(uriPath string) bool {
+	return strings.HasPrefix(path.Join("/", uriPath)+"/", path.Join(Routes.version, "mgmt")+"/")
+}
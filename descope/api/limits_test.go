@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/descope/go-sdk/descope/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoLimits(t *testing.T) {
+	l := newLimiter(RateLimit{})
+	assert.Nil(t, l)
+	require.NoError(t, l.acquire(context.Background()))
+	l.release()
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(RateLimit{RequestsPerSecond: 10, Burst: 2})
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	// the burst is sent at once
+	for i := 0; i < 2; i++ {
+		require.NoError(t, l.wait(context.Background()))
+	}
+	assert.InDelta(t, 0, l.tokens, 0.001)
+
+	// the bucket is refilled over time, but not beyond the burst
+	now = now.Add(150 * time.Millisecond)
+	require.NoError(t, l.wait(context.Background()))
+	assert.InDelta(t, 0.5, l.tokens, 0.001)
+	now = now.Add(time.Hour)
+	require.NoError(t, l.wait(context.Background()))
+	assert.InDelta(t, 1, l.tokens, 0.001)
+}
+
+func TestRateLimitWait(t *testing.T) {
+	l := newLimiter(RateLimit{RequestsPerSecond: 50})
+	require.NoError(t, l.acquire(context.Background()))
+	start := time.Now()
+	require.NoError(t, l.acquire(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestRateLimitWaitCanceled(t *testing.T) {
+	l := newLimiter(RateLimit{RequestsPerSecond: 0.1})
+	require.NoError(t, l.acquire(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := l.acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// the token reserved by the canceled request was returned
+	assert.Greater(t, l.tokens, -1.0)
+}
+
+func TestMaxInFlight(t *testing.T) {
+	l := newLimiter(RateLimit{MaxInFlight: 1})
+	require.NoError(t, l.acquire(context.Background()))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, l.acquire(ctx), context.Canceled)
+	l.release()
+	require.NoError(t, l.acquire(context.Background()))
+	l.release()
+}
+
+func TestIsManagementPath(t *testing.T) {
+	assert.True(t, isManagementPath(Routes.ManagementUserCreate()))
+	assert.True(t, isManagementPath("v1/mgmt/user/create"))
+	assert.False(t, isManagementPath(Routes.SignInOTP()))
+	assert.False(t, isManagementPath(Routes.GetKeys()))
+	assert.False(t, isManagementPath("/v1/mgmtx"))
+}
+
+func TestDoRequestLimits(t *testing.T) {
+	started, unblock := make(chan struct{}), make(chan struct{})
+	c := NewClient(ClientParams{ProjectID: "test", ManagementRateLimit: RateLimit{MaxInFlight: 1}, DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+		if isManagementPath(r.URL.Path) {
+			started <- struct{}{}
+			<-unblock
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})})
+
+	done := make(chan error)
+	go func() {
+		_, err := c.DoPostRequest(Routes.ManagementUserCreate(), nil, nil, "")
+		done <- err
+	}()
+	<-started
+
+	// auth requests are limited separately
+	_, err := c.DoPostRequest(Routes.SignInOTP(), nil, nil, "")
+	require.NoError(t, err)
+
+	// management requests wait for the request in flight until their context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.WithContext(ctx).DoPostRequest(Routes.ManagementUserUpdate(), nil, nil, "")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(unblock)
+	require.NoError(t, <-done)
+}
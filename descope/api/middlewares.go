@@ -0,0 +1,77 @@
+package api
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/descope/go-sdk/descope/logger"
+)
+
+// Doer - sends http requests, such as an http.Client, an alias of IHttpClient
+type Doer = IHttpClient
+
+// DoerFunc - an adapter to use a function as a Doer
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do - calls f(req)
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware - wraps the sending of the requests of a client, such as to add tracing headers, custom authentication,
+// logging or metrics. A middleware calls next to send the request, or returns a response or an error without calling it.
+// Middlewares are called with the request after the client added its headers, the first middleware wraps all the others.
+type Middleware func(next Doer) Doer
+
+// HeadersMiddleware - sets the given headers on every request, such as tracing or routing headers
+func HeadersMiddleware(headers map[string]string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			for key, value := range headers {
+				req.Header.Set(key, value)
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// LoggingMiddleware - logs the method, path, status and duration of every request at the info log level
+func LoggingMiddleware() Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			res, err := next.Do(req)
+			if err != nil {
+				logger.LogInfo("request [%s %s] failed after [%s] with [%s]", req.Method, req.URL.Path, time.Since(start), err)
+				return nil, err
+			}
+			logger.LogInfo("request [%s %s] completed with [%d] after [%s]", req.Method, req.URL.Path, res.StatusCode, time.Since(start))
+			return res, nil
+		})
+	}
+}
+
+// FaultInjectionMiddleware - fails the given fraction of requests (between 0 and 1) without sending them, to test how
+// the application handles failures of Descope. Failed requests are handled by fault, or get a 503 response when nil.
+func FaultInjectionMiddleware(probability float64, fault DoerFunc) Middleware {
+	if fault == nil {
+		fault = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Status:     http.StatusText(http.StatusServiceUnavailable),
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{},
+				Body:       http.NoBody,
+				Request:    req,
+			}, nil
+		}
+	}
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if rand.Float64() < probability {
+				return fault(req)
+			}
+			return next.Do(req)
+		})
+	}
+}
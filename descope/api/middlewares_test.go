@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/logger"
+	"github.com/descope/go-sdk/descope/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Print(v ...interface{}) {
+	for _, line := range v {
+		l.lines = append(l.lines, line.(string))
+	}
+}
+
+func TestMiddlewaresOrder(t *testing.T) {
+	order := []string{}
+	named := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				// middlewares see the headers added by the client
+				assert.NotEmpty(t, req.Header.Get(AuthorizationHeaderName))
+				return next.Do(req)
+			})
+		}
+	}
+	c := NewClient(ClientParams{ProjectID: "test", Middlewares: []Middleware{named("first"), named("second")}, DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+		order = append(order, "client")
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})})
+	_, err := c.DoGetRequest("path", nil, "")
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{"first", "second", "client"}, order)
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	c := NewClient(ClientParams{ProjectID: "test", Middlewares: []Middleware{func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.UnauthorizedError
+		})
+	}}, DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+		require.Fail(t, "request should not be sent")
+		return nil, nil
+	})})
+	_, err := c.DoGetRequest("path", nil, "")
+	assert.ErrorIs(t, err, errors.UnauthorizedError)
+}
+
+func TestHeadersMiddleware(t *testing.T) {
+	c := NewClient(ClientParams{ProjectID: "test", Middlewares: []Middleware{HeadersMiddleware(map[string]string{"traceparent": "00-abc-def-01"})}, DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+		assert.EqualValues(t, "00-abc-def-01", r.Header.Get("traceparent"))
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})})
+	_, err := c.DoGetRequest("path", nil, "")
+	require.NoError(t, err)
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	l := &testLogger{}
+	logger.Init(logger.LogInfoLevel, l)
+	defer logger.Init(logger.LogNone, nil)
+
+	fail := false
+	c := NewClient(ClientParams{ProjectID: "test", Middlewares: []Middleware{LoggingMiddleware()}, DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+		if fail {
+			return nil, errors.NoPublicKeyError
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})})
+	_, err := c.DoGetRequest("path", nil, "")
+	require.NoError(t, err)
+	fail = true
+	_, err = c.DoGetRequest("path", nil, "")
+	require.Error(t, err)
+
+	logged := strings.Join(l.lines, "\n")
+	assert.Contains(t, logged, "request [GET /path] completed with [200]")
+	assert.Contains(t, logged, "request [GET /path] failed")
+	assert.NotContains(t, logged, "Bearer")
+}
+
+func TestFaultInjectionMiddleware(t *testing.T) {
+	sent := 0
+	client := mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	c := NewClient(ClientParams{ProjectID: "test", Middlewares: []Middleware{FaultInjectionMiddleware(1, nil)}, DefaultClient: client})
+	_, err := c.DoGetRequest("path", nil, "")
+	assert.Error(t, err)
+	assert.Zero(t, sent)
+
+	c = NewClient(ClientParams{ProjectID: "test", Middlewares: []Middleware{FaultInjectionMiddleware(1, func(req *http.Request) (*http.Response, error) {
+		return nil, errors.NoPublicKeyError
+	})}, DefaultClient: client})
+	_, err = c.DoGetRequest("path", nil, "")
+	assert.ErrorIs(t, err, errors.NoPublicKeyError)
+
+	c = NewClient(ClientParams{ProjectID: "test", Middlewares: []Middleware{FaultInjectionMiddleware(0, nil)}, DefaultClient: client})
+	_, err = c.DoGetRequest("path", nil, "")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, sent)
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// RequestEvent - describes a request sent to descope services
+type RequestEvent struct {
+	// Context - the context the request was sent with
+	Context context.Context
+	// ProjectID - the project of the client that sent the request
+	ProjectID string
+	// Method - the http method of the request
+	Method string
+	// Route - the path of the request, without the project ID in routes that end with it, such as /v1/keys
+	Route string
+	// Start - when sending the request started, including waiting for the rate limits
+	Start time.Time
+	// Duration - how long the request took, until its response was read
+	Duration time.Duration
+	// StatusCode - the status code of the response, 0 when no response was received
+	StatusCode int
+	// ErrorCode - the descope error code of a failed request, if any
+	ErrorCode string
+	// Err - the error returned for the request, if any
+	Err error
+}
+
+// RequestObserver - observes the requests sent by a client, such as to trace or measure them. Unlike middlewares,
+// observers are also given the outcome of parsing the response. Observers are called synchronously and must not block.
+type RequestObserver interface {
+	ObserveRequest(event RequestEvent)
+}
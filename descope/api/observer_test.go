@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testContextKey struct{}
+
+type testObserver struct {
+	events []RequestEvent
+}
+
+func (o *testObserver) ObserveRequest(event RequestEvent) {
+	o.events = append(o.events, event)
+}
+
+func TestObserveRequest(t *testing.T) {
+	observer := &testObserver{}
+	c := NewClient(ClientParams{ProjectID: "P1", Observer: observer, DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case Routes.SignInOTP():
+			return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader(`{"errorCode":"E011002"}`))}, nil
+		case Routes.Logout():
+			return nil, errors.NoPublicKeyError
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})})
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "value")
+	_, err := c.WithContext(ctx).DoGetRequest(path.Join(Routes.GetKeys(), "P1"), nil, "")
+	require.NoError(t, err)
+	require.Len(t, observer.events, 1)
+	event := observer.events[0]
+	assert.EqualValues(t, "P1", event.ProjectID)
+	assert.EqualValues(t, http.MethodGet, event.Method)
+	assert.EqualValues(t, Routes.GetKeys(), event.Route)
+	assert.EqualValues(t, http.StatusOK, event.StatusCode)
+	assert.EqualValues(t, "value", event.Context.Value(testContextKey{}))
+	assert.False(t, event.Start.IsZero())
+	assert.NoError(t, event.Err)
+
+	_, err = c.DoPostRequest(Routes.SignInOTP(), nil, nil, "")
+	require.Error(t, err)
+	event = observer.events[1]
+	assert.EqualValues(t, Routes.SignInOTP(), event.Route)
+	assert.EqualValues(t, http.StatusBadRequest, event.StatusCode)
+	assert.EqualValues(t, "E011002", event.ErrorCode)
+	assert.Equal(t, err, event.Err)
+
+	_, err = c.DoPostRequest(Routes.Logout(), nil, nil, "")
+	require.Error(t, err)
+	event = observer.events[2]
+	assert.Zero(t, event.StatusCode)
+	assert.Empty(t, event.ErrorCode)
+	assert.ErrorIs(t, event.Err, errors.NoPublicKeyError)
+}
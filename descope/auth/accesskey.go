@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/logger"
+)
+
+const defaultAccessKeyRefreshBefore = time.Minute
+
+// TokenSource - provides a valid session token for outbound requests.
+type TokenSource interface {
+	// Token - returns a session token that is valid for at least a short while, or an error upon failure.
+	Token() (*Token, error)
+}
+
+// ContextTokenSource - a TokenSource that can be given the context of the request it provides a token for, so that
+// obtaining the token is canceled with the request.
+type ContextTokenSource interface {
+	TokenSource
+	// TokenWithContext - returns a session token like Token, obtaining it with the given context.
+	TokenWithContext(ctx context.Context) (*Token, error)
+}
+
+// SourceToken - returns a session token from the given source, with the given context when the source is a
+// ContextTokenSource.
+func SourceToken(ctx context.Context, source TokenSource) (*Token, error) {
+	if s, ok := source.(ContextTokenSource); ok && ctx != nil {
+		return s.TokenWithContext(ctx)
+	}
+	return source.Token()
+}
+
+// AccessKeyTokenSource - a TokenSource that exchanges an access key for a session token using
+// ExchangeAccessKey, caches it, and exchanges it again shortly before the session token expires.
+// It is safe for concurrent use, concurrent callers share a single exchange.
+type AccessKeyTokenSource struct {
+	auth          Authentication
+	accessKey     string
+	refreshBefore time.Duration
+	now           func() time.Time
+
+	mutex sync.Mutex
+	token *Token
+}
+
+// NewAccessKeyTokenSource - creates a new AccessKeyTokenSource for the given access key.
+// refreshBefore (optional, 1 minute) - how long before the session token expiration it should be exchanged again.
+func NewAccessKeyTokenSource(auth Authentication, accessKey string, refreshBefore time.Duration) *AccessKeyTokenSource {
+	if refreshBefore <= 0 {
+		refreshBefore = defaultAccessKeyRefreshBefore
+	}
+	return &AccessKeyTokenSource{auth: auth, accessKey: accessKey, refreshBefore: refreshBefore, now: time.Now}
+}
+
+func (s *AccessKeyTokenSource) Token() (*Token, error) {
+	return s.exchange(s.auth)
+}
+
+// TokenWithContext - returns a session token like Token, exchanging the access key with the given context when the
+// authentication service supports it (see DescopeClient.AuthWithContext).
+func (s *AccessKeyTokenSource) TokenWithContext(ctx context.Context) (*Token, error) {
+	exchanger := s.auth
+	if a, ok := s.auth.(interface {
+		WithContext(ctx context.Context) Authentication
+	}); ok {
+		exchanger = a.WithContext(ctx)
+	}
+	return s.exchange(exchanger)
+}
+
+func (s *AccessKeyTokenSource) exchange(exchanger Authentication) (*Token, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.token != nil && s.now().Add(s.refreshBefore).Before(time.Unix(s.token.Expiration, 0)) {
+		return s.token, nil
+	}
+
+	logger.LogDebug("exchanging access key for a new session token")
+	ok, token, err := exchanger.ExchangeAccessKey(s.accessKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || token == nil {
+		return nil, errors.InvalidAccessKeyResponse
+	}
+	s.token = token
+	return token, nil
+}
+
+// Invalidate - drops the cached session token, so the next call to Token exchanges the access key again.
+func (s *AccessKeyTokenSource) Invalidate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.token = nil
+}
+
+// TokenRoundTripper - an http.RoundTripper that stamps outgoing requests with a bearer
+// session token provided by Source.
+type TokenRoundTripper struct {
+	// Source (required) - provides the session token for every request.
+	Source TokenSource
+	// Base (optional, http.DefaultTransport) - the RoundTripper used to send the stamped requests.
+	Base http.RoundTripper
+}
+
+// NewTokenRoundTripper - creates a TokenRoundTripper, wrap it in an http.Client to use it:
+//
+//	client := &http.Client{Transport: auth.NewTokenRoundTripper(source, nil)}
+func NewTokenRoundTripper(source TokenSource, base http.RoundTripper) *TokenRoundTripper {
+	return &TokenRoundTripper{Source: source, Base: base}
+}
+
+func (rt *TokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := SourceToken(req.Context(), rt.Source)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	// a RoundTripper must not modify the given request
+	req = req.Clone(req.Context())
+	req.Header.Set(api.AuthorizationHeaderName, api.BearerAuthorizationPrefix+token.JWT)
+
+	base := rt.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
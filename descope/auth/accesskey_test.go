@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accessKeyExchanger - an authentication service that returns the given results from ExchangeAccessKey
+type accessKeyExchanger struct {
+	Authentication
+	ok    bool
+	token *Token
+	err   error
+}
+
+func (e accessKeyExchanger) ExchangeAccessKey(string) (bool, *Token, error) {
+	return e.ok, e.token, e.err
+}
+
+func TestAccessKeyTokenSourceCachesToken(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		calls++
+		assert.EqualValues(t, api.Routes.ExchangeAccessKey(), r.URL.Path)
+	}))
+	require.NoError(t, err)
+
+	source := NewAccessKeyTokenSource(a, "foo", 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := source.Token()
+			assert.NoError(t, err)
+			assert.EqualValues(t, jwtTokenValid, token.JWT)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, calls)
+
+	source.Invalidate()
+	_, err = source.Token()
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, calls)
+}
+
+func TestAccessKeyTokenSourceRefreshesBeforeExpiration(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		calls++
+	}))
+	require.NoError(t, err)
+
+	source := NewAccessKeyTokenSource(a, "foo", time.Hour)
+	token, err := source.Token()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, calls)
+
+	source.now = func() time.Time { return time.Unix(token.Expiration, 0).Add(-2 * time.Hour) }
+	_, err = source.Token()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, calls)
+
+	source.now = func() time.Time { return time.Unix(token.Expiration, 0).Add(-30 * time.Minute) }
+	_, err = source.Token()
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, calls)
+}
+
+func TestAccessKeyTokenSourceFailure(t *testing.T) {
+	a, err := newTestAuth(nil, DoBadRequest(nil))
+	require.NoError(t, err)
+	source := NewAccessKeyTokenSource(a, "foo", 0)
+	token, err := source.Token()
+	require.ErrorIs(t, err, errors.UnauthorizedError)
+	require.Nil(t, token)
+
+	source = NewAccessKeyTokenSource(accessKeyExchanger{}, "foo", 0)
+	token, err = source.Token()
+	require.ErrorIs(t, err, errors.InvalidAccessKeyResponse)
+	require.Nil(t, token)
+}
+
+func TestAccessKeyTokenSourceWithContext(t *testing.T) {
+	type contextKey struct{}
+	a, err := newTestAuth(nil, func(r *http.Request) (*http.Response, error) {
+		if err := r.Context().Err(); err != nil {
+			return nil, err
+		}
+		assert.EqualValues(t, "exchange", r.Context().Value(contextKey{}))
+		return DoOk(nil)(r)
+	})
+	require.NoError(t, err)
+	source := NewAccessKeyTokenSource(a, "foo", 0)
+	token, err := SourceToken(context.WithValue(context.Background(), contextKey{}, "exchange"), source)
+	require.NoError(t, err)
+	assert.NotNil(t, token)
+
+	// a canceled context cancels the exchange
+	source = NewAccessKeyTokenSource(a, "foo", 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	token, err = source.TokenWithContext(ctx)
+	assert.Error(t, err)
+	assert.Nil(t, token)
+}
+
+func TestTokenRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, api.BearerAuthorizationPrefix+"test", r.Header.Get(api.AuthorizationHeaderName))
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	source := NewAccessKeyTokenSource(accessKeyExchanger{ok: true, token: &Token{JWT: "test", Expiration: time.Now().Add(time.Hour).Unix()}}, "foo", 0)
+	client := &http.Client{Transport: NewTokenRoundTripper(source, nil)}
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.EqualValues(t, http.StatusTeapot, res.StatusCode)
+	assert.Empty(t, req.Header.Get(api.AuthorizationHeaderName))
+
+	source = NewAccessKeyTokenSource(accessKeyExchanger{ok: true, err: errors.UnauthorizedError}, "foo", 0)
+	client = &http.Client{Transport: NewTokenRoundTripper(source, nil)}
+	_, err = client.Get(server.URL)
+	require.ErrorIs(t, err, errors.UnauthorizedError)
+}
@@ -17,44 +17,90 @@ import (
 )
 
 type AuthParams struct {
-	ProjectID string
-	PublicKey string
+	ProjectID       string
+	PublicKey       string
+	PublicKeys      *PublicKeySet
+	FetchPublicKeys bool
+	DefaultRegion   string
+	Observer        ValidationObserver
+	TenantResolver  SSOTenantResolver
 }
 
 type authenticationsBase struct {
 	client             *api.Client
 	conf               *AuthParams
 	publicKeysProvider *provider
+	identifiers        *IdentifierResolver
+	withContext        bool // the client was given a context with WithContext
+}
+
+// clientFor returns the client that sends the requests made on behalf of the given request, with the context of the
+// request unless the service was given a context with WithContext
+func (auth *authenticationsBase) clientFor(r *http.Request) *api.Client {
+	if r == nil || auth.withContext {
+		return auth.client
+	}
+	return auth.client.WithContext(r.Context())
 }
 
 type authenticationService struct {
 	authenticationsBase
 
-	otp       OTP
-	magicLink MagicLink
-	totp      TOTP
-	webAuthn  WebAuthn
-	oauth     OAuth
-	saml      SAML
+	otp           OTP
+	magicLink     MagicLink
+	enchantedLink EnchantedLink
+	password      Password
+	totp          TOTP
+	webAuthn      WebAuthn
+	oauth         OAuth
+	saml          SAML
 }
 
 func NewAuth(conf AuthParams, c *api.Client) (*authenticationService, error) {
-	base := authenticationsBase{conf: &conf, client: c}
+	identifiers, err := NewIdentifierResolver(conf.DefaultRegion)
+	if err != nil {
+		return nil, err
+	}
+	base := authenticationsBase{conf: &conf, client: c, identifiers: identifiers}
 	base.publicKeysProvider = newProvider(c, base.conf)
+	return newAuthenticationService(base), nil
+}
+
+func newAuthenticationService(base authenticationsBase) *authenticationService {
 	authenticationService := &authenticationService{authenticationsBase: base}
 	authenticationService.otp = &otp{authenticationsBase: base}
 	authenticationService.magicLink = &magicLink{authenticationsBase: base}
+	authenticationService.enchantedLink = &enchantedLink{authenticationsBase: base}
+	authenticationService.password = &password{authenticationsBase: base}
 	authenticationService.oauth = &oauth{authenticationsBase: base}
 	authenticationService.saml = &saml{authenticationsBase: base}
 	authenticationService.webAuthn = &webAuthn{authenticationsBase: base}
 	authenticationService.totp = &totp{authenticationsBase: base}
-	return authenticationService, nil
+	return authenticationService
+}
+
+// WithContext - returns the authentication service that sends its requests with the given context, instead of the
+// context of the request given to the functions that take one. The public keys and the rate limits are shared with
+// this service.
+func (auth *authenticationService) WithContext(ctx context.Context) Authentication {
+	base := auth.authenticationsBase
+	base.client = auth.client.WithContext(ctx)
+	base.withContext = true
+	return newAuthenticationService(base)
 }
 
 func (auth *authenticationService) MagicLink() MagicLink {
 	return auth.magicLink
 }
 
+func (auth *authenticationService) EnchantedLink() EnchantedLink {
+	return auth.enchantedLink
+}
+
+func (auth *authenticationService) Password() Password {
+	return auth.password
+}
+
 func (auth *authenticationService) OTP() OTP {
 	return auth.otp
 }
@@ -92,7 +138,7 @@ func (auth *authenticationService) Logout(request *http.Request, w http.Response
 		return errors.RefreshTokenError
 	}
 
-	httpResponse, err := auth.client.DoPostRequest(api.Routes.Logout(), nil, &api.HTTPRequest{}, refreshToken)
+	httpResponse, err := auth.clientFor(request).DoPostRequest(api.Routes.Logout(), nil, &api.HTTPRequest{}, refreshToken)
 	if err != nil {
 		return err
 	}
@@ -145,7 +191,7 @@ func (auth *authenticationService) LogoutAll(request *http.Request, w http.Respo
 		return errors.RefreshTokenError
 	}
 
-	httpResponse, err := auth.client.DoPostRequest(api.Routes.LogoutAll(), nil, &api.HTTPRequest{}, refreshToken)
+	httpResponse, err := auth.clientFor(request).DoPostRequest(api.Routes.LogoutAll(), nil, &api.HTTPRequest{}, refreshToken)
 	if err != nil {
 		return err
 	}
@@ -198,7 +244,7 @@ func (auth *authenticationService) Me(request *http.Request) (*UserResponse, err
 		return nil, errors.RefreshTokenError
 	}
 
-	httpResponse, err := auth.client.DoGetRequest(api.Routes.Me(), &api.HTTPRequest{}, refreshToken)
+	httpResponse, err := auth.clientFor(request).DoGetRequest(api.Routes.Me(), &api.HTTPRequest{}, refreshToken)
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +262,7 @@ func (auth *authenticationService) ValidateSession(request *http.Request, w http
 		logger.LogDebug("unable to find token from cookies")
 		return false, nil, nil
 	}
-	return auth.validateSession(sessionToken, refreshToken, false, w)
+	return auth.validateSessionWithContext(request.Context(), sessionToken, refreshToken, false, w)
 }
 
 func (auth *authenticationService) ValidateSessionTokens(sessionToken, refreshToken string) (bool, *Token, error) {
@@ -235,7 +281,7 @@ func (auth *authenticationService) RefreshSession(request *http.Request, w http.
 		return false, nil, nil
 	}
 
-	return auth.validateSession(sessionToken, refreshToken, true, w)
+	return auth.validateSessionWithContext(request.Context(), sessionToken, refreshToken, true, w)
 }
 
 func (auth *authenticationService) ExchangeAccessKey(accessKey string) (success bool, SessionToken *Token, err error) {
@@ -286,19 +332,24 @@ func (auth *authenticationService) ValidateTenantRoles(token *Token, tenant stri
 	return true
 }
 
+// SessionValidator - validates the session of a request, such as an Authentication or a descope.Projects registry
+type SessionValidator interface {
+	ValidateSession(request *http.Request, w http.ResponseWriter) (bool, *Token, error)
+}
+
 // AuthenticationMiddleware - middleware used to validate session and invoke if provided a failure and
 // success callbacks after calling ValidateSession().
 // onFailure will be called when the authentication failed, if empty, will write unauthorized (401) on the response writer.
-// onSuccess will be called when the authentication succeeded, if empty, it will generate a new context with the descope user id associated with the given token and runs next.
-func AuthenticationMiddleware(auth Authentication, onFailure func(http.ResponseWriter, *http.Request, error), onSuccess func(http.ResponseWriter, *http.Request, http.Handler, *Token)) func(next http.Handler) http.Handler {
+// onSuccess will be called when the authentication succeeded, if empty, it will generate a new context with the validated token
+// and the descope user id associated with it (see TokenFromContext) and runs next.
+func AuthenticationMiddleware(auth SessionValidator, onFailure func(http.ResponseWriter, *http.Request, error), onSuccess func(http.ResponseWriter, *http.Request, http.Handler, *Token)) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if ok, token, err := auth.ValidateSession(r, w); ok {
 				if onSuccess != nil {
 					onSuccess(w, r, next, token)
 				} else {
-					newCtx := context.WithValue(r.Context(), ContextUserIDPropertyKey, token.ID)
-					r = r.WithContext(newCtx)
+					r = r.WithContext(ContextWithToken(r.Context(), token))
 					next.ServeHTTP(w, r)
 				}
 			} else {
@@ -315,18 +366,57 @@ func AuthenticationMiddleware(auth Authentication, onFailure func(http.ResponseW
 	}
 }
 
+// ContextWithToken - returns a copy of ctx that carries the given validated token, as well as its user id
+// under ContextUserIDPropertyKey for backwards compatibility.
+func ContextWithToken(ctx context.Context, token *Token) context.Context {
+	if token == nil {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, ContextUserIDPropertyKey, token.ID)
+	return context.WithValue(ctx, ContextTokenPropertyKey, token)
+}
+
+// TokenFromContext - returns the validated token stored in ctx by AuthenticationMiddleware (or ContextWithToken).
+// returns false if the context does not carry a token.
+func TokenFromContext(ctx context.Context) (*Token, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	token, ok := ctx.Value(ContextTokenPropertyKey).(*Token)
+	return token, ok && token != nil
+}
+
 func (auth *authenticationService) validateSession(sessionToken string, refreshToken string, forceRefresh bool, w http.ResponseWriter) (bool, *Token, error) {
+	return auth.validateSessionWithContext(context.Background(), sessionToken, refreshToken, forceRefresh, w)
+}
+
+// validateSessionWithContext validates the session and reports the validation to the observer, the session is refreshed
+// with the given context
+func (auth *authenticationService) validateSessionWithContext(ctx context.Context, sessionToken string, refreshToken string, forceRefresh bool, w http.ResponseWriter) (bool, *Token, error) {
+	event := ValidationEvent{Context: ctx, ProjectID: auth.conf.ProjectID, Start: time.Now()}
+	ok, token, err := auth.checkSession(ctx, sessionToken, refreshToken, forceRefresh, w, &event)
+	if observer := auth.conf.Observer; observer != nil {
+		event.Duration = time.Since(event.Start)
+		event.Err = err
+		observer.ObserveValidation(event)
+	}
+	return ok, token, err
+}
+
+func (auth *authenticationService) checkSession(ctx context.Context, sessionToken string, refreshToken string, forceRefresh bool, w http.ResponseWriter, event *ValidationEvent) (bool, *Token, error) {
 	// Make sure to try and validate either JWT because in the process we make sure we have the public keys
 	var token, tToken *Token
 	var err, tErr error
+	outcome, tOutcome := ValidationInvalid, ValidationInvalid
 	if sessionToken != "" {
-		token, err = auth.validateJWT(sessionToken)
+		token, outcome, err = auth.validateJWTWithOutcome(sessionToken, event)
 	}
 	if refreshToken != "" {
-		tToken, tErr = auth.validateJWT(refreshToken)
+		tToken, tOutcome, tErr = auth.validateJWTWithOutcome(refreshToken, event)
 	}
 	if !auth.publicKeysProvider.publicKeyExists() {
 		logger.LogError("Cannot validate session, no public key available", err)
+		event.Outcome = ValidationMissingKey
 		return false, nil, errors.NewNoPublicKeyError()
 	}
 	if err == nil && sessionToken != "" && refreshToken != "" {
@@ -339,25 +429,32 @@ func (auth *authenticationService) validateSession(sessionToken string, refreshT
 	if sessionToken == "" || err != nil || forceRefresh {
 		// check refresh token
 		if refreshToken == "" {
+			event.Outcome = outcome
 			return false, nil, err
 		}
 		if ok, err := validateTokenError(tErr); !ok {
+			event.Outcome = tOutcome
 			return false, nil, err
 		}
 		// auto-refresh session token
-		httpResponse, err := auth.client.DoPostRequest(api.Routes.RefreshToken(), nil, &api.HTTPRequest{}, refreshToken)
+		event.RefreshTriggered = true
+		httpResponse, err := auth.client.WithContext(ctx).DoPostRequest(api.Routes.RefreshToken(), nil, &api.HTTPRequest{}, refreshToken)
 		if err != nil {
+			event.Outcome = ValidationRefreshFailed
 			return false, nil, errors.FailedToRefreshTokenError
 		}
 		info, err := auth.generateAuthenticationInfo(httpResponse, w)
 		if err != nil {
+			event.Outcome = ValidationRefreshFailed
 			return false, nil, err
 		}
 		// No need to check for error again because validateTokenError will return false for any non-nil error
 		info.SessionToken.RefreshExpiration = tToken.Expiration
+		event.Outcome = ValidationRefreshed
 		return true, info.SessionToken, nil
 	}
 
+	event.Outcome = ValidationValid
 	return true, token, nil
 }
 
@@ -430,61 +527,109 @@ func (auth *authenticationsBase) extractTokens(jRes *JWTResponse) ([]*Token, err
 }
 
 func (auth *authenticationsBase) validateJWT(JWT string) (*Token, error) {
-	token, err := jwt.Parse([]byte(JWT), jwt.WithKeyProvider(auth.publicKeysProvider), jwt.WithVerify(true), jwt.WithValidate(true))
+	token, _, err := auth.validateJWTWithOutcome(JWT, nil)
+	return token, err
+}
+
+// validateJWTWithOutcome validates the given JWT and returns the outcome of the validation, whether the public keys were
+// fetched is recorded in the given event when not nil
+func (auth *authenticationsBase) validateJWTWithOutcome(JWT string, event *ValidationEvent) (*Token, ValidationOutcome, error) {
+	lookup := &keyLookup{provider: auth.publicKeysProvider}
+	token, err := jwt.Parse([]byte(JWT), jwt.WithKeyProvider(lookup), jwt.WithVerify(true), jwt.WithValidate(true))
+	outcome := validationOutcome(JWT, err, lookup)
 	if err != nil {
 		var parseErr error
-		token, parseErr = jwt.Parse([]byte(JWT), jwt.WithKeyProvider(auth.publicKeysProvider), jwt.WithVerify(false), jwt.WithValidate(false))
+		token, parseErr = jwt.Parse([]byte(JWT), jwt.WithKeyProvider(lookup), jwt.WithVerify(false), jwt.WithValidate(false))
 		if parseErr != nil {
 			err = parseErr
 		}
 	}
-	return NewToken(JWT, token), err
+	if event != nil && lookup.fetched {
+		event.KeysFetched = true
+	}
+	return NewToken(JWT, token), outcome, err
 }
 
-func (*authenticationsBase) verifyDeliveryMethod(method DeliveryMethod, identifier string, user *User) *errors.WebError {
+var (
+	// otpDeliveryMethods - the delivery methods an OTP code can be sent with, the embedded code is returned by the
+	// OTP functions with the Embedded suffix instead
+	otpDeliveryMethods = []DeliveryMethod{MethodEmail, MethodSMS, MethodWhatsApp, MethodVoice}
+	// otpVerifyDeliveryMethods - the delivery methods an OTP code can be verified with
+	otpVerifyDeliveryMethods = []DeliveryMethod{MethodEmail, MethodSMS, MethodWhatsApp, MethodVoice, MethodEmbedded}
+	// magicLinkDeliveryMethods - the delivery methods a magic link can be sent with
+	magicLinkDeliveryMethods = []DeliveryMethod{MethodEmail, MethodSMS, MethodWhatsApp}
+)
+
+func verifySupportedDeliveryMethod(method DeliveryMethod, supported []DeliveryMethod) *errors.WebError {
+	if !slices.Contains(supported, method) {
+		return errors.NewInvalidArgumentError("method")
+	}
+	return nil
+}
+
+// verifyDeliveryMethod validates the user contact detail the given method delivers to, which is taken from the identifier
+// when not set in the user, and returns a copy of the user with it normalized. Methods that do not deliver to the user
+// only require an identifier.
+func (auth *authenticationsBase) verifyDeliveryMethod(method DeliveryMethod, identifier string, user *User) (*User, error) {
 	varName := "identifier"
 	if identifier == "" {
-		return errors.NewInvalidArgumentError(varName)
+		return nil, errors.NewInvalidArgumentError(varName)
+	}
+	verified := &User{}
+	if user != nil {
+		*verified = *user
 	}
 
 	switch method {
 	case MethodEmail:
-		if len(user.Email) == 0 {
-			user.Email = identifier
+		if len(verified.Email) == 0 {
+			verified.Email = identifier
 		} else {
 			varName = "user.Email"
 		}
-		if !emailRegex.MatchString(user.Email) {
-			return errors.NewInvalidArgumentError(varName)
-		}
-	case MethodSMS:
-		if len(user.Phone) == 0 {
-			user.Phone = identifier
-		} else {
-			varName = "user.Phone"
-		}
-		if !phoneRegex.MatchString(user.Phone) {
-			return errors.NewInvalidArgumentError(varName)
+		email, err := auth.identifiers.NormalizeEmail(verified.Email)
+		if err != nil {
+			return nil, errors.NewInvalidArgumentError(varName)
 		}
-	case MethodWhatsApp:
-		if len(user.Phone) == 0 {
-			user.Phone = identifier
+		verified.Email = email
+	case MethodSMS, MethodWhatsApp, MethodVoice:
+		if len(verified.Phone) == 0 {
+			verified.Phone = identifier
 		} else {
 			varName = "user.Phone"
 		}
-		if !phoneRegex.MatchString(user.Phone) {
-			return errors.NewInvalidArgumentError(varName)
+		phone, err := auth.identifiers.NormalizePhone(verified.Phone)
+		if err != nil {
+			return nil, errors.NewInvalidArgumentError(varName)
 		}
+		verified.Phone = phone
 	}
-	return nil
+	return verified, nil
 }
 
 func (auth *authenticationsBase) exchangeToken(code string, url string, w http.ResponseWriter) (*AuthenticationInfo, error) {
+	return auth.exchangeTokenWithVerifier(code, "", url, w)
+}
+
+func (auth *authenticationsBase) exchangeTokenFromRequest(r *http.Request, url string, w http.ResponseWriter) (*AuthenticationInfo, error) {
+	if r == nil || r.URL == nil {
+		return nil, errors.MissingRequestError
+	}
+	codeVerifier, err := verifyRedirectState(r, w)
+	if err != nil {
+		return nil, err
+	}
+	base := *auth
+	base.client = auth.clientFor(r)
+	return base.exchangeTokenWithVerifier(r.URL.Query().Get(redirectCodeQueryParam), codeVerifier, url, w)
+}
+
+func (auth *authenticationsBase) exchangeTokenWithVerifier(code, codeVerifier string, url string, w http.ResponseWriter) (*AuthenticationInfo, error) {
 	if code == "" {
 		return nil, errors.NewInvalidArgumentError("code")
 	}
 
-	httpResponse, err := auth.client.DoPostRequest(url, newExchangeTokenBody(code), nil, "")
+	httpResponse, err := auth.client.DoPostRequest(url, newExchangeTokenBody(code, codeVerifier), nil, "")
 	if err != nil {
 		return nil, err
 	}
@@ -544,6 +689,12 @@ func createCookie(token *Token, jwtRes *JWTResponse) *http.Cookie {
 	return nil
 }
 
+// RequestTokens - returns the session token of the given request, from the Authorization header or the session cookie,
+// and the refresh token from the refresh cookie, the same way ValidateSession finds them.
+func RequestTokens(r *http.Request) (sessionToken string, refreshToken string) {
+	return provideTokens(r)
+}
+
 func provideTokens(r *http.Request) (string, string) {
 	if r == nil {
 		return "", ""
@@ -620,6 +771,15 @@ func getAuthorizationClaimItems(token *Token, tenant string, claim string) []str
 	return items
 }
 
+func getEnchantedLinkResponse(httpResponse *api.HTTPResponse) (*EnchantedLinkResponse, error) {
+	var response *EnchantedLinkResponse
+	if err := utils.Unmarshal([]byte(httpResponse.BodyStr), &response); err != nil {
+		logger.LogError("failed to load enchanted link from response", err)
+		return response, errors.InvalidPendingRefError
+	}
+	return response, nil
+}
+
 func getPendingRefFromResponse(httpResponse *api.HTTPResponse) (*MagicLinkResponse, error) {
 	var response *MagicLinkResponse
 	if err := utils.Unmarshal([]byte(httpResponse.BodyStr), &response); err != nil {
@@ -677,6 +837,54 @@ func composeVerifyMagicLinkURL() string {
 	return api.Routes.VerifyMagicLink()
 }
 
+func composeEnchantedLinkSignInURL() string {
+	return composeURLMethod(api.Routes.SignInEnchantedLink(), MethodEmail)
+}
+
+func composeEnchantedLinkSignUpURL() string {
+	return composeURLMethod(api.Routes.SignUpEnchantedLink(), MethodEmail)
+}
+
+func composeEnchantedLinkSignUpOrInURL() string {
+	return composeURLMethod(api.Routes.SignUpOrInEnchantedLink(), MethodEmail)
+}
+
+func composeVerifyEnchantedLinkURL() string {
+	return api.Routes.VerifyEnchantedLink()
+}
+
+func composeGetEnchantedLinkSession() string {
+	return api.Routes.GetEnchantedLinkSession()
+}
+
+func composeUpdateUserEmailEnchantedLink() string {
+	return api.Routes.UpdateUserEmailEnchantedLink()
+}
+
+func composeSignUpPasswordURL() string {
+	return api.Routes.SignUpPassword()
+}
+
+func composeSignInPasswordURL() string {
+	return api.Routes.SignInPassword()
+}
+
+func composeSendPasswordResetURL() string {
+	return api.Routes.SendPasswordReset()
+}
+
+func composeUpdateUserPasswordURL() string {
+	return api.Routes.UpdateUserPassword()
+}
+
+func composeReplaceUserPasswordURL() string {
+	return api.Routes.ReplaceUserPassword()
+}
+
+func composePasswordPolicyURL() string {
+	return api.Routes.PasswordPolicy()
+}
+
 func composeOAuthURL() string {
 	return api.Routes.OAuthStart()
 }
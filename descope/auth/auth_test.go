@@ -2,6 +2,7 @@ package auth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -137,29 +138,37 @@ func newTestAuthConf(authParams *AuthParams, clientParams *api.ClientParams, cal
 func TestVerifyDeliveryMethod(t *testing.T) {
 	a, err := newTestAuth(nil, nil)
 	require.NoError(t, err)
-	err = a.verifyDeliveryMethod(MethodEmail, "", &User{})
+	_, err = a.verifyDeliveryMethod(MethodEmail, "", &User{})
 	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
 
-	err = a.verifyDeliveryMethod(MethodSMS, "abc@notaphone.com", &User{})
+	_, err = a.verifyDeliveryMethod(MethodSMS, "abc@notaphone.com", &User{})
 	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
 
 	u := &User{}
-	err = a.verifyDeliveryMethod(MethodEmail, "abc@notaphone.com", u)
+	verified, err := a.verifyDeliveryMethod(MethodEmail, "abc@notaphone.com", u)
 	assert.Nil(t, err)
-	assert.NotEmpty(t, u.Email)
+	assert.NotEmpty(t, verified.Email)
+	assert.Empty(t, u.Email)
 
-	u = &User{Email: "abc@notaphone.com"}
-	err = a.verifyDeliveryMethod(MethodEmail, "my username", u)
+	u = &User{Email: "abc@NotAPhone.com"}
+	verified, err = a.verifyDeliveryMethod(MethodEmail, "my username", u)
 	assert.Nil(t, err)
+	assert.EqualValues(t, "abc@notaphone.com", verified.Email)
+	assert.EqualValues(t, "abc@NotAPhone.com", u.Email)
 
 	u = &User{}
-	err = a.verifyDeliveryMethod(MethodSMS, "+19999999999", u)
+	verified, err = a.verifyDeliveryMethod(MethodSMS, "+19999999999", u)
 	assert.Nil(t, err)
-	assert.NotEmpty(t, u.Phone)
+	assert.NotEmpty(t, verified.Phone)
+	assert.Empty(t, u.Phone)
 
 	u = &User{Phone: "+19999999999"}
-	err = a.verifyDeliveryMethod(MethodSMS, "my username", u)
+	_, err = a.verifyDeliveryMethod(MethodSMS, "my username", u)
 	assert.Nil(t, err)
+
+	verified, err = a.verifyDeliveryMethod(MethodSMS, "+19999999999", nil)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, verified.Phone)
 }
 
 func TestAuthDefaultURL(t *testing.T) {
@@ -172,6 +181,26 @@ func TestAuthDefaultURL(t *testing.T) {
 	require.NoError(t, err)
 }
 
+type testContextKey struct{}
+
+func TestAuthWithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testContextKey{}, "value")
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, "value", r.Context().Value(testContextKey{}))
+	}))
+	require.NoError(t, err)
+	withContext := a.WithContext(ctx)
+	_, err = withContext.OTP().VerifyCode(MethodEmail, "dude@example.com", "123456", nil)
+	require.NoError(t, err)
+	assert.Same(t, a.publicKeysProvider, withContext.(*authenticationService).publicKeysProvider)
+
+	// the context of a given request is used otherwise
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	r.AddCookie(&http.Cookie{Name: RefreshCookieName, Value: jwtTokenValid})
+	err = a.OTP().SignIn(MethodEmail, "dude@example.com", r, &LoginOptions{Stepup: true})
+	require.NoError(t, err)
+}
+
 func TestEmptyPublicKey(t *testing.T) {
 	a, err := newTestAuthConf(&AuthParams{ProjectID: "a"}, nil, mocks.Do(func(r *http.Request) (*http.Response, error) {
 		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("[]"))}, nil
@@ -655,6 +684,10 @@ func TestAuthenticationMiddlewareSuccessDefault(t *testing.T) {
 		s, ok := r.Context().Value(ContextUserIDPropertyKey).(string)
 		require.True(t, ok)
 		assert.EqualValues(t, "someuser", s)
+		token, ok := TokenFromContext(r.Context())
+		require.True(t, ok)
+		assert.EqualValues(t, "someuser", token.ID)
+		assert.EqualValues(t, jwtTokenValid, token.JWT)
 		w.WriteHeader(http.StatusTeapot)
 	}))
 
@@ -686,6 +719,22 @@ func TestAuthenticationMiddlewareSuccess(t *testing.T) {
 	assert.EqualValues(t, http.StatusTeapot, res.Result().StatusCode)
 }
 
+func TestTokenFromContext(t *testing.T) {
+	token, ok := TokenFromContext(context.Background())
+	assert.False(t, ok)
+	assert.Nil(t, token)
+
+	ctx := ContextWithToken(context.Background(), nil)
+	_, ok = TokenFromContext(ctx)
+	assert.False(t, ok)
+
+	ctx = ContextWithToken(context.Background(), &Token{ID: "someuser", Claims: map[string]any{claimRoles: roles}})
+	token, ok = TokenFromContext(ctx)
+	require.True(t, ok)
+	assert.EqualValues(t, "someuser", token.ID)
+	assert.EqualValues(t, "someuser", ctx.Value(ContextUserIDPropertyKey))
+}
+
 func TestExtractTokensEmpty(t *testing.T) {
 	a, err := newTestAuth(nil, nil)
 	require.NoError(t, err)
@@ -1,9 +1,13 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 )
 
+// MockDescopeAuthenticationOTP - a hand-written mock of the OTP authentication.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
 type MockDescopeAuthenticationOTP struct {
 	AssertSignInOTP                 func(method DeliveryMethod, identifier string, r *http.Request, loginOptions *LoginOptions)
 	AssertSignUpOTP                 func(method DeliveryMethod, identifier string, user *User)
@@ -12,6 +16,11 @@ type MockDescopeAuthenticationOTP struct {
 	SignInOTPResponseError          error
 	SignUpOTPResponseError          error
 	SignUpOrInOTPResponseError      error
+	AssertSignInEmbeddedOTP         func(identifier string, r *http.Request, loginOptions *LoginOptions)
+	AssertSignUpEmbeddedOTP         func(identifier string, user *User)
+	AssertSignUpOrInEmbeddedOTP     func(identifier string)
+	EmbeddedOTPResponseCode         string
+	EmbeddedOTPResponseError        error
 	VerifyCodeResponseInfo          *AuthenticationInfo
 	AssertUpdateUserEmailOTP        func(identifier string, email string, request *http.Request)
 	UpdateUserEmailOTPResponseError error
@@ -20,26 +29,42 @@ type MockDescopeAuthenticationOTP struct {
 	VerifyCodeResponseError         error
 }
 
+// MockDescopeAuthenticationExchanger - a hand-written mock of the OAuth and SAML code exchange.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
 type MockDescopeAuthenticationExchanger struct {
-	AssertExchangeToken        func(code string, w http.ResponseWriter)
-	ExchangeTokenResponseInfo  *AuthenticationInfo
-	ExchangeTokenResponseError error
+	AssertExchangeToken            func(code string, w http.ResponseWriter)
+	AssertExchangeTokenFromRequest func(r *http.Request, w http.ResponseWriter)
+	ExchangeTokenResponseInfo      *AuthenticationInfo
+	ExchangeTokenResponseError     error
 }
 
+// MockDescopeAuthenticationSAML - a hand-written mock of the SAML authentication.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
 type MockDescopeAuthenticationSAML struct {
 	MockDescopeAuthenticationExchanger
 	AssertSAMLStart            func(tenant string, landingURL string, r *http.Request, loginOptions *LoginOptions, w http.ResponseWriter)
+	AssertSAMLStartOptions     func(tenant string, landingURL string, r *http.Request, loginOptions *LoginOptions, samlOptions *SAMLOptions, w http.ResponseWriter)
+	AssertSAMLStartWithEmail   func(emailOrDomain string, landingURL string, r *http.Request, loginOptions *LoginOptions, samlOptions *SAMLOptions, w http.ResponseWriter)
 	AssertSAMLStartResponseURL string
 	SAMLStartResponseError     error
 }
 
+// MockDescopeAuthenticationOAuth - a hand-written mock of the OAuth authentication.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
 type MockDescopeAuthenticationOAuth struct {
 	MockDescopeAuthenticationExchanger
 	AssertOAuthStart        func(provider OAuthProvider, landingURL string, r *http.Request, loginOptions *LoginOptions)
+	AssertOAuthStartOptions func(provider OAuthProvider, landingURL string, r *http.Request, loginOptions *LoginOptions, oauthOptions *OAuthOptions)
 	AssertOAuthResponseURL  string
 	OAuthStartResponseError error
 }
 
+// MockDescopeAuthenticationMagicLink - a hand-written mock of the magic link authentication.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
 type MockDescopeAuthenticationMagicLink struct {
 	AssertSignInMagicLink                       func(method DeliveryMethod, identifier, URI string, r *http.Request, loginOptions *LoginOptions)
 	AssertSignUpMagicLink                       func(method DeliveryMethod, identifier, URI string, user *User)
@@ -70,8 +95,52 @@ type MockDescopeAuthenticationMagicLink struct {
 	UpdateUserPhoneMagicLinkCrossDeviceResponse *MagicLinkResponse
 	GetMagicLinkSessionResponseInfo             *AuthenticationInfo
 	GetMagicLinkSessionResponseError            error
-}
-
+	AssertWaitForMagicLinkSession               func(ctx context.Context, pendingRef string, opts *WaitForSessionOptions)
+}
+
+// MockDescopeAuthenticationEnchantedLink - a hand-written mock of the enchanted link authentication.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
+type MockDescopeAuthenticationEnchantedLink struct {
+	AssertSignInEnchantedLink                 func(identifier, URI string, r *http.Request, loginOptions *LoginOptions)
+	AssertSignUpEnchantedLink                 func(identifier, URI string, user *User)
+	AssertSignUpOrInEnchantedLink             func(identifier, URI string)
+	EnchantedLinkResponse                     *EnchantedLinkResponse
+	SignInEnchantedLinkResponseError          error
+	SignUpEnchantedLinkResponseError          error
+	SignUpOrInEnchantedLinkResponseError      error
+	AssertGetEnchantedLinkSession             func(pendingRef string)
+	AssertWaitForEnchantedLinkSession         func(ctx context.Context, pendingRef string, opts *WaitForSessionOptions)
+	GetEnchantedLinkSessionResponseInfo       *AuthenticationInfo
+	GetEnchantedLinkSessionResponseError      error
+	AssertVerifyEnchantedLink                 func(token string)
+	VerifyEnchantedLinkResponseError          error
+	AssertUpdateUserEmailEnchantedLink        func(identifier, email, URI string, request *http.Request)
+	UpdateUserEmailEnchantedLinkResponseError error
+}
+
+// MockDescopeAuthenticationPassword - a hand-written mock of the password authentication.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
+type MockDescopeAuthenticationPassword struct {
+	AssertSignUpPassword             func(identifier string, user *User, password string)
+	AssertSignInPassword             func(identifier, password string, r *http.Request, loginOptions *LoginOptions)
+	PasswordResponseInfo             *AuthenticationInfo
+	SignUpPasswordResponseError      error
+	SignInPasswordResponseError      error
+	AssertSendPasswordReset          func(identifier, redirectURL string)
+	SendPasswordResetResponseError   error
+	AssertUpdateUserPassword         func(identifier, newPassword string, r *http.Request)
+	UpdateUserPasswordResponseError  error
+	AssertReplaceUserPassword        func(identifier, oldPassword, newPassword string)
+	ReplaceUserPasswordResponseError error
+	GetPasswordPolicyResponse        *PasswordPolicy
+	GetPasswordPolicyResponseError   error
+}
+
+// MockDescopeAuthenticationTOTP - a hand-written mock of the TOTP authentication.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
 type MockDescopeAuthenticationTOTP struct {
 	AssertSignInTOTP            func(method DeliveryMethod, identifier string)
 	SignInTOTPResponseError     error
@@ -86,6 +155,9 @@ type MockDescopeAuthenticationTOTP struct {
 	VerifyTOTPCodeResponseError error
 }
 
+// MockDescopeAuthenticationWebAuthn - a hand-written mock of the WebAuthn authentication.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
 type MockDescopeAuthenticationWebAuthn struct {
 	SignUpWebAuthnStartResponseError                 error
 	SignUpWebAuthnStartResponseTransaction           *WebAuthnTransactionResponse
@@ -102,9 +174,14 @@ type MockDescopeAuthenticationWebAuthn struct {
 	UpdateUserDeviceWebAuthnFinishResponseError      error
 }
 
+// MockDescopeAuthentication - a hand-written mock of the authentication services.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
 type MockDescopeAuthentication struct {
 	MockDescopeAuthenticationOTP
 	MockDescopeAuthenticationMagicLink
+	MockDescopeAuthenticationEnchantedLink
+	MockDescopeAuthenticationPassword
 	MockDescopeAuthenticationSAML
 	MockDescopeAuthenticationOAuth
 	MockDescopeAuthenticationTOTP
@@ -135,6 +212,9 @@ type MockDescopeAuthentication struct {
 	MeResponseError error
 }
 
+// NewMockDescopeAuthentication - creates an empty hand-written mock of the authentication services.
+//
+// Deprecated: use the generated mocks of the tests/descopemock package instead.
 func NewMockDescopeAuthentication() MockDescopeAuthentication {
 	return MockDescopeAuthentication{}
 }
@@ -147,6 +227,14 @@ func (m MockDescopeAuthentication) MagicLink() MagicLink {
 	return m.MockDescopeAuthenticationMagicLink
 }
 
+func (m MockDescopeAuthentication) EnchantedLink() EnchantedLink {
+	return m.MockDescopeAuthenticationEnchantedLink
+}
+
+func (m MockDescopeAuthentication) Password() Password {
+	return m.MockDescopeAuthenticationPassword
+}
+
 func (m MockDescopeAuthentication) WebAuthn() WebAuthn {
 	return m.MockDescopeAuthenticationWebAuthn
 }
@@ -184,6 +272,27 @@ func (m MockDescopeAuthenticationOTP) SignUpOrIn(method DeliveryMethod, identifi
 	return m.SignUpOrInOTPResponseError
 }
 
+func (m MockDescopeAuthenticationOTP) SignInEmbedded(identifier string, r *http.Request, loginOptions *LoginOptions) (string, error) {
+	if m.AssertSignInEmbeddedOTP != nil {
+		m.AssertSignInEmbeddedOTP(identifier, r, loginOptions)
+	}
+	return m.EmbeddedOTPResponseCode, m.EmbeddedOTPResponseError
+}
+
+func (m MockDescopeAuthenticationOTP) SignUpEmbedded(identifier string, user *User) (string, error) {
+	if m.AssertSignUpEmbeddedOTP != nil {
+		m.AssertSignUpEmbeddedOTP(identifier, user)
+	}
+	return m.EmbeddedOTPResponseCode, m.EmbeddedOTPResponseError
+}
+
+func (m MockDescopeAuthenticationOTP) SignUpOrInEmbedded(identifier string) (string, error) {
+	if m.AssertSignUpOrInEmbeddedOTP != nil {
+		m.AssertSignUpOrInEmbeddedOTP(identifier)
+	}
+	return m.EmbeddedOTPResponseCode, m.EmbeddedOTPResponseError
+}
+
 func (m MockDescopeAuthenticationTOTP) SignUp(identifier string, user *User) (*TOTPResponse, error) {
 	if m.AssertSignUpTOTP != nil {
 		m.AssertSignUpTOTP(identifier, user)
@@ -303,6 +412,13 @@ func (m MockDescopeAuthenticationMagicLink) GetSession(pendingRef string, _ http
 	return m.GetMagicLinkSessionResponseInfo, m.GetMagicLinkSessionResponseError
 }
 
+func (m MockDescopeAuthenticationMagicLink) WaitForSession(ctx context.Context, pendingRef string, opts *WaitForSessionOptions) (*AuthenticationInfo, error) {
+	if m.AssertWaitForMagicLinkSession != nil {
+		m.AssertWaitForMagicLinkSession(ctx, pendingRef, opts)
+	}
+	return m.GetMagicLinkSessionResponseInfo, m.GetMagicLinkSessionResponseError
+}
+
 func (m MockDescopeAuthenticationOAuth) Start(provider OAuthProvider, returnURL string, r *http.Request, loginOptions *LoginOptions, _ http.ResponseWriter) (string, error) {
 	if m.AssertOAuthStart != nil {
 		m.AssertOAuthStart(provider, returnURL, r, loginOptions)
@@ -310,6 +426,13 @@ func (m MockDescopeAuthenticationOAuth) Start(provider OAuthProvider, returnURL
 	return m.AssertOAuthResponseURL, m.OAuthStartResponseError
 }
 
+func (m MockDescopeAuthenticationOAuth) StartWithOptions(provider OAuthProvider, returnURL string, r *http.Request, loginOptions *LoginOptions, oauthOptions *OAuthOptions, _ http.ResponseWriter) (string, error) {
+	if m.AssertOAuthStartOptions != nil {
+		m.AssertOAuthStartOptions(provider, returnURL, r, loginOptions, oauthOptions)
+	}
+	return m.AssertOAuthResponseURL, m.OAuthStartResponseError
+}
+
 func (m MockDescopeAuthenticationExchanger) ExchangeToken(code string, w http.ResponseWriter) (*AuthenticationInfo, error) {
 	if m.AssertExchangeToken != nil {
 		m.AssertExchangeToken(code, w)
@@ -317,6 +440,13 @@ func (m MockDescopeAuthenticationExchanger) ExchangeToken(code string, w http.Re
 	return m.ExchangeTokenResponseInfo, m.ExchangeTokenResponseError
 }
 
+func (m MockDescopeAuthenticationExchanger) ExchangeTokenFromRequest(r *http.Request, w http.ResponseWriter) (*AuthenticationInfo, error) {
+	if m.AssertExchangeTokenFromRequest != nil {
+		m.AssertExchangeTokenFromRequest(r, w)
+	}
+	return m.ExchangeTokenResponseInfo, m.ExchangeTokenResponseError
+}
+
 func (m MockDescopeAuthenticationSAML) Start(tenant string, returnURL string, r *http.Request, loginOptions *LoginOptions, w http.ResponseWriter) (string, error) {
 	if m.AssertSAMLStart != nil {
 		m.AssertSAMLStart(tenant, returnURL, r, loginOptions, w)
@@ -324,6 +454,20 @@ func (m MockDescopeAuthenticationSAML) Start(tenant string, returnURL string, r
 	return m.AssertSAMLStartResponseURL, m.SAMLStartResponseError
 }
 
+func (m MockDescopeAuthenticationSAML) StartWithOptions(tenant string, returnURL string, r *http.Request, loginOptions *LoginOptions, samlOptions *SAMLOptions, w http.ResponseWriter) (string, error) {
+	if m.AssertSAMLStartOptions != nil {
+		m.AssertSAMLStartOptions(tenant, returnURL, r, loginOptions, samlOptions, w)
+	}
+	return m.AssertSAMLStartResponseURL, m.SAMLStartResponseError
+}
+
+func (m MockDescopeAuthenticationSAML) StartWithEmail(emailOrDomain string, returnURL string, r *http.Request, loginOptions *LoginOptions, samlOptions *SAMLOptions, w http.ResponseWriter) (string, error) {
+	if m.AssertSAMLStartWithEmail != nil {
+		m.AssertSAMLStartWithEmail(emailOrDomain, returnURL, r, loginOptions, samlOptions, w)
+	}
+	return m.AssertSAMLStartResponseURL, m.SAMLStartResponseError
+}
+
 func (m MockDescopeAuthenticationMagicLink) Verify(token string, _ http.ResponseWriter) (*AuthenticationInfo, error) {
 	if m.AssertVerifyMagicLink != nil {
 		m.AssertVerifyMagicLink(token)
@@ -408,3 +552,91 @@ func (m MockDescopeAuthentication) ExchangeAccessKey(_ string) (bool, *Token, er
 func (m MockDescopeAuthentication) Me(_ *http.Request) (*UserResponse, error) {
 	return m.MeResponseInfo, m.MeResponseError
 }
+
+func (m MockDescopeAuthenticationEnchantedLink) SignIn(identifier, URI string, r *http.Request, loginOptions *LoginOptions) (*EnchantedLinkResponse, error) {
+	if m.AssertSignInEnchantedLink != nil {
+		m.AssertSignInEnchantedLink(identifier, URI, r, loginOptions)
+	}
+	return m.EnchantedLinkResponse, m.SignInEnchantedLinkResponseError
+}
+
+func (m MockDescopeAuthenticationEnchantedLink) SignUp(identifier, URI string, user *User) (*EnchantedLinkResponse, error) {
+	if m.AssertSignUpEnchantedLink != nil {
+		m.AssertSignUpEnchantedLink(identifier, URI, user)
+	}
+	return m.EnchantedLinkResponse, m.SignUpEnchantedLinkResponseError
+}
+
+func (m MockDescopeAuthenticationEnchantedLink) SignUpOrIn(identifier, URI string) (*EnchantedLinkResponse, error) {
+	if m.AssertSignUpOrInEnchantedLink != nil {
+		m.AssertSignUpOrInEnchantedLink(identifier, URI)
+	}
+	return m.EnchantedLinkResponse, m.SignUpOrInEnchantedLinkResponseError
+}
+
+func (m MockDescopeAuthenticationEnchantedLink) GetSession(pendingRef string, _ http.ResponseWriter) (*AuthenticationInfo, error) {
+	if m.AssertGetEnchantedLinkSession != nil {
+		m.AssertGetEnchantedLinkSession(pendingRef)
+	}
+	return m.GetEnchantedLinkSessionResponseInfo, m.GetEnchantedLinkSessionResponseError
+}
+
+func (m MockDescopeAuthenticationEnchantedLink) WaitForSession(ctx context.Context, pendingRef string, opts *WaitForSessionOptions) (*AuthenticationInfo, error) {
+	if m.AssertWaitForEnchantedLinkSession != nil {
+		m.AssertWaitForEnchantedLinkSession(ctx, pendingRef, opts)
+	}
+	return m.GetEnchantedLinkSessionResponseInfo, m.GetEnchantedLinkSessionResponseError
+}
+
+func (m MockDescopeAuthenticationEnchantedLink) Verify(token string) error {
+	if m.AssertVerifyEnchantedLink != nil {
+		m.AssertVerifyEnchantedLink(token)
+	}
+	return m.VerifyEnchantedLinkResponseError
+}
+
+func (m MockDescopeAuthenticationEnchantedLink) UpdateUserEmail(identifier, email, URI string, request *http.Request) (*EnchantedLinkResponse, error) {
+	if m.AssertUpdateUserEmailEnchantedLink != nil {
+		m.AssertUpdateUserEmailEnchantedLink(identifier, email, URI, request)
+	}
+	return m.EnchantedLinkResponse, m.UpdateUserEmailEnchantedLinkResponseError
+}
+
+func (m MockDescopeAuthenticationPassword) SignUp(identifier string, user *User, password string, _ http.ResponseWriter) (*AuthenticationInfo, error) {
+	if m.AssertSignUpPassword != nil {
+		m.AssertSignUpPassword(identifier, user, password)
+	}
+	return m.PasswordResponseInfo, m.SignUpPasswordResponseError
+}
+
+func (m MockDescopeAuthenticationPassword) SignIn(identifier, password string, r *http.Request, loginOptions *LoginOptions, _ http.ResponseWriter) (*AuthenticationInfo, error) {
+	if m.AssertSignInPassword != nil {
+		m.AssertSignInPassword(identifier, password, r, loginOptions)
+	}
+	return m.PasswordResponseInfo, m.SignInPasswordResponseError
+}
+
+func (m MockDescopeAuthenticationPassword) SendPasswordReset(identifier, redirectURL string) error {
+	if m.AssertSendPasswordReset != nil {
+		m.AssertSendPasswordReset(identifier, redirectURL)
+	}
+	return m.SendPasswordResetResponseError
+}
+
+func (m MockDescopeAuthenticationPassword) UpdateUserPassword(identifier, newPassword string, r *http.Request) error {
+	if m.AssertUpdateUserPassword != nil {
+		m.AssertUpdateUserPassword(identifier, newPassword, r)
+	}
+	return m.UpdateUserPasswordResponseError
+}
+
+func (m MockDescopeAuthenticationPassword) ReplaceUserPassword(identifier, oldPassword, newPassword string, _ http.ResponseWriter) (*AuthenticationInfo, error) {
+	if m.AssertReplaceUserPassword != nil {
+		m.AssertReplaceUserPassword(identifier, oldPassword, newPassword)
+	}
+	return m.PasswordResponseInfo, m.ReplaceUserPasswordResponseError
+}
+
+func (m MockDescopeAuthenticationPassword) GetPasswordPolicy() (*PasswordPolicy, error) {
+	return m.GetPasswordPolicyResponse, m.GetPasswordPolicyResponseError
+}
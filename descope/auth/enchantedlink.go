@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/descope/go-sdk/descope/errors"
+)
+
+type enchantedLink struct {
+	authenticationsBase
+}
+
+func (auth *enchantedLink) SignIn(identifier, URI string, r *http.Request, loginOptions *LoginOptions) (*EnchantedLinkResponse, error) {
+	var pswd string
+	var err error
+	if identifier == "" {
+		return nil, errors.NewInvalidArgumentError("identifier")
+	}
+	if loginOptions.IsJWTRequired() {
+		pswd, err = getValidRefreshToken(r)
+		if err != nil {
+			return nil, errors.InvalidStepupJwtError
+		}
+	}
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeEnchantedLinkSignInURL(), newEnchantedLinkAuthenticationRequestBody(identifier, URI, loginOptions), nil, pswd)
+	if err != nil {
+		return nil, err
+	}
+	return getEnchantedLinkResponse(httpResponse)
+}
+
+func (auth *enchantedLink) SignUp(identifier, URI string, user *User) (*EnchantedLinkResponse, error) {
+	user, err := auth.verifyDeliveryMethod(MethodEmail, identifier, user)
+	if err != nil {
+		return nil, err
+	}
+	httpResponse, err := auth.client.DoPostRequest(composeEnchantedLinkSignUpURL(), newEnchantedLinkAuthenticationSignUpRequestBody(identifier, URI, user), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return getEnchantedLinkResponse(httpResponse)
+}
+
+func (auth *enchantedLink) SignUpOrIn(identifier, URI string) (*EnchantedLinkResponse, error) {
+	if identifier == "" {
+		return nil, errors.NewInvalidArgumentError("identifier")
+	}
+	httpResponse, err := auth.client.DoPostRequest(composeEnchantedLinkSignUpOrInURL(), newEnchantedLinkAuthenticationRequestBody(identifier, URI, nil), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return getEnchantedLinkResponse(httpResponse)
+}
+
+func (auth *enchantedLink) GetSession(pendingRef string, w http.ResponseWriter) (*AuthenticationInfo, error) {
+	httpResponse, err := auth.client.DoPostRequest(composeGetEnchantedLinkSession(), newAuthenticationGetMagicLinkSessionBody(pendingRef), nil, "")
+	if err != nil {
+		if err == errors.UnauthorizedError {
+			return nil, errors.EnchantedLinkUnauthorized
+		}
+		return nil, err
+	}
+	return auth.generateAuthenticationInfo(httpResponse, w)
+}
+
+func (auth *enchantedLink) WaitForSession(ctx context.Context, pendingRef string, opts *WaitForSessionOptions) (*AuthenticationInfo, error) {
+	if pendingRef == "" {
+		return nil, errors.NewInvalidArgumentError("pendingRef")
+	}
+	return pollSession(ctx, opts, func(ctx context.Context) (*AuthenticationInfo, error) {
+		polling := &enchantedLink{authenticationsBase: auth.authenticationsBase}
+		polling.client = auth.client.WithContext(ctx)
+		return polling.GetSession(pendingRef, nil)
+	}, errors.EnchantedLinkUnauthorized, nil)
+}
+
+func (auth *enchantedLink) Verify(token string) error {
+	if token == "" {
+		return errors.NewInvalidArgumentError("token")
+	}
+	_, err := auth.client.DoPostRequest(composeVerifyEnchantedLinkURL(), newMagicLinkAuthenticationVerifyRequestBody(token), nil, "")
+	return err
+}
+
+func (auth *enchantedLink) UpdateUserEmail(identifier, email, URI string, r *http.Request) (*EnchantedLinkResponse, error) {
+	if identifier == "" {
+		return nil, errors.NewInvalidArgumentError("identifier")
+	}
+	if email == "" {
+		return nil, errors.NewInvalidArgumentError("email")
+	}
+	email, err := auth.identifiers.NormalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	pswd, err := getValidRefreshToken(r)
+	if err != nil {
+		return nil, err
+	}
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeUpdateUserEmailEnchantedLink(), newMagicLinkUpdateEmailRequestBody(identifier, email, URI, false), nil, pswd)
+	if err != nil {
+		return nil, err
+	}
+	return getEnchantedLinkResponse(httpResponse)
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var enchantedLinkResponse = &EnchantedLinkResponse{PendingRef: "pending_ref", LinkID: "24", MaskedEmail: "t***@test.com"}
+
+func TestSignInEnchantedLink(t *testing.T) {
+	email := "test@test.com"
+	uri := "http://test.me"
+	a, err := newTestAuth(nil, DoOkWithBody(func(r *http.Request) {
+		assert.EqualValues(t, composeEnchantedLinkSignInURL(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, email, body["externalId"])
+		assert.EqualValues(t, uri, body["URI"])
+		assert.EqualValues(t, map[string]interface{}{"stepup": true}, body["loginOptions"])
+		_, jwt := getProjectAndJwt(r)
+		assert.EqualValues(t, "test", jwt)
+	}, enchantedLinkResponse))
+	require.NoError(t, err)
+	res, err := a.EnchantedLink().SignIn(email, uri, &http.Request{Header: http.Header{"Cookie": []string{"DSR=test"}}}, &LoginOptions{Stepup: true})
+	require.NoError(t, err)
+	assert.EqualValues(t, enchantedLinkResponse, res)
+}
+
+func TestSignInEnchantedLinkInvalid(t *testing.T) {
+	a, err := newTestAuth(nil, nil)
+	require.NoError(t, err)
+	_, err = a.EnchantedLink().SignIn("", "", nil, nil)
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+	_, err = a.EnchantedLink().SignIn("test@test.com", "", nil, &LoginOptions{Stepup: true})
+	assert.ErrorIs(t, err, errors.InvalidStepupJwtError)
+}
+
+func TestSignUpEnchantedLink(t *testing.T) {
+	email := "test@test.com"
+	a, err := newTestAuth(nil, DoOkWithBody(func(r *http.Request) {
+		assert.EqualValues(t, composeEnchantedLinkSignUpURL(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, email, body["email"])
+		assert.EqualValues(t, email, body["externalId"])
+		assert.EqualValues(t, "dude", body["user"].(map[string]interface{})["name"])
+	}, enchantedLinkResponse))
+	require.NoError(t, err)
+	res, err := a.EnchantedLink().SignUp(email, "", &User{Name: "dude"})
+	require.NoError(t, err)
+	assert.EqualValues(t, "24", res.LinkID)
+
+	_, err = a.EnchantedLink().SignUp("not-an-email", "", nil)
+	require.Error(t, err)
+}
+
+func TestSignUpOrInEnchantedLink(t *testing.T) {
+	email := "test@test.com"
+	a, err := newTestAuth(nil, DoOkWithBody(func(r *http.Request) {
+		assert.EqualValues(t, composeEnchantedLinkSignUpOrInURL(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, email, body["externalId"])
+	}, enchantedLinkResponse))
+	require.NoError(t, err)
+	res, err := a.EnchantedLink().SignUpOrIn(email, "")
+	require.NoError(t, err)
+	assert.EqualValues(t, "pending_ref", res.PendingRef)
+
+	_, err = a.EnchantedLink().SignUpOrIn("", "")
+	require.Error(t, err)
+}
+
+func TestVerifyEnchantedLink(t *testing.T) {
+	token := "token"
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, composeVerifyEnchantedLinkURL(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, token, body["token"])
+	}))
+	require.NoError(t, err)
+	require.NoError(t, a.EnchantedLink().Verify(token))
+	require.Error(t, a.EnchantedLink().Verify(""))
+}
+
+func TestGetEnchantedLinkSession(t *testing.T) {
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, composeGetEnchantedLinkSession(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, "pending_ref", body["pendingRef"])
+	}))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	info, err := a.EnchantedLink().GetSession("pending_ref", w)
+	require.NoError(t, err)
+	assert.NotEmpty(t, info.SessionToken.JWT)
+	assert.NotNil(t, findCookie(w.Result(), SessionCookieName))
+}
+
+func TestWaitForEnchantedLinkSession(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, doPendingSession(2, &calls))
+	require.NoError(t, err)
+	_, err = a.EnchantedLink().GetSession("pending_ref", nil)
+	assert.ErrorIs(t, err, errors.EnchantedLinkUnauthorized)
+	info, err := a.EnchantedLink().WaitForSession(context.Background(), "pending_ref", testWaitForSessionOptions)
+	require.NoError(t, err)
+	assert.NotNil(t, info.SessionToken)
+	assert.EqualValues(t, 3, calls)
+}
+
+func TestWaitForEnchantedLinkSessionTimeoutDuringRequest(t *testing.T) {
+	a, err := newTestAuth(nil, doSlowSession)
+	require.NoError(t, err)
+	start := time.Now()
+	_, err = a.EnchantedLink().WaitForSession(context.Background(), "pending_ref", &WaitForSessionOptions{Timeout: 20 * time.Millisecond})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestUpdateUserEmailEnchantedLink(t *testing.T) {
+	a, err := newTestAuth(nil, DoOkWithBody(func(r *http.Request) {
+		assert.EqualValues(t, composeUpdateUserEmailEnchantedLink(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, "id", body["externalId"])
+		assert.EqualValues(t, "new@test.com", body["email"])
+		_, jwt := getProjectAndJwt(r)
+		assert.EqualValues(t, "test", jwt)
+	}, enchantedLinkResponse))
+	require.NoError(t, err)
+	r := &http.Request{Header: http.Header{"Cookie": []string{"DSR=test"}}}
+	res, err := a.EnchantedLink().UpdateUserEmail("id", "new@test.com", "", r)
+	require.NoError(t, err)
+	assert.EqualValues(t, "24", res.LinkID)
+
+	_, err = a.EnchantedLink().UpdateUserEmail("id", "invalid", "", r)
+	require.Error(t, err)
+	_, err = a.EnchantedLink().UpdateUserEmail("id", "new@test.com", "", &http.Request{})
+	require.Error(t, err)
+}
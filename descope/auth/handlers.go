@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/logger"
+)
+
+const defaultPostLoginURL = "/"
+
+// RedirectHandlerOptions - configuration shared by the OAuth and SAML start and callback handlers.
+type RedirectHandlerOptions struct {
+	// CallbackURL (required) - the absolute URL the callback handler is served on, the user will be redirected
+	// back to it by Descope once the authentication with the provider is done.
+	CallbackURL string
+	// PostLoginURL (optional, "/") - the URL the callback handler redirects to after a successful authentication.
+	PostLoginURL string
+	// LoginOptions (optional, nil) - used for step-up or MFA flows, same as in Start.
+	LoginOptions *LoginOptions
+	// OnError (optional, nil) - called when the flow fails, if empty, will write unauthorized (401) on the response writer.
+	OnError func(http.ResponseWriter, *http.Request, error)
+}
+
+// OAuthHandlerOptions - configuration for NewOAuthHandlers.
+type OAuthHandlerOptions struct {
+	RedirectHandlerOptions
+	// Provider (optional, "") - the OAuth provider used when the start request has no "provider" query parameter.
+	Provider OAuthProvider
+	// OAuthOptions (optional, nil) - additional provider specific options, same as in StartWithOptions.
+	OAuthOptions *OAuthOptions
+}
+
+// SAMLHandlerOptions - configuration for NewSAMLHandlers.
+type SAMLHandlerOptions struct {
+	RedirectHandlerOptions
+	// Tenant (optional, "") - the tenant to start the SSO for, if empty, it is taken from the "tenant" query parameter of the start request,
+	// and when that is empty as well, the tenant is resolved from the "email" query parameter (see SAML.StartWithEmail).
+	Tenant string
+}
+
+// NewOAuthHandlers - returns a ready-made pair of handlers for an OAuth authentication.
+// The start handler redirects to the provider, binding the flow to the browser with a short-lived state cookie,
+// and the callback handler verifies the state, exchanges the code, sets the session cookies on the response
+// and redirects to the configured post-login URL.
+func NewOAuthHandlers(oauth OAuth, options OAuthHandlerOptions) (startHandler http.Handler, callbackHandler http.Handler) {
+	startHandler = newRedirectStartHandler(&options.RedirectHandlerOptions, func(w http.ResponseWriter, r *http.Request) error {
+		provider := OAuthProvider(r.URL.Query().Get("provider"))
+		if provider == "" {
+			provider = options.Provider
+		}
+		oauthOptions := OAuthOptions{}
+		if options.OAuthOptions != nil {
+			oauthOptions = *options.OAuthOptions
+		}
+		oauthOptions.BindState = true
+		_, err := oauth.StartWithOptions(provider, options.CallbackURL, r, options.LoginOptions, &oauthOptions, w)
+		return err
+	})
+	callbackHandler = newRedirectCallbackHandler(&options.RedirectHandlerOptions, oauth.ExchangeTokenFromRequest)
+	return startHandler, callbackHandler
+}
+
+// NewSAMLHandlers - returns a ready-made pair of handlers for a SAML authentication, see NewOAuthHandlers.
+func NewSAMLHandlers(saml SAML, options SAMLHandlerOptions) (startHandler http.Handler, callbackHandler http.Handler) {
+	startHandler = newRedirectStartHandler(&options.RedirectHandlerOptions, func(w http.ResponseWriter, r *http.Request) error {
+		tenant := options.Tenant
+		if tenant == "" {
+			tenant = r.URL.Query().Get("tenant")
+		}
+		samlOptions := &SAMLOptions{BindState: true}
+		if email := r.URL.Query().Get("email"); tenant == "" && email != "" {
+			_, err := saml.StartWithEmail(email, options.CallbackURL, r, options.LoginOptions, samlOptions, w)
+			return err
+		}
+		_, err := saml.StartWithOptions(tenant, options.CallbackURL, r, options.LoginOptions, samlOptions, w)
+		return err
+	})
+	callbackHandler = newRedirectCallbackHandler(&options.RedirectHandlerOptions, saml.ExchangeTokenFromRequest)
+	return startHandler, callbackHandler
+}
+
+func newRedirectStartHandler(options *RedirectHandlerOptions, start func(http.ResponseWriter, *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if options.CallbackURL == "" {
+			options.fail(w, r, errors.NewInvalidArgumentError("CallbackURL"))
+			return
+		}
+		if err := start(w, r); err != nil {
+			logger.LogError("failed to start redirect authentication", err)
+			options.fail(w, r, err)
+		}
+	})
+}
+
+func newRedirectCallbackHandler(options *RedirectHandlerOptions, exchange func(*http.Request, http.ResponseWriter) (*AuthenticationInfo, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := exchange(r, w); err != nil {
+			logger.LogError("failed to exchange redirect authentication code", err)
+			options.fail(w, r, err)
+			return
+		}
+
+		postLoginURL := options.PostLoginURL
+		if postLoginURL == "" {
+			postLoginURL = defaultPostLoginURL
+		}
+		http.Redirect(w, r, postLoginURL, http.StatusFound)
+	})
+}
+
+func (options *RedirectHandlerOptions) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if options.OnError != nil {
+		options.OnError(w, r, err)
+	} else {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+}
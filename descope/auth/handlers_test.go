@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findCookie(res *http.Response, name string) *http.Cookie {
+	for _, c := range res.Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestOAuthHandlers(t *testing.T) {
+	uri := "http://test.me"
+	callbackURL := "https://example.com/oauth/callback?a=b"
+	var state string
+	a, err := newTestAuth(nil, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == composeOAuthURL() {
+			assert.EqualValues(t, OAuthGithub, r.URL.Query().Get("provider"))
+			redirectURL, err := url.Parse(r.URL.Query().Get("redirectURL"))
+			require.NoError(t, err)
+			assert.EqualValues(t, "b", redirectURL.Query().Get("a"))
+			state = redirectURL.Query().Get(redirectStateQueryParam)
+			assert.NotEmpty(t, state)
+			return DoRedirect(uri, nil)(r)
+		}
+		req := exchangeTokenBody{}
+		require.NoError(t, readBody(r, &req))
+		assert.EqualValues(t, "abc", req.Code)
+		assert.NotEmpty(t, req.CodeVerifier)
+		return DoOk(nil)(r)
+	})
+	require.NoError(t, err)
+	start, callback := NewOAuthHandlers(a.OAuth(), OAuthHandlerOptions{RedirectHandlerOptions: RedirectHandlerOptions{CallbackURL: callbackURL, PostLoginURL: "/home"}})
+
+	w := httptest.NewRecorder()
+	start.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oauth/start?provider=github", nil))
+	res := w.Result()
+	assert.EqualValues(t, http.StatusTemporaryRedirect, res.StatusCode)
+	assert.EqualValues(t, uri, res.Header.Get(RedirectLocationCookieName))
+	stateCookie := findCookie(res, RedirectStateCookiePrefix+state)
+	require.NotNil(t, stateCookie)
+	assert.NotEmpty(t, stateCookie.Value)
+	assert.EqualValues(t, "/oauth/callback", stateCookie.Path)
+	assert.True(t, stateCookie.HttpOnly)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/oauth/callback?code=abc&state="+url.QueryEscape(state), nil)
+	req.AddCookie(stateCookie)
+	callback.ServeHTTP(w, req)
+	res = w.Result()
+	assert.EqualValues(t, http.StatusFound, res.StatusCode)
+	assert.EqualValues(t, "/home", res.Header.Get("Location"))
+	assert.NotNil(t, findCookie(res, SessionCookieName))
+	assert.EqualValues(t, -1, findCookie(res, RedirectStateCookiePrefix+state).MaxAge)
+}
+
+func TestOAuthHandlersDefaultProvider(t *testing.T) {
+	providers := []string{}
+	a, err := newTestAuth(nil, DoRedirect("http://test.me", func(r *http.Request) {
+		providers = append(providers, r.URL.Query().Get("provider"))
+	}))
+	require.NoError(t, err)
+	start, _ := NewOAuthHandlers(a.OAuth(), OAuthHandlerOptions{RedirectHandlerOptions: RedirectHandlerOptions{CallbackURL: "https://example.com/oauth/callback"}, Provider: OAuthFacebook})
+
+	start.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/oauth/start", nil))
+	start.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/oauth/start?provider=github", nil))
+	assert.EqualValues(t, []string{string(OAuthFacebook), string(OAuthGithub)}, providers)
+}
+
+func TestOAuthHandlersStateMismatch(t *testing.T) {
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.Fail(t, "code should not be exchanged")
+	}))
+	require.NoError(t, err)
+	var failure error
+	_, callback := NewOAuthHandlers(a.OAuth(), OAuthHandlerOptions{RedirectHandlerOptions: RedirectHandlerOptions{CallbackURL: "https://example.com", OnError: func(w http.ResponseWriter, r *http.Request, err error) {
+		failure = err
+		w.WriteHeader(http.StatusBadRequest)
+	}}})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/oauth/callback?code=abc&state=foo", nil)
+	req.AddCookie(&http.Cookie{Name: RedirectStateCookiePrefix + "bar", Value: "verifier"})
+	callback.ServeHTTP(w, req)
+	assert.EqualValues(t, http.StatusBadRequest, w.Result().StatusCode)
+	assert.ErrorIs(t, failure, errors.InvalidStateError)
+
+	w = httptest.NewRecorder()
+	callback.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/oauth/callback?code=abc&state=foo", nil))
+	assert.ErrorIs(t, failure, errors.InvalidStateError)
+}
+
+func TestSAMLHandlers(t *testing.T) {
+	uri := "http://test.me"
+	var state string
+	a, err := newTestAuth(nil, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == composeSAMLStartURL() {
+			assert.EqualValues(t, "tenant1", r.URL.Query().Get("tenant"))
+			state = assertBoundRedirectURL(t, r, "https://example.com/saml/callback")
+			return DoRedirect(uri, nil)(r)
+		}
+		return DoOk(nil)(r)
+	})
+	require.NoError(t, err)
+	start, callback := NewSAMLHandlers(a.SAML(), SAMLHandlerOptions{RedirectHandlerOptions: RedirectHandlerOptions{CallbackURL: "https://example.com/saml/callback"}})
+
+	w := httptest.NewRecorder()
+	start.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/saml/start?tenant=tenant1", nil))
+	res := w.Result()
+	assert.EqualValues(t, http.StatusTemporaryRedirect, res.StatusCode)
+	stateCookie := findCookie(res, RedirectStateCookiePrefix+state)
+	require.NotNil(t, stateCookie)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/saml/callback?code=abc&state="+url.QueryEscape(state), nil)
+	req.AddCookie(stateCookie)
+	callback.ServeHTTP(w, req)
+	res = w.Result()
+	assert.EqualValues(t, http.StatusFound, res.StatusCode)
+	assert.EqualValues(t, defaultPostLoginURL, res.Header.Get("Location"))
+}
+
+func TestRedirectHandlersStartFailure(t *testing.T) {
+	a, err := newTestAuth(nil, DoBadRequest(nil))
+	require.NoError(t, err)
+	start, _ := NewSAMLHandlers(a.SAML(), SAMLHandlerOptions{RedirectHandlerOptions: RedirectHandlerOptions{CallbackURL: "https://example.com/saml/callback"}})
+	w := httptest.NewRecorder()
+	start.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/saml/start", nil))
+	assert.EqualValues(t, http.StatusUnauthorized, w.Result().StatusCode)
+
+	start, _ = NewSAMLHandlers(a.SAML(), SAMLHandlerOptions{Tenant: "t1"})
+	w = httptest.NewRecorder()
+	start.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/saml/start", nil))
+	assert.EqualValues(t, http.StatusUnauthorized, w.Result().StatusCode)
+}
+
+func TestSAMLHandlersStartWithEmail(t *testing.T) {
+	uri := "http://test.me"
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", TenantResolver: ssoTenants{"example.com": "t1"}}, nil, DoRedirect(uri, func(r *http.Request) {
+		assert.EqualValues(t, "t1", r.URL.Query().Get("tenant"))
+	}))
+	require.NoError(t, err)
+	start, _ := NewSAMLHandlers(a.SAML(), SAMLHandlerOptions{RedirectHandlerOptions: RedirectHandlerOptions{CallbackURL: "https://example.com/saml/callback"}})
+	w := httptest.NewRecorder()
+	start.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/saml/start?email=dude%40example.com", nil))
+	assert.EqualValues(t, http.StatusTemporaryRedirect, w.Result().StatusCode)
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"net/mail"
+	"strings"
+
+	"github.com/descope/go-sdk/descope/errors"
+)
+
+const (
+	minPhoneDigits = 7
+	maxPhoneDigits = 15 // the maximal length of an E.164 number, including the country calling code
+)
+
+type phoneRegion struct {
+	callingCode string
+	trunkPrefix string // removed from national numbers before adding the calling code
+}
+
+// phoneRegions - the ISO 3166-1 alpha-2 regions that can be used as a default region, with their country calling
+// code and national trunk prefix.
+var phoneRegions = map[string]phoneRegion{
+	"AE": {"971", "0"}, "AR": {"54", "0"}, "AT": {"43", "0"}, "AU": {"61", "0"}, "BE": {"32", "0"},
+	"BR": {"55", "0"}, "CA": {"1", "1"}, "CH": {"41", "0"}, "CN": {"86", "0"}, "CZ": {"420", ""},
+	"DE": {"49", "0"}, "DK": {"45", ""}, "EG": {"20", "0"}, "ES": {"34", ""}, "FI": {"358", "0"},
+	"FR": {"33", "0"}, "GB": {"44", "0"}, "GR": {"30", ""}, "HK": {"852", ""}, "ID": {"62", "0"},
+	"IE": {"353", "0"}, "IL": {"972", "0"}, "IN": {"91", "0"}, "IT": {"39", ""}, "JP": {"81", "0"},
+	"KR": {"82", "0"}, "MX": {"52", ""}, "MY": {"60", "0"}, "NG": {"234", "0"}, "NL": {"31", "0"},
+	"NO": {"47", ""}, "NZ": {"64", "0"}, "PH": {"63", "0"}, "PL": {"48", ""}, "PT": {"351", ""},
+	"RU": {"7", "8"}, "SA": {"966", "0"}, "SE": {"46", "0"}, "SG": {"65", ""}, "TH": {"66", "0"},
+	"TR": {"90", "0"}, "UA": {"380", "0"}, "US": {"1", "1"}, "VN": {"84", "0"}, "ZA": {"27", "0"},
+}
+
+// IdentifierResolver - validates, normalizes and classifies the emails and phone numbers given to the
+// authentication methods. The SDK uses it with the default region from the client configuration,
+// and it can be used directly to normalize identifiers the same way before storing them.
+type IdentifierResolver struct {
+	// DefaultRegion (optional, "") - the ISO 3166-1 alpha-2 region (e.g. "US") of phone numbers given in national format,
+	// i.e. without a leading + or 00. When empty, such numbers are validated and their digits are returned with a
+	// leading + as is.
+	DefaultRegion string
+}
+
+// NewIdentifierResolver - returns a resolver for the given default region, or an error when the region is not supported.
+func NewIdentifierResolver(defaultRegion string) (*IdentifierResolver, error) {
+	defaultRegion = strings.ToUpper(strings.TrimSpace(defaultRegion))
+	if _, ok := phoneRegions[defaultRegion]; defaultRegion != "" && !ok {
+		return nil, errors.NewInvalidArgumentError("DefaultRegion")
+	}
+	return &IdentifierResolver{DefaultRegion: defaultRegion}, nil
+}
+
+// NormalizePhone - returns the given phone number in E.164 format (e.g. "+14155550100").
+// Spaces, dashes, dots, slashes and parentheses are ignored, and a leading 00 is treated as a +.
+// Numbers without a leading + or 00 are resolved using the default region. When there is none, their country cannot
+// be known, and their digits are returned with a leading + as is, so they must already include the calling code.
+// Extensions, letters and numbers that are too short or too long are not supported and return an error.
+func (r *IdentifierResolver) NormalizePhone(phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	digits := strings.Map(func(c rune) rune {
+		if strings.ContainsRune(" -./\\()", c) {
+			return -1
+		}
+		return c
+	}, phone)
+
+	international := false
+	if strings.HasPrefix(digits, "+") {
+		digits, international = digits[1:], true
+	} else if strings.HasPrefix(digits, "00") {
+		digits, international = digits[2:], true
+	}
+	if digits == "" || strings.IndexFunc(digits, func(c rune) bool { return c < '0' || c > '9' }) >= 0 {
+		return "", errors.NewInvalidArgumentError("phone")
+	}
+
+	if !international {
+		if r == nil || r.DefaultRegion == "" {
+			if len(digits) < minPhoneDigits || len(digits) > maxPhoneDigits {
+				return "", errors.NewInvalidArgumentError("phone")
+			}
+			return "+" + digits, nil
+		}
+		region, ok := phoneRegions[strings.ToUpper(r.DefaultRegion)]
+		if !ok {
+			return "", errors.NewInvalidArgumentError("DefaultRegion")
+		}
+		if region.trunkPrefix != "" {
+			digits = strings.TrimPrefix(digits, region.trunkPrefix)
+		}
+		digits = region.callingCode + digits
+	}
+
+	if digits[0] == '0' || len(digits) < minPhoneDigits || len(digits) > maxPhoneDigits {
+		return "", errors.NewInvalidArgumentError("phone")
+	}
+	return "+" + digits, nil
+}
+
+// NormalizeEmail - returns the given email address trimmed and with a lower cased domain.
+// The local part is kept as is, display names (e.g. "Dude <dude@example.com>") are not supported,
+// and the domain must contain at least one dot.
+func (r *IdentifierResolver) NormalizeEmail(email string) (string, error) {
+	return normalizeEmail(email)
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Name != "" || address.Address != email {
+		return "", errors.NewInvalidArgumentError("email")
+	}
+	at := strings.LastIndex(email, "@")
+	domain := strings.ToLower(email[at+1:])
+	if !strings.Contains(strings.Trim(domain, "."), ".") {
+		return "", errors.NewInvalidArgumentError("email")
+	}
+	return email[:at+1] + domain, nil
+}
+
+// ResolveDeliveryMethod - returns the delivery method that matches the given identifier, and the identifier
+// that should be used with it.
+//   - An identifier with an @ resolves to MethodEmail, when it is a valid email address.
+//   - Otherwise, an identifier that is a valid phone number resolves to MethodSMS.
+//
+// A phone number cannot tell which channel a code was delivered with, so it never resolves to MethodWhatsApp or
+// MethodVoice, and codes delivered with those methods must be verified with the method given explicitly.
+// The returned identifier is not normalized, as it is used as the login ID of the user.
+func (r *IdentifierResolver) ResolveDeliveryMethod(identifier string) (DeliveryMethod, string, error) {
+	identifier = strings.TrimSpace(identifier)
+	if strings.Contains(identifier, "@") {
+		if _, err := r.NormalizeEmail(identifier); err != nil {
+			return "", "", errors.NewInvalidArgumentError("identifier")
+		}
+		return MethodEmail, identifier, nil
+	}
+	if _, err := r.NormalizePhone(identifier); err != nil {
+		return "", "", errors.NewInvalidArgumentError("method")
+	}
+	return MethodSMS, identifier, nil
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIdentifierResolver(t *testing.T) {
+	r, err := NewIdentifierResolver(" gb")
+	require.NoError(t, err)
+	assert.EqualValues(t, "GB", r.DefaultRegion)
+	_, err = NewIdentifierResolver("")
+	require.NoError(t, err)
+	_, err = NewIdentifierResolver("XX")
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+}
+
+func TestNormalizePhone(t *testing.T) {
+	noRegion := &IdentifierResolver{}
+	for phone, expected := range map[string]string{
+		"+1 (415) 555-0100": "+14155550100",
+		"0044 20.7946.0958": "+442079460958",
+		" +79161234567 ":    "+79161234567",
+	} {
+		res, err := noRegion.NormalizePhone(phone)
+		require.NoError(t, err, phone)
+		assert.EqualValues(t, expected, res, phone)
+	}
+	// without a default region, national numbers are not given a country calling code
+	for phone, expected := range map[string]string{
+		"943248329844":    "+943248329844",
+		"972/54-123-4567": "+972541234567",
+	} {
+		res, err := noRegion.NormalizePhone(phone)
+		require.NoError(t, err, phone)
+		assert.EqualValues(t, expected, res, phone)
+	}
+	for _, phone := range []string{"", "+", "12345", "123456", "1234567890123456", "+0123456789", "+1234567890123456", "+1 415 555 0100 ext 12", "415-CALL-NOW", "test@test.com"} {
+		_, err := noRegion.NormalizePhone(phone)
+		assert.Error(t, err, phone)
+	}
+
+	us := &IdentifierResolver{DefaultRegion: "US"}
+	res, err := us.NormalizePhone("(415) 555-0100")
+	require.NoError(t, err)
+	assert.EqualValues(t, "+14155550100", res)
+	res, err = us.NormalizePhone("1 415 555 0100")
+	require.NoError(t, err)
+	assert.EqualValues(t, "+14155550100", res)
+	res, err = us.NormalizePhone("+44 20 7946 0958")
+	require.NoError(t, err)
+	assert.EqualValues(t, "+442079460958", res)
+
+	gb := &IdentifierResolver{DefaultRegion: "GB"}
+	res, err = gb.NormalizePhone("020 7946 0958")
+	require.NoError(t, err)
+	assert.EqualValues(t, "+442079460958", res)
+
+	it := &IdentifierResolver{DefaultRegion: "IT"}
+	res, err = it.NormalizePhone("06 1234 5678")
+	require.NoError(t, err)
+	assert.EqualValues(t, "+390612345678", res)
+
+	_, err = (&IdentifierResolver{DefaultRegion: "XX"}).NormalizePhone("4155550100")
+	assert.Error(t, err)
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	r := &IdentifierResolver{}
+	res, err := r.NormalizeEmail(" Dude.Name+tag@Example.COM ")
+	require.NoError(t, err)
+	assert.EqualValues(t, "Dude.Name+tag@example.com", res)
+	for _, email := range []string{"", "test", "test@", "@test.com", "test@localhost", "Dude <dude@test.com>", "te st@test.com", "a@b@test.com"} {
+		_, err := r.NormalizeEmail(email)
+		assert.Error(t, err, email)
+	}
+}
+
+func TestResolveDeliveryMethod(t *testing.T) {
+	r := &IdentifierResolver{}
+	method, identifier, err := r.ResolveDeliveryMethod("test@test.com")
+	require.NoError(t, err)
+	assert.EqualValues(t, MethodEmail, method)
+	assert.EqualValues(t, "test@test.com", identifier)
+
+	method, identifier, err = r.ResolveDeliveryMethod("+1 415 555 0100")
+	require.NoError(t, err)
+	assert.EqualValues(t, MethodSMS, method)
+	assert.EqualValues(t, "+1 415 555 0100", identifier)
+
+	// an invalid email is not treated as a phone number
+	_, _, err = r.ResolveDeliveryMethod("1234567@")
+	assert.Error(t, err)
+	_, _, err = r.ResolveDeliveryMethod("my username")
+	assert.Error(t, err)
+}
+
+func TestDefaultRegionNormalization(t *testing.T) {
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", PublicKey: publicKey, DefaultRegion: "GB"}, nil, DoOk(func(r *http.Request) {
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, "+447911123456", body["phone"])
+		assert.EqualValues(t, "07911 123456", body["externalId"])
+	}))
+	require.NoError(t, err)
+	require.NoError(t, a.OTP().SignUp(MethodSMS, "07911 123456", nil))
+
+	_, err = newTestAuthConf(&AuthParams{ProjectID: "a", DefaultRegion: "XX"}, nil, nil)
+	assert.Error(t, err)
+}
@@ -3,6 +3,8 @@ package auth
 import (
 	"context"
 	"path"
+	"sync"
+	"time"
 
 	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwk"
@@ -15,10 +17,13 @@ import (
 )
 
 type provider struct {
-	client      *api.Client
-	conf        *AuthParams
-	providedKey jwk.Key
-	keySet      map[string]jwk.Key
+	client *api.Client
+	conf   *AuthParams
+	// providedKeys - the keys parsed from the PublicKey configuration, once on first use
+	providedKeys     *PublicKeySet
+	providedKeysErr  error
+	providedKeysOnce sync.Once
+	keySet           map[string]jwk.Key
 }
 
 func newProvider(client *api.Client, conf *AuthParams) *provider {
@@ -26,7 +31,8 @@ func newProvider(client *api.Client, conf *AuthParams) *provider {
 }
 
 func (p *provider) publicKeyExists() bool {
-	return len(p.keySet) > 0 || p.providedKey != nil
+	providedKeys, _ := p.parsedPublicKey()
+	return len(p.keySet) > 0 || providedKeys.Len() > 0 || p.conf.PublicKeys.Len() > 0
 }
 
 func (p *provider) selectKey(sink jws.KeySink, key jwk.Key) error {
@@ -47,12 +53,28 @@ func (p *provider) selectKey(sink jws.KeySink, key jwk.Key) error {
 	return errors.NewValidationError("algorithm in the message does not match")
 }
 
+// keyLookup is the key provider of a single JWT validation, which records how the keys were found
+type keyLookup struct {
+	provider *provider
+	fetched  bool
+	err      error
+}
+
 func (p *provider) requestKeys() error {
+	start := time.Now()
+	count, err := p.fetchKeySet()
+	if observer := p.conf.Observer; observer != nil {
+		observer.ObserveKeysFetch(KeysEvent{ProjectID: p.conf.ProjectID, Start: start, Duration: time.Since(start), Keys: count, Err: err})
+	}
+	return err
+}
+
+func (p *provider) fetchKeySet() (int, error) {
 	projectID := p.conf.ProjectID
 	keys := []map[string]interface{}{}
 	_, err := p.client.DoGetRequest(path.Join(api.Routes.GetKeys(), projectID), &api.HTTPRequest{ResBodyObj: &keys}, "")
 	if err != nil {
-		return err
+		return 0, err
 	}
 	tempKeySet := map[string]jwk.Key{}
 	for i := range keys {
@@ -79,65 +101,102 @@ func (p *provider) requestKeys() error {
 
 	logger.LogDebug("refresh keys set with %d key(s)", len(tempKeySet))
 	p.keySet = tempKeySet
-	return nil
+	return len(tempKeySet), nil
 }
 
-func (p *provider) providedPublicKey() (jwk.Key, error) {
-	if p.providedKey != nil {
-		return p.providedKey, nil
-	}
-
-	if p.conf.PublicKey != "" {
-		jk, err := jwk.ParseKey([]byte(p.conf.PublicKey))
-		if err != nil {
+// parsedPublicKey returns the keys of the PublicKey configuration, nil when it is empty
+func (p *provider) parsedPublicKey() (*PublicKeySet, error) {
+	p.providedKeysOnce.Do(func() {
+		if p.conf.PublicKey == "" {
+			return
+		}
+		p.providedKeys, p.providedKeysErr = ParsePublicKeys([]byte(p.conf.PublicKey))
+		if p.providedKeysErr != nil {
 			logger.LogDebug("unable to parse key")
-			return nil, err
 		}
-		p.providedKey, _ = jk.PublicKey()
-		return p.providedKey, nil
+	})
+	return p.providedKeys, p.providedKeysErr
+}
+
+// providedPublicKeys returns the keys given in the configuration, nil when no keys were given.
+// exact is true when the keys were found by the given key ID, otherwise the returned keys are the ones without an ID.
+func (p *provider) providedPublicKeys(kid string) (keys []jwk.Key, exact bool, err error) {
+	providedKeys, err := p.parsedPublicKey()
+	if err != nil {
+		return nil, false, err
+	}
+	if providedKeys == nil && p.conf.PublicKeys == nil {
+		return nil, false, nil
+	}
+	configured, configuredExact := p.conf.PublicKeys.find(kid)
+	parsed, parsedExact := providedKeys.find(kid)
+	if configuredExact || parsedExact {
+		keys = []jwk.Key{}
+		if configuredExact {
+			keys = append(keys, configured...)
+		}
+		if parsedExact {
+			keys = append(keys, parsed...)
+		}
+		return keys, true, nil
 	}
-	return nil, nil
+	return append(append([]jwk.Key{}, configured...), parsed...), false, nil
 }
 
-func (p *provider) findKey(kid string) (jwk.Key, error) {
-	key, err := p.providedPublicKey()
+func (p *provider) findKeys(kid string, lookup *keyLookup) ([]jwk.Key, error) {
+	keys, exact, err := p.providedPublicKeys(kid)
 	if err != nil {
 		return nil, err
 	}
-	if key != nil {
-		if key.KeyID() == kid {
-			return key, nil
-		}
+	// keys without an ID match any token when the keys cannot be fetched, otherwise they are only tried along with the
+	// fetched key of the token, which could have been signed by a key that was rotated since they were pinned
+	if exact || (len(keys) > 0 && (kid == "" || !p.conf.FetchPublicKeys)) {
+		return keys, nil
+	}
+	if key, ok := p.keySet[kid]; ok {
+		return append([]jwk.Key{key}, keys...), nil
+	}
+	if keys != nil && !p.conf.FetchPublicKeys {
 		err = errors.NewNoPublicKeyError()
 		logger.LogError("Provided public key does not match required public key", err)
 		return nil, err
 	}
 
+	lookup.fetched = true
 	if err := p.requestKeys(); err != nil {
 		logger.LogDebug("failed to retrieve public keys from API [%s]", err)
+		if len(keys) > 0 {
+			return keys, nil
+		}
 		return nil, err
 	}
 
 	key, ok := p.keySet[kid]
 	if !ok {
+		if len(keys) > 0 {
+			return keys, nil
+		}
 		err := errors.NewNoPublicKeyError()
 		logger.LogError("Required public key does not exists in key set (key set size [%d])", err, len(p.keySet))
 		return nil, err
 	}
 
-	return key, nil
+	return append([]jwk.Key{key}, keys...), nil
 }
 
-func (p *provider) FetchKeys(_ context.Context, sink jws.KeySink, sig *jws.Signature, _ *jws.Message) error {
+func (l *keyLookup) FetchKeys(_ context.Context, sink jws.KeySink, sig *jws.Signature, _ *jws.Message) error {
 	wantedKid := sig.ProtectedHeaders().KeyID()
-	v, ok := p.keySet[wantedKid]
-	if !ok {
+	keys, err := l.provider.findKeys(wantedKid, l)
+	if err != nil {
 		logger.LogDebug("key was not found, looking for key id [%s]", wantedKid)
-		if key, err := p.findKey(wantedKid); key != nil {
-			v = key
-		} else {
+		l.err = err
+		return err
+	}
+	for _, key := range keys {
+		if err := l.provider.selectKey(sink, key); err != nil {
+			l.err = err
 			return err
 		}
 	}
-	return p.selectKey(sink, v)
+	return nil
 }
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/descope/go-sdk/descope/errors"
@@ -16,6 +17,9 @@ func (auth *magicLink) SignIn(method DeliveryMethod, identifier, URI string, r *
 	if identifier == "" {
 		return errors.NewInvalidArgumentError("identifier")
 	}
+	if err := verifySupportedDeliveryMethod(method, magicLinkDeliveryMethods); err != nil {
+		return err
+	}
 	if loginOptions.IsJWTRequired() {
 		pswd, err = getValidRefreshToken(r)
 		if err != nil {
@@ -23,19 +27,20 @@ func (auth *magicLink) SignIn(method DeliveryMethod, identifier, URI string, r *
 		}
 	}
 
-	_, err = auth.client.DoPostRequest(composeMagicLinkSignInURL(method), newMagicLinkAuthenticationRequestBody(identifier, URI, false, loginOptions), nil, pswd)
+	_, err = auth.clientFor(r).DoPostRequest(composeMagicLinkSignInURL(method), newMagicLinkAuthenticationRequestBody(identifier, URI, false, loginOptions), nil, pswd)
 	return err
 }
 
 func (auth *magicLink) SignUp(method DeliveryMethod, identifier, URI string, user *User) error {
-	if user == nil {
-		user = &User{}
+	if err := verifySupportedDeliveryMethod(method, magicLinkDeliveryMethods); err != nil {
+		return err
 	}
-	if err := auth.verifyDeliveryMethod(method, identifier, user); err != nil {
+	user, err := auth.verifyDeliveryMethod(method, identifier, user)
+	if err != nil {
 		return err
 	}
 
-	_, err := auth.client.DoPostRequest(composeMagicLinkSignUpURL(method), newMagicLinkAuthenticationSignUpRequestBody(method, identifier, URI, user, false), nil, "")
+	_, err = auth.client.DoPostRequest(composeMagicLinkSignUpURL(method), newMagicLinkAuthenticationSignUpRequestBody(method, identifier, URI, user, false), nil, "")
 	return err
 }
 
@@ -43,6 +48,9 @@ func (auth *magicLink) SignUpOrIn(method DeliveryMethod, identifier, URI string)
 	if identifier == "" {
 		return errors.NewInvalidArgumentError("identifier")
 	}
+	if err := verifySupportedDeliveryMethod(method, magicLinkDeliveryMethods); err != nil {
+		return err
+	}
 	_, err := auth.client.DoPostRequest(composeMagicLinkSignUpOrInURL(method), newMagicLinkAuthenticationRequestBody(identifier, URI, false, nil), nil, "")
 	return err
 }
@@ -53,13 +61,16 @@ func (auth *magicLink) SignInCrossDevice(method DeliveryMethod, identifier, URI
 	if identifier == "" {
 		return nil, errors.NewInvalidArgumentError("identifier")
 	}
+	if err := verifySupportedDeliveryMethod(method, magicLinkDeliveryMethods); err != nil {
+		return nil, err
+	}
 	if loginOptions.IsJWTRequired() {
 		pswd, err = getValidRefreshToken(r)
 		if err != nil {
 			return nil, errors.InvalidStepupJwtError
 		}
 	}
-	httpResponse, err := auth.client.DoPostRequest(composeMagicLinkSignInURL(method), newMagicLinkAuthenticationRequestBody(identifier, URI, true, loginOptions), nil, pswd)
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeMagicLinkSignInURL(method), newMagicLinkAuthenticationRequestBody(identifier, URI, true, loginOptions), nil, pswd)
 	if err != nil {
 		return nil, err
 	}
@@ -67,10 +78,11 @@ func (auth *magicLink) SignInCrossDevice(method DeliveryMethod, identifier, URI
 }
 
 func (auth *magicLink) SignUpCrossDevice(method DeliveryMethod, identifier, URI string, user *User) (*MagicLinkResponse, error) {
-	if user == nil {
-		user = &User{}
+	if err := verifySupportedDeliveryMethod(method, magicLinkDeliveryMethods); err != nil {
+		return nil, err
 	}
-	if err := auth.verifyDeliveryMethod(method, identifier, user); err != nil {
+	user, err := auth.verifyDeliveryMethod(method, identifier, user)
+	if err != nil {
 		return nil, err
 	}
 
@@ -85,6 +97,9 @@ func (auth *magicLink) SignUpOrInCrossDevice(method DeliveryMethod, identifier,
 	if identifier == "" {
 		return nil, errors.NewInvalidArgumentError("identifier")
 	}
+	if err := verifySupportedDeliveryMethod(method, magicLinkDeliveryMethods); err != nil {
+		return nil, err
+	}
 	httpResponse, err := auth.client.DoPostRequest(composeMagicLinkSignUpOrInURL(method), newMagicLinkAuthenticationRequestBody(identifier, URI, true, nil), nil, "")
 	if err != nil {
 		return nil, err
@@ -104,6 +119,17 @@ func (auth *magicLink) GetSession(pendingRef string, w http.ResponseWriter) (*Au
 	return auth.generateAuthenticationInfo(httpResponse, w)
 }
 
+func (auth *magicLink) WaitForSession(ctx context.Context, pendingRef string, opts *WaitForSessionOptions) (*AuthenticationInfo, error) {
+	if pendingRef == "" {
+		return nil, errors.NewInvalidArgumentError("pendingRef")
+	}
+	return pollSession(ctx, opts, func(ctx context.Context) (*AuthenticationInfo, error) {
+		polling := &magicLink{authenticationsBase: auth.authenticationsBase}
+		polling.client = auth.client.WithContext(ctx)
+		return polling.GetSession(pendingRef, nil)
+	}, errors.MagicLinkUnauthorized, nil)
+}
+
 func (auth *magicLink) Verify(token string, w http.ResponseWriter) (*AuthenticationInfo, error) {
 	var err error
 
@@ -121,14 +147,15 @@ func (auth *magicLink) UpdateUserEmail(identifier, email, URI string, r *http.Re
 	if email == "" {
 		return errors.NewInvalidArgumentError("email")
 	}
-	if !emailRegex.MatchString(email) {
-		return errors.NewInvalidArgumentError("email")
+	email, err := auth.identifiers.NormalizeEmail(email)
+	if err != nil {
+		return err
 	}
 	pswd, err := getValidRefreshToken(r)
 	if err != nil {
 		return err
 	}
-	_, err = auth.client.DoPostRequest(composeUpdateUserEmailMagicLink(), newMagicLinkUpdateEmailRequestBody(identifier, email, URI, false), nil, pswd)
+	_, err = auth.clientFor(r).DoPostRequest(composeUpdateUserEmailMagicLink(), newMagicLinkUpdateEmailRequestBody(identifier, email, URI, false), nil, pswd)
 	return err
 }
 
@@ -139,14 +166,15 @@ func (auth *magicLink) UpdateUserEmailCrossDevice(identifier, email, URI string,
 	if email == "" {
 		return nil, errors.NewInvalidArgumentError("email")
 	}
-	if !emailRegex.MatchString(email) {
-		return nil, errors.NewInvalidArgumentError("email")
+	email, err := auth.identifiers.NormalizeEmail(email)
+	if err != nil {
+		return nil, err
 	}
 	pswd, err := getValidRefreshToken(r)
 	if err != nil {
 		return nil, err
 	}
-	httpResponse, err := auth.client.DoPostRequest(composeUpdateUserEmailMagicLink(), newMagicLinkUpdateEmailRequestBody(identifier, email, URI, true), nil, pswd)
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeUpdateUserEmailMagicLink(), newMagicLinkUpdateEmailRequestBody(identifier, email, URI, true), nil, pswd)
 	if err != nil {
 		return nil, err
 	}
@@ -160,8 +188,9 @@ func (auth *magicLink) UpdateUserPhone(method DeliveryMethod, identifier, phone,
 	if phone == "" {
 		return errors.NewInvalidArgumentError("phone")
 	}
-	if !phoneRegex.MatchString(phone) {
-		return errors.NewInvalidArgumentError("phone")
+	phone, err := auth.identifiers.NormalizePhone(phone)
+	if err != nil {
+		return err
 	}
 	if method != MethodSMS && method != MethodWhatsApp {
 		return errors.NewInvalidArgumentError("method")
@@ -170,7 +199,7 @@ func (auth *magicLink) UpdateUserPhone(method DeliveryMethod, identifier, phone,
 	if err != nil {
 		return err
 	}
-	_, err = auth.client.DoPostRequest(composeUpdateUserPhoneMagiclink(method), newMagicLinkUpdatePhoneRequestBody(identifier, phone, URI, false), nil, pswd)
+	_, err = auth.clientFor(r).DoPostRequest(composeUpdateUserPhoneMagiclink(method), newMagicLinkUpdatePhoneRequestBody(identifier, phone, URI, false), nil, pswd)
 	return err
 }
 
@@ -181,8 +210,9 @@ func (auth *magicLink) UpdateUserPhoneCrossDevice(method DeliveryMethod, identif
 	if phone == "" {
 		return nil, errors.NewInvalidArgumentError("phone")
 	}
-	if !phoneRegex.MatchString(phone) {
-		return nil, errors.NewInvalidArgumentError("phone")
+	phone, err := auth.identifiers.NormalizePhone(phone)
+	if err != nil {
+		return nil, err
 	}
 	if method != MethodSMS && method != MethodWhatsApp {
 		return nil, errors.NewInvalidArgumentError("method")
@@ -191,7 +221,7 @@ func (auth *magicLink) UpdateUserPhoneCrossDevice(method DeliveryMethod, identif
 	if err != nil {
 		return nil, err
 	}
-	httpResponse, err := auth.client.DoPostRequest(composeUpdateUserPhoneMagiclink(method), newMagicLinkUpdatePhoneRequestBody(identifier, phone, URI, true), nil, pswd)
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeUpdateUserPhoneMagiclink(method), newMagicLinkUpdatePhoneRequestBody(identifier, phone, URI, true), nil, pswd)
 	if err != nil {
 		return nil, err
 	}
@@ -419,7 +419,7 @@ func TestSignUpMagicLinkSMS(t *testing.T) {
 
 		body, err := readBodyMap(r)
 		require.NoError(t, err)
-		assert.EqualValues(t, phone, body["phone"])
+		assert.EqualValues(t, "+"+phone, body["phone"])
 		assert.EqualValues(t, uri, body["URI"])
 		assert.EqualValues(t, phone, body["externalId"])
 		assert.EqualValues(t, "test", body["user"].(map[string]interface{})["name"])
@@ -437,7 +437,7 @@ func TestSignUpMagicLinkWhatsApp(t *testing.T) {
 
 		body, err := readBodyMap(r)
 		require.NoError(t, err)
-		assert.EqualValues(t, phone, body["whatsapp"])
+		assert.EqualValues(t, "+"+phone, body["whatsapp"])
 		assert.EqualValues(t, uri, body["URI"])
 		assert.EqualValues(t, phone, body["externalId"])
 		assert.EqualValues(t, "test", body["user"].(map[string]interface{})["name"])
@@ -672,3 +672,14 @@ func TestUpdatePhoneMagicLinkFailures(t *testing.T) {
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, errors.RefreshTokenError)
 }
+
+func TestMagicLinkUnsupportedMethod(t *testing.T) {
+	a, err := newTestAuth(nil, nil)
+	require.NoError(t, err)
+	err = a.MagicLink().SignIn(MethodVoice, "+943248329844", "", nil, nil)
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+	err = a.MagicLink().SignUp(MethodEmbedded, "my username", "", nil)
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+	_, err = a.MagicLink().SignUpOrInCrossDevice(MethodVoice, "+943248329844", "")
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+}
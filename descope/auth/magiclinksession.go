@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/logger"
+	"github.com/descope/go-sdk/descope/utils"
+)
+
+const (
+	defaultWaitForSessionInterval    = 2 * time.Second
+	defaultWaitForSessionMaxInterval = 10 * time.Second
+	defaultWaitForSessionTimeout     = 10 * time.Minute
+	defaultSessionHandlerTimeout     = 30 * time.Second
+
+	pendingRefQueryParam = "pendingRef"
+	eventStreamMediaType = "text/event-stream"
+)
+
+// pollSession calls getSession until it returns anything but the pendingErr error, or until the context is done.
+// getSession is given the context of the polling, which should be used for its requests so that they are canceled
+// with it.
+// onPending, when given, is called after every poll that found the session still pending.
+func pollSession(ctx context.Context, opts *WaitForSessionOptions, getSession func(ctx context.Context) (*AuthenticationInfo, error), pendingErr error, onPending func()) (*AuthenticationInfo, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts == nil {
+		opts = &WaitForSessionOptions{}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultWaitForSessionInterval
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultWaitForSessionMaxInterval
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultWaitForSessionTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		info, err := getSession(ctx)
+		if err != pendingErr {
+			if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+				// the request was canceled with the context
+				return nil, ctxErr
+			}
+			return info, err
+		}
+		if onPending != nil {
+			onPending()
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if opts.Backoff > 1 {
+			interval = time.Duration(float64(interval) * opts.Backoff)
+			if interval > maxInterval {
+				interval = maxInterval
+			}
+		}
+	}
+}
+
+// NewMagicLinkSessionHandler - returns a handler browsers can use to wait for a cross device magic link session,
+// instead of polling GetSession themselves. The pending reference is taken from the "pendingRef" query parameter.
+//
+// When the request accepts "text/event-stream", the handler streams server-sent events, a "pending" event
+// after every poll, followed by either a "session" event with the authentication info (including the session JWT,
+// as cookies cannot be set once the stream started) or an "error" event.
+//
+// Otherwise the handler long-polls, on success it writes the session cookies and the authentication info as JSON,
+// responds 202 (Accepted) when the session is still pending once the timeout elapsed so the browser can try again,
+// and 401 (Unauthorized) on any other failure.
+// opts are optional, the timeout defaults to 30 seconds, see auth/WaitForSessionOptions for the other defaults.
+func NewMagicLinkSessionHandler(magicLink MagicLink, opts *WaitForSessionOptions) http.Handler {
+	handlerOpts := WaitForSessionOptions{Timeout: defaultSessionHandlerTimeout}
+	if opts != nil {
+		handlerOpts = *opts
+		if handlerOpts.Timeout <= 0 {
+			handlerOpts.Timeout = defaultSessionHandlerTimeout
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pendingRef := r.URL.Query().Get(pendingRefQueryParam)
+		if pendingRef == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(r.Header.Get("Accept"), eventStreamMediaType) {
+			streamSession(magicLink, pendingRef, &handlerOpts, w, r)
+		} else {
+			longPollSession(magicLink, pendingRef, &handlerOpts, w, r)
+		}
+	})
+}
+
+func longPollSession(magicLink MagicLink, pendingRef string, opts *WaitForSessionOptions, w http.ResponseWriter, r *http.Request) {
+	info, err := pollSession(r.Context(), opts, func(context.Context) (*AuthenticationInfo, error) {
+		return magicLink.GetSession(pendingRef, w)
+	}, errors.MagicLinkUnauthorized, nil)
+	if err == context.DeadlineExceeded {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if err != nil {
+		logger.LogDebug("failed waiting for magic link session [%s]", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, err := utils.Marshal(info)
+	if err != nil {
+		logger.LogError("failed to marshal magic link session", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+func streamSession(magicLink MagicLink, pendingRef string, opts *WaitForSessionOptions, w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		longPollSession(magicLink, pendingRef, opts, w, r)
+		return
+	}
+	w.Header().Set("Content-Type", eventStreamMediaType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	info, err := pollSession(r.Context(), opts, func(context.Context) (*AuthenticationInfo, error) {
+		return magicLink.GetSession(pendingRef, nil)
+	}, errors.MagicLinkUnauthorized, func() {
+		writeEvent(w, "pending", []byte("{}"))
+		flusher.Flush()
+	})
+	if err == nil {
+		var data []byte
+		if data, err = utils.Marshal(info); err == nil {
+			writeEvent(w, "session", data)
+		}
+	}
+	if err != nil {
+		logger.LogDebug("failed waiting for magic link session [%s]", err)
+		data, _ := utils.Marshal(map[string]string{"message": err.Error()})
+		writeEvent(w, "error", data)
+	}
+	flusher.Flush()
+}
+
+func writeEvent(w http.ResponseWriter, event string, data []byte) {
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testWaitForSessionOptions = &WaitForSessionOptions{Interval: time.Millisecond, Backoff: 2, MaxInterval: 5 * time.Millisecond, Timeout: time.Second}
+
+// failingMagicLink - a magic link service whose GetSession fails with the given error
+type failingMagicLink struct {
+	MagicLink
+	err error
+}
+
+func (m failingMagicLink) GetSession(string, http.ResponseWriter) (*AuthenticationInfo, error) {
+	return nil, m.err
+}
+
+func doPendingSession(pendingPolls int, calls *int) func(r *http.Request) (*http.Response, error) {
+	return func(r *http.Request) (*http.Response, error) {
+		*calls++
+		if *calls <= pendingPolls {
+			return &http.Response{StatusCode: http.StatusUnauthorized}, nil
+		}
+		return DoOk(nil)(r)
+	}
+}
+
+func TestWaitForSession(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, doPendingSession(3, &calls))
+	require.NoError(t, err)
+	info, err := a.MagicLink().WaitForSession(context.Background(), "pending_ref", testWaitForSessionOptions)
+	require.NoError(t, err)
+	assert.NotEmpty(t, info.SessionToken.JWT)
+	assert.EqualValues(t, 4, calls)
+}
+
+func TestWaitForSessionTimeout(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, doPendingSession(1000, &calls))
+	require.NoError(t, err)
+	_, err = a.MagicLink().WaitForSession(context.Background(), "pending_ref", &WaitForSessionOptions{Interval: time.Millisecond, Timeout: 20 * time.Millisecond})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Greater(t, calls, 1)
+}
+
+func TestWaitForSessionCanceled(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, doPendingSession(1000, &calls))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = a.MagicLink().WaitForSession(ctx, "pending_ref", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, calls)
+}
+
+// doSlowSession - a session request that only returns when its context is done, or after a long while
+func doSlowSession(r *http.Request) (*http.Response, error) {
+	select {
+	case <-r.Context().Done():
+		return nil, r.Context().Err()
+	case <-time.After(10 * time.Second):
+		return DoOk(nil)(r)
+	}
+}
+
+func TestWaitForSessionCanceledDuringRequest(t *testing.T) {
+	a, err := newTestAuth(nil, doSlowSession)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = a.MagicLink().WaitForSession(ctx, "pending_ref", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWaitForSessionError(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, func(r *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusBadGateway}, nil
+	})
+	require.NoError(t, err)
+	_, err = a.MagicLink().WaitForSession(context.Background(), "pending_ref", testWaitForSessionOptions)
+	require.Error(t, err)
+	assert.EqualValues(t, 1, calls)
+
+	_, err = a.MagicLink().WaitForSession(context.Background(), "", nil)
+	require.Error(t, err)
+}
+
+func TestMagicLinkSessionHandlerLongPoll(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, doPendingSession(2, &calls))
+	require.NoError(t, err)
+	handler := NewMagicLinkSessionHandler(a.MagicLink(), testWaitForSessionOptions)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/session?pendingRef=pending_ref", nil))
+	res := w.Result()
+	assert.EqualValues(t, http.StatusOK, res.StatusCode)
+	assert.NotNil(t, findCookie(res, SessionCookieName))
+	assert.Contains(t, w.Body.String(), `"jwt"`)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/session", nil))
+	assert.EqualValues(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func TestMagicLinkSessionHandlerLongPollPending(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, doPendingSession(1000, &calls))
+	require.NoError(t, err)
+	handler := NewMagicLinkSessionHandler(a.MagicLink(), &WaitForSessionOptions{Interval: time.Millisecond, Timeout: 10 * time.Millisecond})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/session?pendingRef=pending_ref", nil))
+	assert.EqualValues(t, http.StatusAccepted, w.Result().StatusCode)
+
+	handler = NewMagicLinkSessionHandler(failingMagicLink{err: errors.UnauthorizedError}, nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/session?pendingRef=pending_ref", nil))
+	assert.EqualValues(t, http.StatusUnauthorized, w.Result().StatusCode)
+}
+
+func TestMagicLinkSessionHandlerEventStream(t *testing.T) {
+	calls := 0
+	a, err := newTestAuth(nil, doPendingSession(2, &calls))
+	require.NoError(t, err)
+	handler := NewMagicLinkSessionHandler(a.MagicLink(), testWaitForSessionOptions)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/session?pendingRef=pending_ref", nil)
+	req.Header.Set("Accept", eventStreamMediaType)
+	handler.ServeHTTP(w, req)
+	assert.EqualValues(t, eventStreamMediaType, w.Result().Header.Get("Content-Type"))
+	body := w.Body.String()
+	assert.EqualValues(t, 2, strings.Count(body, "event: pending\n"))
+	assert.Contains(t, body, "event: session\ndata: {")
+
+	handler = NewMagicLinkSessionHandler(failingMagicLink{err: errors.UnauthorizedError}, nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), "event: error\n")
+}
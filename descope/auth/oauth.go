@@ -2,6 +2,7 @@ package auth
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/descope/go-sdk/descope/api"
 	"github.com/descope/go-sdk/descope/errors"
@@ -18,12 +19,35 @@ type oauthStartResponse struct {
 }
 
 func (auth *oauth) Start(provider OAuthProvider, redirectURL string, r *http.Request, loginOptions *LoginOptions, w http.ResponseWriter) (url string, err error) {
+	return auth.StartWithOptions(provider, redirectURL, r, loginOptions, nil, w)
+}
+
+func (auth *oauth) StartWithOptions(provider OAuthProvider, redirectURL string, r *http.Request, loginOptions *LoginOptions, oauthOptions *OAuthOptions, w http.ResponseWriter) (url string, err error) {
+	if provider == "" {
+		return "", errors.NewInvalidArgumentError("provider")
+	}
 	m := map[string]string{
 		"provider": string(provider),
 	}
+	if oauthOptions != nil && oauthOptions.BindState {
+		if redirectURL, err = bindRedirectState(redirectURL, m, w); err != nil {
+			return "", err
+		}
+	}
 	if len(redirectURL) > 0 {
 		m["redirectURL"] = redirectURL
 	}
+	if oauthOptions != nil {
+		if len(oauthOptions.Scopes) > 0 {
+			m["scopes"] = strings.Join(oauthOptions.Scopes, " ")
+		}
+		if len(oauthOptions.Prompt) > 0 {
+			m["prompt"] = strings.Join(oauthOptions.Prompt, " ")
+		}
+		if len(oauthOptions.LoginHint) > 0 {
+			m["loginHint"] = oauthOptions.LoginHint
+		}
+	}
 	var pswd string
 	if loginOptions.IsJWTRequired() {
 		pswd, err = getValidRefreshToken(r)
@@ -32,7 +56,7 @@ func (auth *oauth) Start(provider OAuthProvider, redirectURL string, r *http.Req
 		}
 	}
 
-	httpResponse, err := auth.client.DoPostRequest(composeOAuthURL(), loginOptions, &api.HTTPRequest{QueryParams: m}, pswd)
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeOAuthURL(), loginOptions, &api.HTTPRequest{QueryParams: m}, pswd)
 	if err != nil {
 		return
 	}
@@ -54,3 +78,7 @@ func (auth *oauth) Start(provider OAuthProvider, redirectURL string, r *http.Req
 func (auth *oauth) ExchangeToken(code string, w http.ResponseWriter) (*AuthenticationInfo, error) {
 	return auth.exchangeToken(code, composeOAuthExchangeTokenURL(), w)
 }
+
+func (auth *oauth) ExchangeTokenFromRequest(r *http.Request, w http.ResponseWriter) (*AuthenticationInfo, error) {
+	return auth.exchangeTokenFromRequest(r, composeOAuthExchangeTokenURL(), w)
+}
@@ -112,6 +112,44 @@ func TestExchangeTokenOAuth(t *testing.T) {
 	assert.True(t, authInfo.FirstSeen)
 }
 
+func TestOAuthStartWithOptions(t *testing.T) {
+	uri := "http://test.me"
+	provider := OAuthProvider("my-oidc")
+	a, err := newTestAuth(nil, DoRedirect(uri, func(r *http.Request) {
+		assert.EqualValues(t, string(provider), r.URL.Query().Get("provider"))
+		assert.EqualValues(t, "email profile", r.URL.Query().Get("scopes"))
+		assert.EqualValues(t, "consent", r.URL.Query().Get("prompt"))
+		assert.EqualValues(t, "a@b.com", r.URL.Query().Get("loginHint"))
+	}))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	urlStr, err := a.OAuth().StartWithOptions(provider, "", nil, nil, &OAuthOptions{Scopes: []string{"email", "profile"}, Prompt: []string{"consent"}, LoginHint: "a@b.com"}, w)
+	require.NoError(t, err)
+	assert.EqualValues(t, uri, urlStr)
+	assert.EqualValues(t, http.StatusTemporaryRedirect, w.Result().StatusCode)
+}
+
+func TestOAuthStartEmptyProvider(t *testing.T) {
+	a, err := newTestAuth(nil, nil)
+	require.NoError(t, err)
+	_, err = a.OAuth().Start("", "", nil, nil, nil)
+	require.Error(t, err)
+}
+
+func TestExchangeTokenOAuthProviderToken(t *testing.T) {
+	a, err := newTestAuth(nil, DoOkWithBody(nil, &JWTResponse{
+		RefreshJwt:    jwtTokenValid,
+		ProviderToken: &ProviderToken{Provider: string(OAuthSlack), AccessToken: "access", Scopes: []string{"chat:write"}},
+	}))
+	require.NoError(t, err)
+	authInfo, err := a.OAuth().ExchangeToken("code", nil)
+	require.NoError(t, err)
+	require.NotNil(t, authInfo.ProviderToken)
+	assert.EqualValues(t, OAuthSlack, authInfo.ProviderToken.Provider)
+	assert.EqualValues(t, "access", authInfo.ProviderToken.AccessToken)
+	assert.EqualValues(t, []string{"chat:write"}, authInfo.ProviderToken.Scopes)
+}
+
 func TestExchangeTokenSAML(t *testing.T) {
 	code := "code"
 	a, err := newTestAuth(nil, func(r *http.Request) (*http.Response, error) {
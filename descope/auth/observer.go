@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	goErrors "errors"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+// ValidationOutcome - the outcome of a session validation
+type ValidationOutcome string
+
+const (
+	// ValidationValid - the session token is valid
+	ValidationValid ValidationOutcome = "valid"
+	// ValidationRefreshed - the session was refreshed with a valid refresh token
+	ValidationRefreshed ValidationOutcome = "refreshed"
+	// ValidationExpired - the token has expired
+	ValidationExpired ValidationOutcome = "expired"
+	// ValidationInvalidSignature - the token is not signed by any of the public keys of the project
+	ValidationInvalidSignature ValidationOutcome = "invalid_signature"
+	// ValidationMissingKey - the public key of the token was not found or could not be fetched
+	ValidationMissingKey ValidationOutcome = "missing_key"
+	// ValidationRefreshFailed - the refresh token is valid but refreshing the session failed
+	ValidationRefreshFailed ValidationOutcome = "refresh_failed"
+	// ValidationInvalid - the token is malformed or its claims are invalid
+	ValidationInvalid ValidationOutcome = "invalid"
+)
+
+// ValidationEvent - describes a session validation
+type ValidationEvent struct {
+	// Context - the context of the validated request, context.Background() when validating tokens
+	Context context.Context
+	// ProjectID - the project of the client that validated the session
+	ProjectID string
+	// Start - when the validation started
+	Start time.Time
+	// Duration - how long the validation took, including refreshing the session and fetching the public keys
+	Duration time.Duration
+	// Outcome - the outcome of the validation
+	Outcome ValidationOutcome
+	// RefreshTriggered - whether the session was refreshed, or refreshing it was attempted
+	RefreshTriggered bool
+	// KeysFetched - whether the public keys were fetched during the validation, a cache miss, or the cached or pinned
+	// keys were used
+	KeysFetched bool
+	// Err - the error returned by the validation, if any
+	Err error
+}
+
+// KeysEvent - describes fetching the public keys of a project from Descope
+type KeysEvent struct {
+	// ProjectID - the project of the fetched keys
+	ProjectID string
+	// Start - when fetching the keys started
+	Start time.Time
+	// Duration - how long fetching the keys took
+	Duration time.Duration
+	// Keys - the number of fetched keys
+	Keys int
+	// Err - the error fetching the keys, if any
+	Err error
+}
+
+// ValidationObserver - observes the session validations of a client and the public key fetches they trigger, such as to
+// trace or measure them. Observers are called synchronously and must not block.
+type ValidationObserver interface {
+	ObserveValidation(event ValidationEvent)
+	ObserveKeysFetch(event KeysEvent)
+}
+
+// validationOutcome returns the outcome of validating the given JWT
+func validationOutcome(JWT string, err error, lookup *keyLookup) ValidationOutcome {
+	var validationErr jwt.ValidationError
+	switch {
+	case err == nil:
+		return ValidationValid
+	case lookup.err != nil:
+		return ValidationMissingKey
+	case goErrors.Is(err, jwt.ErrTokenExpired()):
+		return ValidationExpired
+	case goErrors.As(err, &validationErr):
+		return ValidationInvalid
+	}
+	// a well formed token that failed verification
+	if _, parseErr := jws.Parse([]byte(JWT)); parseErr != nil {
+		return ValidationInvalid
+	}
+	return ValidationInvalidSignature
+}
@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/mocks"
+)
+
+type testObserver struct {
+	validations []ValidationEvent
+	fetches     []KeysEvent
+}
+
+func (o *testObserver) ObserveValidation(event ValidationEvent) {
+	o.validations = append(o.validations, event)
+}
+
+func (o *testObserver) ObserveKeysFetch(event KeysEvent) {
+	o.fetches = append(o.fetches, event)
+}
+
+func (o *testObserver) last(t *testing.T) ValidationEvent {
+	require.NotEmpty(t, o.validations)
+	return o.validations[len(o.validations)-1]
+}
+
+func signExpired(t *testing.T, k *testSigningKey) string {
+	token := jwt.New()
+	require.NoError(t, token.Set(jwt.SubjectKey, "someuser"))
+	require.NoError(t, token.Set(jwt.ExpirationKey, time.Now().Add(-time.Hour)))
+	signed, err := jwt.Sign(token, jwt.WithKey(k.alg, k.private))
+	require.NoError(t, err)
+	return string(signed)
+}
+
+func TestObserveValidation(t *testing.T) {
+	k := newTestSigningKey(t, "k1", false)
+	observer := &testObserver{}
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", PublicKey: k.jwk(t), Observer: observer}, nil, nil)
+	require.NoError(t, err)
+
+	ok, _, err := a.ValidateSessionTokens(k.sign(t), "")
+	require.NoError(t, err)
+	require.True(t, ok)
+	event := observer.last(t)
+	assert.EqualValues(t, "a", event.ProjectID)
+	assert.EqualValues(t, ValidationValid, event.Outcome)
+	assert.False(t, event.RefreshTriggered)
+	assert.False(t, event.KeysFetched)
+	assert.NoError(t, event.Err)
+	assert.NotNil(t, event.Context)
+
+	_, _, err = a.ValidateSessionTokens(signExpired(t, k), "")
+	require.Error(t, err)
+	assert.EqualValues(t, ValidationExpired, observer.last(t).Outcome)
+	assert.Equal(t, err, observer.last(t).Err)
+
+	// same key ID, different key
+	forged := newTestSigningKey(t, "k1", false)
+	_, _, err = a.ValidateSessionTokens(forged.sign(t), "")
+	require.Error(t, err)
+	assert.EqualValues(t, ValidationInvalidSignature, observer.last(t).Outcome)
+
+	_, _, err = a.ValidateSessionTokens(newTestSigningKey(t, "k2", false).sign(t), "")
+	assert.ErrorIs(t, err, errors.NoPublicKeyError)
+	assert.EqualValues(t, ValidationMissingKey, observer.last(t).Outcome)
+
+	_, _, err = a.ValidateSessionTokens("not a jwt", "")
+	require.Error(t, err)
+	assert.EqualValues(t, ValidationInvalid, observer.last(t).Outcome)
+	assert.Empty(t, observer.fetches)
+}
+
+func TestObserveValidationRefresh(t *testing.T) {
+	observer := &testObserver{}
+	refreshFails := false
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", PublicKey: publicKey, Observer: observer}, nil, func(r *http.Request) (*http.Response, error) {
+		if refreshFails {
+			return &http.Response{StatusCode: http.StatusInternalServerError}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(mockAuthSessionBody))}, nil
+	})
+	require.NoError(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.AddCookie(&http.Cookie{Name: RefreshCookieName, Value: jwtTokenValid})
+	ok, _, err := a.ValidateSession(request, httptest.NewRecorder())
+	require.NoError(t, err)
+	require.True(t, ok)
+	event := observer.last(t)
+	assert.EqualValues(t, ValidationRefreshed, event.Outcome)
+	assert.True(t, event.RefreshTriggered)
+	assert.Equal(t, request.Context(), event.Context)
+
+	refreshFails = true
+	_, _, err = a.ValidateSession(request, nil)
+	assert.ErrorIs(t, err, errors.FailedToRefreshTokenError)
+	assert.EqualValues(t, ValidationRefreshFailed, observer.last(t).Outcome)
+	assert.True(t, observer.last(t).RefreshTriggered)
+}
+
+func TestObserveKeysFetch(t *testing.T) {
+	observer := &testObserver{}
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", Observer: observer}, nil, mocks.Do(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(fmt.Sprintf("[%s]", publicKey)))}, nil
+	}))
+	require.NoError(t, err)
+
+	ok, _, err := a.ValidateSessionTokens(jwtTokenValid, "")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, observer.last(t).KeysFetched)
+	require.Len(t, observer.fetches, 1)
+	assert.EqualValues(t, "a", observer.fetches[0].ProjectID)
+	assert.EqualValues(t, 1, observer.fetches[0].Keys)
+	assert.NoError(t, observer.fetches[0].Err)
+
+	// the cached keys are used
+	ok, _, err = a.ValidateSessionTokens(jwtTokenValid, "")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.False(t, observer.last(t).KeysFetched)
+	assert.Len(t, observer.fetches, 1)
+}
@@ -3,49 +3,98 @@ package auth
 import (
 	"net/http"
 
+	"github.com/descope/go-sdk/descope/api"
 	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/utils"
 )
 
 type otp struct {
 	authenticationsBase
 }
 
+type otpEmbeddedResponse struct {
+	Code string `json:"code"`
+}
+
 func (auth *otp) SignIn(method DeliveryMethod, identifier string, r *http.Request, loginOptions *LoginOptions) error {
+	if err := verifySupportedDeliveryMethod(method, otpDeliveryMethods); err != nil {
+		return err
+	}
+	_, err := auth.signIn(method, identifier, r, loginOptions)
+	return err
+}
+
+func (auth *otp) SignInEmbedded(identifier string, r *http.Request, loginOptions *LoginOptions) (string, error) {
+	return getEmbeddedCode(auth.signIn(MethodEmbedded, identifier, r, loginOptions))
+}
+
+func (auth *otp) signIn(method DeliveryMethod, identifier string, r *http.Request, loginOptions *LoginOptions) (*api.HTTPResponse, error) {
 	var pswd string
 	var err error
 	if identifier == "" {
-		return errors.NewInvalidArgumentError("identifier")
+		return nil, errors.NewInvalidArgumentError("identifier")
 	}
 	if loginOptions.IsJWTRequired() {
 		pswd, err = getValidRefreshToken(r)
 		if err != nil {
-			return errors.InvalidStepupJwtError
+			return nil, errors.InvalidStepupJwtError
 		}
 	}
+	return auth.clientFor(r).DoPostRequest(composeSignInURL(method), newSignInRequestBody(identifier, loginOptions), nil, pswd)
+}
 
-	_, err = auth.client.DoPostRequest(composeSignInURL(method), newSignInRequestBody(identifier, loginOptions), nil, pswd)
+func (auth *otp) SignUp(method DeliveryMethod, identifier string, user *User) error {
+	if err := verifySupportedDeliveryMethod(method, otpDeliveryMethods); err != nil {
+		return err
+	}
+	_, err := auth.signUp(method, identifier, user)
 	return err
 }
 
-func (auth *otp) SignUp(method DeliveryMethod, identifier string, user *User) error {
-	if user == nil {
-		user = &User{}
+func (auth *otp) SignUpEmbedded(identifier string, user *User) (string, error) {
+	return getEmbeddedCode(auth.signUp(MethodEmbedded, identifier, user))
+}
+
+func (auth *otp) signUp(method DeliveryMethod, identifier string, user *User) (*api.HTTPResponse, error) {
+	user, err := auth.verifyDeliveryMethod(method, identifier, user)
+	if err != nil {
+		return nil, err
 	}
-	if err := auth.verifyDeliveryMethod(method, identifier, user); err != nil {
+	return auth.client.DoPostRequest(composeSignUpURL(method), newAuthenticationSignUpRequestBody(method, identifier, user), nil, "")
+}
+
+func (auth *otp) SignUpOrIn(method DeliveryMethod, identifier string) error {
+	if err := verifySupportedDeliveryMethod(method, otpDeliveryMethods); err != nil {
 		return err
 	}
-
-	_, err := auth.client.DoPostRequest(composeSignUpURL(method), newAuthenticationSignUpRequestBody(method, identifier, user), nil, "")
+	_, err := auth.signUpOrIn(method, identifier)
 	return err
 }
 
-func (auth *otp) SignUpOrIn(method DeliveryMethod, identifier string) error {
+func (auth *otp) SignUpOrInEmbedded(identifier string) (string, error) {
+	return getEmbeddedCode(auth.signUpOrIn(MethodEmbedded, identifier))
+}
+
+func (auth *otp) signUpOrIn(method DeliveryMethod, identifier string) (*api.HTTPResponse, error) {
 	if identifier == "" {
-		return errors.NewInvalidArgumentError("identifier")
+		return nil, errors.NewInvalidArgumentError("identifier")
 	}
+	return auth.client.DoPostRequest(composeSignUpOrInURL(method), newSignInRequestBody(identifier, nil), nil, "")
+}
 
-	_, err := auth.client.DoPostRequest(composeSignUpOrInURL(method), newSignInRequestBody(identifier, nil), nil, "")
-	return err
+// getEmbeddedCode returns the OTP code in the response of a request with MethodEmbedded
+func getEmbeddedCode(httpResponse *api.HTTPResponse, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	res := &otpEmbeddedResponse{}
+	if err = utils.Unmarshal([]byte(httpResponse.BodyStr), res); err != nil {
+		return "", err
+	}
+	if res.Code == "" {
+		return "", errors.InvalidEmbeddedCodeResponse
+	}
+	return res.Code, nil
 }
 
 func (auth *otp) VerifyCode(method DeliveryMethod, identifier string, code string, w http.ResponseWriter) (*AuthenticationInfo, error) {
@@ -53,18 +102,14 @@ func (auth *otp) VerifyCode(method DeliveryMethod, identifier string, code strin
 		return nil, errors.NewInvalidArgumentError("identifier")
 	}
 	if method == "" {
-		if phoneRegex.MatchString(identifier) {
-			method = MethodSMS
-		}
-
-		if emailRegex.MatchString(identifier) {
-			method = MethodEmail
-		}
-
-		if method == "" {
-			return nil, errors.NewInvalidArgumentError("method")
+		var err error
+		if method, identifier, err = auth.identifiers.ResolveDeliveryMethod(identifier); err != nil {
+			return nil, err
 		}
 	}
+	if err := verifySupportedDeliveryMethod(method, otpVerifyDeliveryMethods); err != nil {
+		return nil, err
+	}
 	httpResponse, err := auth.client.DoPostRequest(composeVerifyCodeURL(method), newAuthenticationVerifyRequestBody(identifier, code), nil, "")
 	if err != nil {
 		return nil, err
@@ -79,14 +124,15 @@ func (auth *otp) UpdateUserEmail(identifier, email string, r *http.Request) erro
 	if email == "" {
 		return errors.NewInvalidArgumentError("email")
 	}
-	if !emailRegex.MatchString(email) {
-		return errors.NewInvalidArgumentError("email")
+	email, err := auth.identifiers.NormalizeEmail(email)
+	if err != nil {
+		return err
 	}
 	pswd, err := getValidRefreshToken(r)
 	if err != nil {
 		return err
 	}
-	_, err = auth.client.DoPostRequest(composeUpdateUserEmailOTP(), newOTPUpdateEmailRequestBody(identifier, email), nil, pswd)
+	_, err = auth.clientFor(r).DoPostRequest(composeUpdateUserEmailOTP(), newOTPUpdateEmailRequestBody(identifier, email), nil, pswd)
 	return err
 }
 
@@ -97,8 +143,9 @@ func (auth *otp) UpdateUserPhone(method DeliveryMethod, identifier, phone string
 	if phone == "" {
 		return errors.NewInvalidArgumentError("phone")
 	}
-	if !phoneRegex.MatchString(phone) {
-		return errors.NewInvalidArgumentError("phone")
+	phone, err := auth.identifiers.NormalizePhone(phone)
+	if err != nil {
+		return err
 	}
 	if method != MethodSMS && method != MethodWhatsApp {
 		return errors.NewInvalidArgumentError("method")
@@ -107,6 +154,6 @@ func (auth *otp) UpdateUserPhone(method DeliveryMethod, identifier, phone string
 	if err != nil {
 		return err
 	}
-	_, err = auth.client.DoPostRequest(composeUpdateUserPhoneOTP(method), newOTPUpdatePhoneRequestBody(identifier, phone), nil, pswd)
+	_, err = auth.clientFor(r).DoPostRequest(composeUpdateUserPhoneOTP(method), newOTPUpdatePhoneRequestBody(identifier, phone), nil, pswd)
 	return err
 }
@@ -72,7 +72,7 @@ func TestSignUpSMS(t *testing.T) {
 
 		body, err := readBodyMap(r)
 		require.NoError(t, err)
-		assert.EqualValues(t, phone, body["phone"])
+		assert.EqualValues(t, "+"+phone, body["phone"])
 		assert.EqualValues(t, phone, body["externalId"])
 		assert.EqualValues(t, "test", body["user"].(map[string]interface{})["name"])
 	}))
@@ -88,7 +88,7 @@ func TestSignUpWhatsApp(t *testing.T) {
 
 		body, err := readBodyMap(r)
 		require.NoError(t, err)
-		assert.EqualValues(t, phone, body["whatsapp"])
+		assert.EqualValues(t, "+"+phone, body["whatsapp"])
 		assert.EqualValues(t, phone, body["externalId"])
 		assert.EqualValues(t, "test", body["user"].(map[string]interface{})["name"])
 	}))
@@ -97,6 +97,87 @@ func TestSignUpWhatsApp(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSignUpVoice(t *testing.T) {
+	phone := "+943248329844"
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, composeSignUpURL(MethodVoice), r.URL.RequestURI())
+
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, phone, body["phone"])
+		assert.EqualValues(t, phone, body["externalId"])
+	}))
+	require.NoError(t, err)
+	err = a.OTP().SignUp(MethodVoice, phone, &User{Name: "test"})
+	require.NoError(t, err)
+
+	err = a.OTP().SignUp(MethodVoice, "my username", nil)
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+}
+
+func TestSignUpEmbedded(t *testing.T) {
+	identifier := "my username"
+	a, err := newTestAuth(nil, DoOkWithBody(func(r *http.Request) {
+		assert.EqualValues(t, composeSignUpURL(MethodEmbedded), r.URL.RequestURI())
+
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, identifier, body["externalId"])
+		assert.Nil(t, body["email"])
+		assert.Nil(t, body["phone"])
+	}, otpEmbeddedResponse{Code: "123456"}))
+	require.NoError(t, err)
+	code, err := a.OTP().SignUpEmbedded(identifier, &User{Name: "test"})
+	require.NoError(t, err)
+	assert.EqualValues(t, "123456", code)
+
+	_, err = a.OTP().SignUpEmbedded("", nil)
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+}
+
+func TestOTPVoiceAndEmbedded(t *testing.T) {
+	var calls []string
+	a, err := newTestAuth(nil, DoOkWithBody(func(r *http.Request) {
+		calls = append(calls, r.URL.RequestURI())
+	}, otpEmbeddedResponse{Code: "4444"}))
+	require.NoError(t, err)
+	require.NoError(t, a.OTP().SignIn(MethodVoice, "+943248329844", nil, nil))
+	code, err := a.OTP().SignUpOrInEmbedded("my username")
+	require.NoError(t, err)
+	assert.EqualValues(t, "4444", code)
+	code, err = a.OTP().SignInEmbedded("my username", nil, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, "4444", code)
+	_, err = a.OTP().VerifyCode(MethodEmbedded, "my username", code, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{composeSignInURL(MethodVoice), composeSignUpOrInURL(MethodEmbedded), composeSignInURL(MethodEmbedded), composeVerifyCodeURL(MethodEmbedded)}, calls)
+}
+
+func TestOTPEmbeddedMissingCode(t *testing.T) {
+	a, err := newTestAuth(nil, DoOk(nil))
+	require.NoError(t, err)
+	_, err = a.OTP().SignInEmbedded("my username", nil, nil)
+	assert.ErrorIs(t, err, errors.InvalidEmbeddedCodeResponse)
+}
+
+func TestOTPUnsupportedMethod(t *testing.T) {
+	a, err := newTestAuth(nil, nil)
+	require.NoError(t, err)
+	err = a.OTP().SignIn("fax", "+943248329844", nil, nil)
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+	err = a.OTP().SignUp("fax", "+943248329844", nil)
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+	err = a.OTP().SignUpOrIn("fax", "+943248329844")
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+	_, err = a.OTP().VerifyCode("fax", "+943248329844", "4444", nil)
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+	// the embedded code can only be returned by the Embedded functions
+	err = a.OTP().SignIn(MethodEmbedded, "my username", nil, nil)
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+	err = a.OTP().SignUpOrIn(MethodEmbedded, "my username")
+	assert.EqualValues(t, errors.BadRequestErrorCode, err.(*errors.WebError).Code)
+}
+
 func TestSignUpOrInWhatsApp(t *testing.T) {
 	externalID := "943248329844"
 	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
@@ -287,6 +368,37 @@ func TestVerifyCodeWhatsApp(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestVerifyCodeVoice(t *testing.T) {
+	phone := "943248329844"
+	code := "4914"
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, composeVerifyCodeURL(MethodVoice), r.URL.RequestURI())
+
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, phone, body["externalId"])
+		assert.EqualValues(t, code, body["code"])
+	}))
+	require.NoError(t, err)
+	_, err = a.OTP().VerifyCode(MethodVoice, phone, code, nil)
+	require.NoError(t, err)
+}
+
+func TestVerifyCodeWhatsAppNotDetected(t *testing.T) {
+	// a phone number resolves to SMS, so a code sent with WhatsApp is verified with the method given explicitly
+	phone := "+972541234567"
+	var routes []string
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		routes = append(routes, r.URL.RequestURI())
+	}))
+	require.NoError(t, err)
+	_, err = a.OTP().VerifyCode("", phone, "4914", nil)
+	require.NoError(t, err)
+	_, err = a.OTP().VerifyCode(MethodWhatsApp, phone, "4914", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{composeVerifyCodeURL(MethodSMS), composeVerifyCodeURL(MethodWhatsApp)}, routes)
+}
+
 func TestVerifyCodeEmailResponseOption(t *testing.T) {
 	email := "test@email.com"
 	code := "4914"
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/errors"
+)
+
+type password struct {
+	authenticationsBase
+}
+
+func (auth *password) SignUp(identifier string, user *User, password string, w http.ResponseWriter) (*AuthenticationInfo, error) {
+	if identifier == "" {
+		return nil, errors.NewInvalidArgumentError("identifier")
+	}
+	if password == "" {
+		return nil, errors.NewInvalidArgumentError("password")
+	}
+	if user == nil {
+		user = &User{}
+	}
+	httpResponse, err := auth.client.DoPostRequest(composeSignUpPasswordURL(), newPasswordSignUpRequestBody(identifier, user, password), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return auth.generateAuthenticationInfo(httpResponse, w)
+}
+
+func (auth *password) SignIn(identifier, password string, r *http.Request, loginOptions *LoginOptions, w http.ResponseWriter) (*AuthenticationInfo, error) {
+	if identifier == "" {
+		return nil, errors.NewInvalidArgumentError("identifier")
+	}
+	if password == "" {
+		return nil, errors.NewInvalidArgumentError("password")
+	}
+	var pswd string
+	var err error
+	if loginOptions.IsJWTRequired() {
+		pswd, err = getValidRefreshToken(r)
+		if err != nil {
+			return nil, errors.InvalidStepupJwtError
+		}
+	}
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeSignInPasswordURL(), newPasswordSignInRequestBody(identifier, password, loginOptions), nil, pswd)
+	if err != nil {
+		return nil, err
+	}
+	return auth.generateAuthenticationInfo(httpResponse, w)
+}
+
+func (auth *password) SendPasswordReset(identifier, redirectURL string) error {
+	if identifier == "" {
+		return errors.NewInvalidArgumentError("identifier")
+	}
+	_, err := auth.client.DoPostRequest(composeSendPasswordResetURL(), newPasswordResetRequestBody(identifier, redirectURL), nil, "")
+	return err
+}
+
+func (auth *password) UpdateUserPassword(identifier, newPassword string, r *http.Request) error {
+	if identifier == "" {
+		return errors.NewInvalidArgumentError("identifier")
+	}
+	if newPassword == "" {
+		return errors.NewInvalidArgumentError("newPassword")
+	}
+	pswd, err := getValidRefreshToken(r)
+	if err != nil {
+		return err
+	}
+	_, err = auth.clientFor(r).DoPostRequest(composeUpdateUserPasswordURL(), newPasswordUpdateRequestBody(identifier, newPassword), nil, pswd)
+	return err
+}
+
+func (auth *password) ReplaceUserPassword(identifier, oldPassword, newPassword string, w http.ResponseWriter) (*AuthenticationInfo, error) {
+	if identifier == "" {
+		return nil, errors.NewInvalidArgumentError("identifier")
+	}
+	if oldPassword == "" {
+		return nil, errors.NewInvalidArgumentError("oldPassword")
+	}
+	if newPassword == "" {
+		return nil, errors.NewInvalidArgumentError("newPassword")
+	}
+	httpResponse, err := auth.client.DoPostRequest(composeReplaceUserPasswordURL(), newPasswordReplaceRequestBody(identifier, oldPassword, newPassword), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return auth.generateAuthenticationInfo(httpResponse, w)
+}
+
+func (auth *password) GetPasswordPolicy() (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{}
+	_, err := auth.client.DoGetRequest(composePasswordPolicyURL(), &api.HTTPRequest{ResBodyObj: policy}, "")
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
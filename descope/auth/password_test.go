@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignUpPassword(t *testing.T) {
+	identifier := "test@test.com"
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, composeSignUpPasswordURL(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, identifier, body["externalId"])
+		assert.EqualValues(t, "abc123!", body["password"])
+		assert.EqualValues(t, "dude", body["user"].(map[string]interface{})["name"])
+	}))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	info, err := a.Password().SignUp(identifier, &User{Name: "dude"}, "abc123!", w)
+	require.NoError(t, err)
+	assert.NotEmpty(t, info.SessionToken.JWT)
+	assert.NotNil(t, findCookie(w.Result(), SessionCookieName))
+}
+
+func TestSignUpPasswordInvalid(t *testing.T) {
+	a, err := newTestAuth(nil, nil)
+	require.NoError(t, err)
+	_, err = a.Password().SignUp("", nil, "abc123!", nil)
+	require.Error(t, err)
+	_, err = a.Password().SignUp("test@test.com", nil, "", nil)
+	require.Error(t, err)
+}
+
+func TestSignInPassword(t *testing.T) {
+	identifier := "test@test.com"
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, composeSignInPasswordURL(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, identifier, body["externalId"])
+		assert.EqualValues(t, "abc123!", body["password"])
+		assert.EqualValues(t, map[string]interface{}{"mfa": true}, body["loginOptions"])
+		_, jwt := getProjectAndJwt(r)
+		assert.EqualValues(t, "test", jwt)
+	}))
+	require.NoError(t, err)
+	r := &http.Request{Header: http.Header{"Cookie": []string{"DSR=test"}}}
+	info, err := a.Password().SignIn(identifier, "abc123!", r, &LoginOptions{MFA: true}, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, info.SessionToken)
+}
+
+func TestSignInPasswordInvalid(t *testing.T) {
+	a, err := newTestAuth(nil, DoBadRequest(nil))
+	require.NoError(t, err)
+	_, err = a.Password().SignIn("", "abc123!", nil, nil, nil)
+	require.Error(t, err)
+	_, err = a.Password().SignIn("test@test.com", "", nil, nil, nil)
+	require.Error(t, err)
+	_, err = a.Password().SignIn("test@test.com", "abc123!", nil, &LoginOptions{Stepup: true}, nil)
+	assert.ErrorIs(t, err, errors.InvalidStepupJwtError)
+	_, err = a.Password().SignIn("test@test.com", "wrong", nil, nil, nil)
+	require.Error(t, err)
+}
+
+func TestSendPasswordReset(t *testing.T) {
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, composeSendPasswordResetURL(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, "test@test.com", body["externalId"])
+		assert.EqualValues(t, "https://test.me/reset", body["redirectUrl"])
+	}))
+	require.NoError(t, err)
+	require.NoError(t, a.Password().SendPasswordReset("test@test.com", "https://test.me/reset"))
+	require.Error(t, a.Password().SendPasswordReset("", ""))
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, composeUpdateUserPasswordURL(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, "test@test.com", body["externalId"])
+		assert.EqualValues(t, "new123!", body["newPassword"])
+		_, jwt := getProjectAndJwt(r)
+		assert.EqualValues(t, "test", jwt)
+	}))
+	require.NoError(t, err)
+	r := &http.Request{Header: http.Header{"Cookie": []string{"DSR=test"}}}
+	require.NoError(t, a.Password().UpdateUserPassword("test@test.com", "new123!", r))
+	require.Error(t, a.Password().UpdateUserPassword("test@test.com", "", r))
+	assert.ErrorIs(t, a.Password().UpdateUserPassword("test@test.com", "new123!", &http.Request{}), errors.RefreshTokenError)
+}
+
+func TestReplaceUserPassword(t *testing.T) {
+	a, err := newTestAuth(nil, DoOk(func(r *http.Request) {
+		assert.EqualValues(t, composeReplaceUserPasswordURL(), r.URL.RequestURI())
+		body, err := readBodyMap(r)
+		require.NoError(t, err)
+		assert.EqualValues(t, "test@test.com", body["externalId"])
+		assert.EqualValues(t, "old123!", body["oldPassword"])
+		assert.EqualValues(t, "new123!", body["newPassword"])
+	}))
+	require.NoError(t, err)
+	info, err := a.Password().ReplaceUserPassword("test@test.com", "old123!", "new123!", nil)
+	require.NoError(t, err)
+	assert.NotNil(t, info.SessionToken)
+
+	_, err = a.Password().ReplaceUserPassword("test@test.com", "", "new123!", nil)
+	require.Error(t, err)
+	_, err = a.Password().ReplaceUserPassword("test@test.com", "old123!", "", nil)
+	require.Error(t, err)
+}
+
+func TestGetPasswordPolicy(t *testing.T) {
+	a, err := newTestAuth(nil, DoOkWithBody(func(r *http.Request) {
+		assert.EqualValues(t, http.MethodGet, r.Method)
+		assert.EqualValues(t, composePasswordPolicyURL(), r.URL.RequestURI())
+	}, &PasswordPolicy{MinLength: 8, Uppercase: true, NonAlphanumeric: true}))
+	require.NoError(t, err)
+	policy, err := a.Password().GetPasswordPolicy()
+	require.NoError(t, err)
+	assert.EqualValues(t, &PasswordPolicy{MinLength: 8, Uppercase: true, NonAlphanumeric: true}, policy)
+}
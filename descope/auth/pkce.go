@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	urlpkg "net/url"
+	"time"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/logger"
+)
+
+const (
+	redirectStateQueryParam = "state"
+	redirectCodeQueryParam  = "code"
+	redirectStateMaxAge     = 10 * time.Minute
+	codeChallengeMethodS256 = "S256"
+)
+
+// bindRedirectState generates a state value and a PKCE code verifier for a redirect (OAuth/SAML) flow,
+// stores the verifier in a short-lived cookie named after the state, so that flows started concurrently in
+// the same browser do not override each other, adds the code challenge to the start request query params,
+// and returns the redirect URL with the state appended to it.
+func bindRedirectState(redirectURL string, params map[string]string, w http.ResponseWriter) (string, error) {
+	if w == nil {
+		return "", errors.MissingResponseWriterError
+	}
+	if redirectURL == "" {
+		return "", errors.NewInvalidArgumentError("redirectURL")
+	}
+	parsedURL, err := urlpkg.Parse(redirectURL)
+	if err != nil {
+		return "", errors.NewInvalidArgumentError("redirectURL")
+	}
+	state, err := generateRandomString()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := generateRandomString()
+	if err != nil {
+		return "", err
+	}
+
+	query := parsedURL.Query()
+	query.Set(redirectStateQueryParam, state)
+	parsedURL.RawQuery = query.Encode()
+
+	challenge := sha256.Sum256([]byte(verifier))
+	params["codeChallenge"] = base64.RawURLEncoding.EncodeToString(challenge[:])
+	params["codeChallengeMethod"] = codeChallengeMethodS256
+
+	http.SetCookie(w, createRedirectStateCookie(state, verifier, parsedURL.Path, int(redirectStateMaxAge.Seconds())))
+	return parsedURL.String(), nil
+}
+
+// verifyRedirectState finds the cookie stored by bindRedirectState for the state in the request query,
+// deletes the single use cookie, and returns the code verifier that should be sent with the exchange.
+func verifyRedirectState(r *http.Request, w http.ResponseWriter) (string, error) {
+	state := r.URL.Query().Get(redirectStateQueryParam)
+	if state == "" {
+		logger.LogDebug("redirect authentication state is missing")
+		return "", errors.InvalidStateError
+	}
+	cookie, _ := r.Cookie(RedirectStateCookiePrefix + state)
+	if cookie == nil || cookie.Value == "" {
+		logger.LogDebug("redirect authentication state does not match any started flow")
+		return "", errors.InvalidStateError
+	}
+	if w != nil {
+		http.SetCookie(w, createRedirectStateCookie(state, "", r.URL.Path, -1))
+	}
+	return cookie.Value, nil
+}
+
+func generateRandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func createRedirectStateCookie(state, value, path string, maxAge int) *http.Cookie {
+	if path == "" {
+		path = "/"
+	}
+	return &http.Cookie{
+		// only sent to the callback the flow redirects back to
+		Path:     path,
+		Name:     RedirectStateCookiePrefix + state,
+		Value:    value,
+		HttpOnly: true,
+		MaxAge:   maxAge,
+		// the cookie must be sent when the browser is redirected back from the provider
+		SameSite: http.SameSiteLaxMode,
+		Secure:   true,
+	}
+}
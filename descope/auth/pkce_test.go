@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertBoundRedirectURL asserts the start request of a bound flow and returns its state
+func assertBoundRedirectURL(t *testing.T, r *http.Request, landingURL string) string {
+	redirectURL, err := url.Parse(r.URL.Query().Get("redirectURL"))
+	require.NoError(t, err)
+	state := redirectURL.Query().Get(redirectStateQueryParam)
+	assert.NotEmpty(t, state)
+	redirectURL.RawQuery = ""
+	assert.EqualValues(t, landingURL, redirectURL.String())
+	assert.NotEmpty(t, r.URL.Query().Get("codeChallenge"))
+	assert.EqualValues(t, codeChallengeMethodS256, r.URL.Query().Get("codeChallengeMethod"))
+	return state
+}
+
+func TestBindRedirectState(t *testing.T) {
+	params := map[string]string{}
+	w := httptest.NewRecorder()
+	redirectURL, err := bindRedirectState("https://example.com/callback?a=b", params, w)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(redirectURL)
+	require.NoError(t, err)
+	assert.EqualValues(t, "b", parsed.Query().Get("a"))
+	state := parsed.Query().Get(redirectStateQueryParam)
+	require.NotEmpty(t, state)
+
+	cookie := findCookie(w.Result(), RedirectStateCookiePrefix+state)
+	require.NotNil(t, cookie)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.EqualValues(t, "/callback", cookie.Path)
+	assert.EqualValues(t, int(redirectStateMaxAge.Seconds()), cookie.MaxAge)
+
+	req := httptest.NewRequest(http.MethodGet, "/callback?code=abc&state="+url.QueryEscape(state), nil)
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	verifier, err := verifyRedirectState(req, w)
+	require.NoError(t, err)
+	challenge := sha256.Sum256([]byte(verifier))
+	assert.EqualValues(t, base64.RawURLEncoding.EncodeToString(challenge[:]), params["codeChallenge"])
+	assert.EqualValues(t, codeChallengeMethodS256, params["codeChallengeMethod"])
+	deleted := findCookie(w.Result(), RedirectStateCookiePrefix+state)
+	require.NotNil(t, deleted)
+	assert.EqualValues(t, -1, deleted.MaxAge)
+	assert.EqualValues(t, "/callback", deleted.Path)
+}
+
+func TestBindRedirectStateConcurrentFlows(t *testing.T) {
+	w := httptest.NewRecorder()
+	first, err := bindRedirectState("https://example.com/callback", map[string]string{}, w)
+	require.NoError(t, err)
+	second, err := bindRedirectState("https://example.com/callback", map[string]string{}, w)
+	require.NoError(t, err)
+
+	// both flows keep their own cookie and can be finalized in any order
+	for _, redirectURL := range []string{second, first} {
+		parsed, err := url.Parse(redirectURL)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/callback?code=abc&"+parsed.RawQuery, nil)
+		for _, cookie := range w.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		verifier, err := verifyRedirectState(req, nil)
+		require.NoError(t, err)
+		assert.EqualValues(t, findCookie(w.Result(), RedirectStateCookiePrefix+parsed.Query().Get(redirectStateQueryParam)).Value, verifier)
+	}
+}
+
+func TestBindRedirectStateRequiresResponseWriter(t *testing.T) {
+	params := map[string]string{}
+	_, err := bindRedirectState("https://example.com", params, nil)
+	assert.ErrorIs(t, err, errors.MissingResponseWriterError)
+	assert.Empty(t, params)
+
+	_, err = bindRedirectState("", params, httptest.NewRecorder())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "redirectURL")
+	assert.Empty(t, params)
+}
+
+func TestVerifyRedirectStateMismatch(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/callback?code=abc&state=foo", nil)
+	req.AddCookie(&http.Cookie{Name: RedirectStateCookiePrefix + "bar", Value: "verifier"})
+	w := httptest.NewRecorder()
+	_, err := verifyRedirectState(req, w)
+	assert.ErrorIs(t, err, errors.InvalidStateError)
+	// the cookie of another flow is kept
+	assert.Empty(t, w.Result().Cookies())
+
+	req = httptest.NewRequest(http.MethodGet, "/callback?code=abc", nil)
+	req.AddCookie(&http.Cookie{Name: RedirectStateCookiePrefix + "foo", Value: "verifier"})
+	_, err = verifyRedirectState(req, nil)
+	assert.ErrorIs(t, err, errors.InvalidStateError)
+}
+
+func TestOAuthStartBindState(t *testing.T) {
+	uri := "http://test.me"
+	landingURL := "https://test.com/callback"
+	var state string
+	a, err := newTestAuth(nil, DoRedirect(uri, func(r *http.Request) {
+		state = assertBoundRedirectURL(t, r, landingURL)
+	}))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	urlStr, err := a.OAuth().StartWithOptions(OAuthGithub, landingURL, nil, nil, &OAuthOptions{BindState: true}, w)
+	require.NoError(t, err)
+	assert.EqualValues(t, uri, urlStr)
+	assert.NotNil(t, findCookie(w.Result(), RedirectStateCookiePrefix+state))
+
+	_, err = a.OAuth().StartWithOptions(OAuthGithub, landingURL, nil, nil, &OAuthOptions{BindState: true}, nil)
+	assert.ErrorIs(t, err, errors.MissingResponseWriterError)
+}
+
+func TestSAMLStartBindState(t *testing.T) {
+	uri := "http://test.me"
+	landingURL := "https://test.com/callback"
+	var state string
+	a, err := newTestAuth(nil, DoRedirect(uri, func(r *http.Request) {
+		assert.EqualValues(t, "tenantID", r.URL.Query().Get("tenant"))
+		state = assertBoundRedirectURL(t, r, landingURL)
+	}))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	urlStr, err := a.SAML().StartWithOptions("tenantID", landingURL, nil, nil, &SAMLOptions{BindState: true}, w)
+	require.NoError(t, err)
+	assert.EqualValues(t, uri, urlStr)
+	assert.NotNil(t, findCookie(w.Result(), RedirectStateCookiePrefix+state))
+}
+
+func TestExchangeTokenFromRequest(t *testing.T) {
+	a, err := newTestAuth(nil, func(r *http.Request) (*http.Response, error) {
+		req := exchangeTokenBody{}
+		require.NoError(t, readBody(r, &req))
+		assert.EqualValues(t, "abc", req.Code)
+		assert.EqualValues(t, "verifier", req.CodeVerifier)
+		return DoOk(nil)(r)
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/callback?code=abc&state=foo", nil)
+	req.AddCookie(&http.Cookie{Name: RedirectStateCookiePrefix + "foo", Value: "verifier"})
+	authInfo, err := a.OAuth().ExchangeTokenFromRequest(req, httptest.NewRecorder())
+	require.NoError(t, err)
+	assert.NotNil(t, authInfo)
+
+	authInfo, err = a.SAML().ExchangeTokenFromRequest(req, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, authInfo)
+
+	_, err = a.OAuth().ExchangeTokenFromRequest(nil, nil)
+	assert.ErrorIs(t, err, errors.MissingRequestError)
+}
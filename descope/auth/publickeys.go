@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+
+	"github.com/descope/go-sdk/descope/errors"
+)
+
+// PublicKeySet - a set of public keys used to validate tokens without fetching the keys from Descope, such as a pinned
+// set loaded from a file. A set can hold both the current and the next keys of a project during key rotation, and is
+// safe to update while in use.
+type PublicKeySet struct {
+	mu     sync.RWMutex
+	byKID  map[string]jwk.Key
+	noKIDs []jwk.Key // keys without a key ID, such as PEM encoded keys, are tried for any token
+}
+
+// ParsePublicKeys - parses public keys given as a single JWK, a JWK set ({"keys": [...]}), a JSON array of JWKs, or
+// one or more PEM encoded public keys or certificates.
+func ParsePublicKeys(data []byte) (*PublicKeySet, error) {
+	data = bytes.TrimSpace(data)
+	var keys []jwk.Key
+	switch {
+	case bytes.HasPrefix(data, []byte("-----BEGIN")):
+		set, err := jwk.Parse(data, jwk.WithPEM(true))
+		if err != nil {
+			return nil, err
+		}
+		keys = setKeys(set)
+	case bytes.HasPrefix(data, []byte("[")):
+		raw := []json.RawMessage{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		for i := range raw {
+			key, err := jwk.ParseKey(raw[i])
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+	default:
+		set, err := jwk.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		keys = setKeys(set)
+	}
+
+	s := &PublicKeySet{byKID: map[string]jwk.Key{}}
+	for _, key := range keys {
+		pk, err := key.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if pk.Algorithm().String() == "" {
+			if alg, ok := defaultAlgorithm(pk); ok {
+				_ = pk.Set(jwk.AlgorithmKey, alg)
+			}
+		}
+		if kid := pk.KeyID(); kid != "" {
+			s.byKID[kid] = pk
+		} else {
+			s.noKIDs = append(s.noKIDs, pk)
+		}
+	}
+	if s.Len() == 0 {
+		return nil, errors.NewNoPublicKeyError()
+	}
+	return s, nil
+}
+
+// ReadPublicKeys - reads and parses public keys in any of the formats accepted by ParsePublicKeys
+func ReadPublicKeys(r io.Reader) (*PublicKeySet, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKeys(data)
+}
+
+// LoadPublicKeys - loads and parses public keys from a file in any of the formats accepted by ParsePublicKeys
+func LoadPublicKeys(path string) (*PublicKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKeys(data)
+}
+
+// Update - replaces the keys in the set with the keys in the given set, such as after loading a rotated key bundle
+func (s *PublicKeySet) Update(keys *PublicKeySet) {
+	if keys == nil || keys == s {
+		return
+	}
+	keys.mu.RLock()
+	byKID, noKIDs := keys.byKID, keys.noKIDs
+	keys.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byKID, s.noKIDs = byKID, noKIDs
+}
+
+// Len - the number of keys in the set
+func (s *PublicKeySet) Len() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.byKID) + len(s.noKIDs)
+}
+
+// KeyIDs - the IDs of the keys in the set, keys without an ID are not included
+func (s *PublicKeySet) KeyIDs() []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	kids := []string{}
+	for kid := range s.byKID {
+		kids = append(kids, kid)
+	}
+	return kids
+}
+
+// find returns the key with the given ID when in the set, with exact set to true, or otherwise the keys without an ID
+func (s *PublicKeySet) find(kid string) (keys []jwk.Key, exact bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if key, ok := s.byKID[kid]; ok {
+		return []jwk.Key{key}, true
+	}
+	return s.noKIDs, false
+}
+
+func setKeys(set jwk.Set) []jwk.Key {
+	keys := []jwk.Key{}
+	for i := 0; i < set.Len(); i++ {
+		if key, ok := set.Key(i); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// defaultAlgorithm returns the signature algorithm of keys that do not specify one, such as PEM encoded keys
+func defaultAlgorithm(key jwk.Key) (jwa.SignatureAlgorithm, bool) {
+	switch k := key.(type) {
+	case jwk.RSAPublicKey:
+		return jwa.RS256, true
+	case jwk.ECDSAPublicKey:
+		switch k.Crv() {
+		case jwa.P256:
+			return jwa.ES256, true
+		case jwa.P384:
+			return jwa.ES384, true
+		case jwa.P521:
+			return jwa.ES512, true
+		}
+	case jwk.OKPPublicKey:
+		if k.Crv() == jwa.Ed25519 {
+			return jwa.EdDSA, true
+		}
+	}
+	return "", false
+}
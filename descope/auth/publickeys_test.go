@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSigningKey struct {
+	private jwk.Key
+	public  jwk.Key
+	alg     jwa.SignatureAlgorithm
+	raw     any
+}
+
+func newTestSigningKey(t *testing.T, kid string, rsaKey bool) *testSigningKey {
+	var raw, rawPublic any
+	alg := jwa.ES256
+	if rsaKey {
+		k, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		raw, rawPublic, alg = k, &k.PublicKey, jwa.RS256
+	} else {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		raw, rawPublic = k, &k.PublicKey
+	}
+	private, err := jwk.FromRaw(raw)
+	require.NoError(t, err)
+	require.NoError(t, private.Set(jwk.KeyIDKey, kid))
+	require.NoError(t, private.Set(jwk.AlgorithmKey, alg))
+	public, err := private.PublicKey()
+	require.NoError(t, err)
+	return &testSigningKey{private: private, public: public, alg: alg, raw: rawPublic}
+}
+
+func (k *testSigningKey) jwk(t *testing.T) string {
+	b, err := json.Marshal(k.public)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func (k *testSigningKey) pem(t *testing.T) string {
+	b, err := x509.MarshalPKIXPublicKey(k.raw)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+func (k *testSigningKey) sign(t *testing.T) string {
+	token := jwt.New()
+	require.NoError(t, token.Set(jwt.SubjectKey, "someuser"))
+	require.NoError(t, token.Set(jwt.ExpirationKey, time.Now().Add(time.Hour)))
+	require.NoError(t, token.Set(claimAttributeName, SessionCookieName))
+	signed, err := jwt.Sign(token, jwt.WithKey(k.alg, k.private))
+	require.NoError(t, err)
+	return string(signed)
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	k1, k2 := newTestSigningKey(t, "k1", false), newTestSigningKey(t, "k2", true)
+
+	keys, err := ParsePublicKeys([]byte(k1.jwk(t)))
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{"k1"}, keys.KeyIDs())
+
+	keys, err = ParsePublicKeys([]byte(fmt.Sprintf(`{"keys": [%s, %s]}`, k1.jwk(t), k2.jwk(t))))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"k1", "k2"}, keys.KeyIDs())
+
+	keys, err = ReadPublicKeys(strings.NewReader(fmt.Sprintf(` [%s, %s]`, k1.jwk(t), k2.jwk(t))))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, keys.Len())
+
+	keys, err = ParsePublicKeys([]byte(k1.pem(t) + k2.pem(t)))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, keys.Len())
+	assert.Empty(t, keys.KeyIDs())
+
+	path := filepath.Join(t.TempDir(), "keys.pem")
+	require.NoError(t, os.WriteFile(path, []byte(k2.pem(t)), 0o600))
+	keys, err = LoadPublicKeys(path)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, keys.Len())
+
+	_, err = LoadPublicKeys(filepath.Join(t.TempDir(), "missing.pem"))
+	assert.Error(t, err)
+	_, err = ParsePublicKeys([]byte(`{"keys": []}`))
+	assert.ErrorIs(t, err, errors.NoPublicKeyError)
+	_, err = ParsePublicKeys([]byte(`[{"kty": "unknown"}]`))
+	assert.Error(t, err)
+	_, err = ParsePublicKeys([]byte("-----BEGIN PUBLIC KEY-----\nbad\n-----END PUBLIC KEY-----"))
+	assert.Error(t, err)
+}
+
+func TestPublicKeySetRotation(t *testing.T) {
+	k1, k2 := newTestSigningKey(t, "k1", false), newTestSigningKey(t, "k2", true)
+	keys, err := ParsePublicKeys([]byte(fmt.Sprintf(`[%s, %s]`, k1.jwk(t), k2.jwk(t))))
+	require.NoError(t, err)
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", PublicKeys: keys}, nil, func(r *http.Request) (*http.Response, error) {
+		require.Fail(t, "keys should not be fetched")
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	ok, _, err := a.ValidateSessionTokens(k1.sign(t), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, _, err = a.ValidateSessionTokens(k2.sign(t), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// k1 is rotated out
+	rotated, err := ParsePublicKeys([]byte(k2.jwk(t)))
+	require.NoError(t, err)
+	keys.Update(rotated)
+	ok, _, err = a.ValidateSessionTokens(k1.sign(t), "")
+	assert.False(t, ok)
+	assert.Error(t, err)
+	ok, _, err = a.ValidateSessionTokens(k2.sign(t), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestProvidedPEMPublicKey(t *testing.T) {
+	k := newTestSigningKey(t, "unknown-to-pem", true)
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", PublicKey: k.pem(t)}, nil, nil)
+	require.NoError(t, err)
+	ok, token, err := a.ValidateSessionTokens(k.sign(t), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, "someuser", token.ID)
+
+	other := newTestSigningKey(t, "other", true)
+	ok, _, err = a.ValidateSessionTokens(other.sign(t), "")
+	assert.False(t, ok)
+	assert.Error(t, err)
+}
+
+func TestPinnedPublicKeysWithFetching(t *testing.T) {
+	pinned, fetched := newTestSigningKey(t, "pinned", false), newTestSigningKey(t, "fetched", false)
+	requests := 0
+	fetchKeys := func(r *http.Request) (*http.Response, error) {
+		requests++
+		return DoOkWithBody(nil, []any{fetched.public})(r)
+	}
+
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", PublicKey: pinned.jwk(t)}, nil, fetchKeys)
+	require.NoError(t, err)
+	ok, _, err := a.ValidateSessionTokens(fetched.sign(t), "")
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, errors.NoPublicKeyError)
+	assert.Zero(t, requests)
+
+	a, err = newTestAuthConf(&AuthParams{ProjectID: "a", PublicKey: pinned.jwk(t), FetchPublicKeys: true}, nil, fetchKeys)
+	require.NoError(t, err)
+	ok, _, err = a.ValidateSessionTokens(pinned.sign(t), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Zero(t, requests)
+	ok, _, err = a.ValidateSessionTokens(fetched.sign(t), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, requests)
+	ok, _, err = a.ValidateSessionTokens(fetched.sign(t), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, requests)
+}
+
+func TestPinnedPEMPublicKeyWithFetching(t *testing.T) {
+	// the PEM key has no ID, and the live key it was pinned from has since been rotated
+	pinned, live := newTestSigningKey(t, "pinned", true), newTestSigningKey(t, "live", true)
+	requests := 0
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", PublicKey: pinned.pem(t), FetchPublicKeys: true}, nil, func(r *http.Request) (*http.Response, error) {
+		requests++
+		return DoOkWithBody(nil, []any{live.public})(r)
+	})
+	require.NoError(t, err)
+
+	ok, _, err := a.ValidateSessionTokens(live.sign(t), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, requests)
+
+	// the pinned key is still tried for tokens whose key is not in the fetched keys
+	ok, _, err = a.ValidateSessionTokens(pinned.sign(t), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	other := newTestSigningKey(t, "other", true)
+	ok, _, err = a.ValidateSessionTokens(other.sign(t), "")
+	assert.False(t, ok)
+	assert.Error(t, err)
+}
+
+func TestProvidedPublicKeyConcurrentValidation(t *testing.T) {
+	k := newTestSigningKey(t, "k", false)
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", PublicKey: k.jwk(t)}, nil, nil)
+	require.NoError(t, err)
+	token := k.sign(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, _, err := a.ValidateSessionTokens(token, "")
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		}()
+	}
+	wg.Wait()
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/descope/go-sdk/descope/api"
 	"github.com/descope/go-sdk/descope/errors"
@@ -18,11 +19,41 @@ type samlStartResponse struct {
 }
 
 func (auth *saml) Start(tenant string, redirectURL string, r *http.Request, loginOptions *LoginOptions, w http.ResponseWriter) (url string, err error) {
+	return auth.StartWithOptions(tenant, redirectURL, r, loginOptions, nil, w)
+}
+
+func (auth *saml) StartWithOptions(tenant string, redirectURL string, r *http.Request, loginOptions *LoginOptions, samlOptions *SAMLOptions, w http.ResponseWriter) (url string, err error) {
 	if tenant == "" {
 		return "", errors.NewInvalidArgumentError("tenant")
 	}
-	m := map[string]string{
-		"tenant": string(tenant),
+	return auth.start(map[string]string{"tenant": tenant}, redirectURL, r, loginOptions, samlOptions, w)
+}
+
+func (auth *saml) StartWithEmail(emailOrDomain string, redirectURL string, r *http.Request, loginOptions *LoginOptions, samlOptions *SAMLOptions, w http.ResponseWriter) (url string, err error) {
+	domain := extractEmailDomain(emailOrDomain)
+	if domain == "" {
+		return "", errors.NewInvalidArgumentError("emailOrDomain")
+	}
+	if auth.conf.TenantResolver == nil {
+		return "", errors.MissingSSOTenantResolverError
+	}
+	tenantID, err := auth.conf.TenantResolver.ResolveSSOTenant(domain)
+	if err != nil {
+		logger.LogError("failed to resolve the sso tenant for domain [%s]", err, domain)
+		return "", err
+	}
+	if tenantID == "" {
+		logger.LogDebug("no sso tenant found for domain [%s]", domain)
+		return "", errors.SSOTenantNotFoundError
+	}
+	return auth.start(map[string]string{"tenant": tenantID}, redirectURL, r, loginOptions, samlOptions, w)
+}
+
+func (auth *saml) start(m map[string]string, redirectURL string, r *http.Request, loginOptions *LoginOptions, samlOptions *SAMLOptions, w http.ResponseWriter) (url string, err error) {
+	if samlOptions != nil && samlOptions.BindState {
+		if redirectURL, err = bindRedirectState(redirectURL, m, w); err != nil {
+			return "", err
+		}
 	}
 	if len(redirectURL) > 0 {
 		m["redirectURL"] = redirectURL
@@ -34,7 +65,7 @@ func (auth *saml) Start(tenant string, redirectURL string, r *http.Request, logi
 			return "", errors.InvalidStepupJwtError
 		}
 	}
-	httpResponse, err := auth.client.DoPostRequest(composeSAMLStartURL(), loginOptions, &api.HTTPRequest{QueryParams: m}, pswd)
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeSAMLStartURL(), loginOptions, &api.HTTPRequest{QueryParams: m}, pswd)
 	if err != nil {
 		return
 	}
@@ -43,7 +74,7 @@ func (auth *saml) Start(tenant string, redirectURL string, r *http.Request, logi
 		res := &samlStartResponse{}
 		err = utils.Unmarshal([]byte(httpResponse.BodyStr), res)
 		if err != nil {
-			logger.LogError("failed to parse saml location from response for [%s]", err, tenant)
+			logger.LogError("failed to parse saml location from response for [%v]", err, m)
 			return "", err
 		}
 		url = res.URL
@@ -53,6 +84,26 @@ func (auth *saml) Start(tenant string, redirectURL string, r *http.Request, logi
 	return
 }
 
+// extractEmailDomain returns the lower cased domain of the given email, or the given value itself when it is
+// already a bare domain, and an empty string when it is neither.
+func extractEmailDomain(emailOrDomain string) string {
+	emailOrDomain = strings.TrimSpace(emailOrDomain)
+	if strings.Contains(emailOrDomain, "@") {
+		if _, err := normalizeEmail(emailOrDomain); err != nil {
+			return ""
+		}
+		emailOrDomain = emailOrDomain[strings.LastIndex(emailOrDomain, "@")+1:]
+	}
+	if !strings.Contains(emailOrDomain, ".") || strings.ContainsAny(emailOrDomain, " /:?#") {
+		return ""
+	}
+	return strings.ToLower(emailOrDomain)
+}
+
 func (auth *saml) ExchangeToken(code string, w http.ResponseWriter) (*AuthenticationInfo, error) {
 	return auth.exchangeToken(code, composeSAMLExchangeTokenURL(), w)
 }
+
+func (auth *saml) ExchangeTokenFromRequest(r *http.Request, w http.ResponseWriter) (*AuthenticationInfo, error) {
+	return auth.exchangeTokenFromRequest(r, composeSAMLExchangeTokenURL(), w)
+}
@@ -67,3 +67,67 @@ func TestSAMLStartInvalidForwardResponse(t *testing.T) {
 	_, err = a.SAML().Start("test", "", nil, &LoginOptions{Stepup: true}, w)
 	assert.ErrorIs(t, err, errors.InvalidStepupJwtError)
 }
+
+// ssoTenants resolves the tenant IDs of the domains it maps, or returns the error mapped to the empty domain
+type ssoTenants map[string]string
+
+func (m ssoTenants) ResolveSSOTenant(domain string) (string, error) {
+	if err, ok := m[""]; ok {
+		return "", errors.NewError(errors.BadRequestErrorCode, err)
+	}
+	return m[domain], nil
+}
+
+func TestSAMLStartWithEmail(t *testing.T) {
+	uri := "http://test.me"
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", TenantResolver: ssoTenants{"example.com": "t1"}}, nil, DoRedirect(uri, func(r *http.Request) {
+		assert.EqualValues(t, composeSAMLStartURL(), r.URL.Path)
+		assert.EqualValues(t, "t1", r.URL.Query().Get("tenant"))
+		assert.Empty(t, r.URL.Query().Get("domain"))
+	}))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	urlStr, err := a.SAML().StartWithEmail("Dude@Example.com", "https://test.com", nil, nil, nil, w)
+	require.NoError(t, err)
+	assert.EqualValues(t, uri, urlStr)
+	assert.EqualValues(t, http.StatusTemporaryRedirect, w.Result().StatusCode)
+
+	urlStr, err = a.SAML().StartWithEmail("example.com", "", nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, uri, urlStr)
+}
+
+func TestSAMLStartWithEmailInvalid(t *testing.T) {
+	a, err := newTestAuth(nil, nil)
+	require.NoError(t, err)
+	for _, value := range []string{"", "dude", "dude@", "@example", "http://example.com"} {
+		_, err = a.SAML().StartWithEmail(value, "", nil, nil, nil, nil)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestSAMLStartWithEmailNoTenant(t *testing.T) {
+	called := false
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", TenantResolver: ssoTenants{"example.com": "t1"}}, nil, func(r *http.Request) (*http.Response, error) {
+		called = true
+		return nil, nil
+	})
+	require.NoError(t, err)
+	_, err = a.SAML().StartWithEmail("dude@other.com", "", nil, nil, nil, nil)
+	assert.ErrorIs(t, err, errors.SSOTenantNotFoundError)
+	assert.False(t, called)
+}
+
+func TestSAMLStartWithEmailResolverError(t *testing.T) {
+	a, err := newTestAuthConf(&AuthParams{ProjectID: "a", TenantResolver: ssoTenants{"": "failed"}}, nil, nil)
+	require.NoError(t, err)
+	_, err = a.SAML().StartWithEmail("dude@example.com", "", nil, nil, nil, nil)
+	assert.ErrorContains(t, err, "failed")
+}
+
+func TestSAMLStartWithEmailNoResolver(t *testing.T) {
+	a, err := newTestAuth(nil, nil)
+	require.NoError(t, err)
+	_, err = a.SAML().StartWithEmail("dude@example.com", "", nil, nil, nil, nil)
+	assert.ErrorIs(t, err, errors.MissingSSOTenantResolverError)
+}
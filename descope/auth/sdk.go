@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -48,6 +49,12 @@ type MagicLink interface {
 	// GetSession - Use to get a session that was generated by SignIn/SignUp request, and verified with Verify request.
 	GetSession(pendingRef string, w http.ResponseWriter) (*AuthenticationInfo, error)
 
+	// WaitForSession - Use to wait for a session that was generated by a cross device SignIn/SignUp request.
+	// Polls GetSession until the magic link is verified, an error other than a pending session is returned,
+	// the context is done, or the timeout in the options elapses (returning context.DeadlineExceeded).
+	// opts are optional, see auth/WaitForSessionOptions for the defaults.
+	WaitForSession(ctx context.Context, pendingRef string, opts *WaitForSessionOptions) (*AuthenticationInfo, error)
+
 	// Verify - Use to verify a SignIn/SignUp request, based on the magic link token generated.
 	// if the link was generated with crossDevice, the authentication info will be nil, and should returned with GetSession.
 	Verify(token string, w http.ResponseWriter) (*AuthenticationInfo, error)
@@ -75,6 +82,78 @@ type MagicLink interface {
 	UpdateUserPhoneCrossDevice(method DeliveryMethod, identifier, phone, URI string, request *http.Request) (*MagicLinkResponse, error)
 }
 
+// EnchantedLink - a magic link flavor where the email contains several links, and only the one matching the
+// link ID displayed to the user on the original device authenticates, protecting against lookalike links.
+// Enchanted links are always cross device, the session is returned with GetSession or WaitForSession.
+type EnchantedLink interface {
+	// SignIn - Use to login a user based on an enchanted link that will be sent to the given email.
+	// returns the pending reference to be used in GetSession and the link ID that should be displayed to the user,
+	// or an error upon failure.
+	SignIn(identifier, URI string, r *http.Request, loginOptions *LoginOptions) (*EnchantedLinkResponse, error)
+
+	// SignUp - Use to create a new user based on the given email identifier.
+	// optional to add user metadata for farther user details such as name and more.
+	// returns the pending reference and link ID, or an error upon failure.
+	SignUp(identifier, URI string, user *User) (*EnchantedLinkResponse, error)
+
+	// SignUpOrIn - Use to login in using identifier, if user does not exists, a new user will be created
+	// with the given identifier.
+	// returns the pending reference and link ID, or an error upon failure.
+	SignUpOrIn(identifier string, URI string) (*EnchantedLinkResponse, error)
+
+	// GetSession - Use to get a session that was generated by SignIn/SignUp request, and verified with Verify request.
+	// returns errors.EnchantedLinkUnauthorized while the link was not verified yet.
+	GetSession(pendingRef string, w http.ResponseWriter) (*AuthenticationInfo, error)
+
+	// WaitForSession - Use to wait for a session that was generated by a SignIn/SignUp request, see MagicLink.WaitForSession.
+	WaitForSession(ctx context.Context, pendingRef string, opts *WaitForSessionOptions) (*AuthenticationInfo, error)
+
+	// Verify - Use to verify a SignIn/SignUp request, based on the token of the link the user picked.
+	// returns an error when the token is invalid or does not belong to the link that was displayed.
+	Verify(token string) error
+
+	// UpdateUserEmail - Use to update email and validate via enchanted link
+	// identifier of user to update
+	// email to update for the user
+	// request is required to validate the user
+	// returns the pending reference and link ID, or an error upon failure.
+	UpdateUserEmail(identifier, email, URI string, request *http.Request) (*EnchantedLinkResponse, error)
+}
+
+type Password interface {
+	// SignUp - Use to create a new user that authenticates with a password.
+	// optional to add user metadata for farther user details such as name and more.
+	// The password must comply with the project password policy (see GetPasswordPolicy).
+	// Use the ResponseWriter (optional) to apply the cookies to the response automatically.
+	// returns the authentication info upon success or an error upon failure.
+	SignUp(identifier string, user *User, password string, w http.ResponseWriter) (*AuthenticationInfo, error)
+
+	// SignIn - Use to login a user with their identifier and password.
+	// loginOptions (optional) are used for step-up or MFA flows, in which case the request must contain a valid refresh token.
+	// Use the ResponseWriter (optional) to apply the cookies to the response automatically.
+	// returns the authentication info upon success or an error upon failure.
+	SignIn(identifier, password string, r *http.Request, loginOptions *LoginOptions, w http.ResponseWriter) (*AuthenticationInfo, error)
+
+	// SendPasswordReset - Use to send a password reset link to the user, using the method configured in the project settings.
+	// redirectURL (optional) is the URL the reset link should point to.
+	// returns an error upon failure.
+	SendPasswordReset(identifier, redirectURL string) error
+
+	// UpdateUserPassword - Use to set a new password for a logged in user.
+	// request is required to validate the user.
+	// returns an error upon failure.
+	UpdateUserPassword(identifier, newPassword string, request *http.Request) error
+
+	// ReplaceUserPassword - Use to replace the password of a user, given their current password.
+	// Use the ResponseWriter (optional) to apply the cookies to the response automatically.
+	// returns the authentication info upon success or an error upon failure.
+	ReplaceUserPassword(identifier, oldPassword, newPassword string, w http.ResponseWriter) (*AuthenticationInfo, error)
+
+	// GetPasswordPolicy - Use to get the password requirements configured in the project settings,
+	// for example to validate passwords on the client before submitting them.
+	GetPasswordPolicy() (*PasswordPolicy, error)
+}
+
 type OTP interface {
 	// SignIn - Use to login a user based on the given identifier either email or a phone
 	// and choose the selected delivery method for verification. (see auth/DeliveryMethod)
@@ -91,8 +170,22 @@ type OTP interface {
 	// with the given identifier.
 	SignUpOrIn(method DeliveryMethod, identifier string) error
 
+	// SignInEmbedded - Use to login a user like SignIn, with a code that is not delivered by Descope but returned
+	// for the application to deliver it in its own channel (see auth/MethodEmbedded). The identifier can be any login ID.
+	// The code is verified with VerifyCode and MethodEmbedded.
+	SignInEmbedded(identifier string, r *http.Request, loginOptions *LoginOptions) (code string, err error)
+
+	// SignUpEmbedded - Use to create a new user like SignUp, and returns the code to deliver, see SignInEmbedded.
+	SignUpEmbedded(identifier string, user *User) (code string, err error)
+
+	// SignUpOrInEmbedded - Use to login or create a user like SignUpOrIn, and returns the code to deliver, see SignInEmbedded.
+	SignUpOrInEmbedded(identifier string) (code string, err error)
+
 	// VerifyCode - Use to verify a SignIn/SignUp based on the given identifier either an email or a phone
 	// followed by the code used to verify and authenticate the user.
+	// When the method is empty it is resolved from the identifier, see auth/IdentifierResolver.ResolveDeliveryMethod.
+	// A phone number resolves to MethodSMS, so codes sent with MethodWhatsApp or MethodVoice must be verified with
+	// that method given explicitly.
 	// In case the request cookie can be renewed an automatic renewal is called and returns a new set of cookies to use.
 	// Use the ResponseWriter (optional) to apply the cookies to the response automatically.
 	// returns a list of cookies or an error upon failure.
@@ -132,11 +225,24 @@ type OAuth interface {
 	// returns an error upon failure and a string represent the redirect URL upon success.
 	// Uses the response writer to automatically redirect the client to the provider url for authentication.
 	// A successful authentication will result in a callback to the url defined in the current project settings.
+	// Any provider name that is configured in the project settings can be used, including custom OIDC providers,
+	// e.g. auth.OAuthProvider("my-oidc").
 	Start(provider OAuthProvider, returnURL string, r *http.Request, loginOptions *LoginOptions, w http.ResponseWriter) (string, error)
 
+	// StartWithOptions - Use to start an OAuth authentication like Start, with additional provider specific
+	// options such as extra scopes or a prompt (see auth/OAuthOptions).
+	StartWithOptions(provider OAuthProvider, returnURL string, r *http.Request, loginOptions *LoginOptions, oauthOptions *OAuthOptions, w http.ResponseWriter) (string, error)
+
 	// ExchangeToken - Finalize OAuth
 	// code should be extracted from the redirect URL of OAth/SAML authentication flow
+	// The returned authentication info includes the provider tokens when they are enabled in the project settings.
+	// Flows started with OAuthOptions.BindState must be finalized with ExchangeTokenFromRequest instead.
 	ExchangeToken(code string, w http.ResponseWriter) (*AuthenticationInfo, error)
+
+	// ExchangeTokenFromRequest - Finalize an OAuth flow started with OAuthOptions.BindState from the callback request itself.
+	// Verifies the state against the short-lived cookie of the flow, clears the cookie and sends the PKCE code verifier
+	// along with the code. Returns errors.InvalidStateError when the state does not match a flow started in the browser.
+	ExchangeTokenFromRequest(r *http.Request, w http.ResponseWriter) (*AuthenticationInfo, error)
 }
 
 type SAML interface {
@@ -145,9 +251,23 @@ type SAML interface {
 	// and finalize with the ExchangeToken call
 	Start(tenant string, returnURL string, r *http.Request, loginOptions *LoginOptions, w http.ResponseWriter) (redirectURL string, err error)
 
+	// StartWithOptions - Use to start a SAML login flow like Start, with additional options such as binding the flow
+	// to the browser (see auth/SAMLOptions).
+	StartWithOptions(tenant string, returnURL string, r *http.Request, loginOptions *LoginOptions, samlOptions *SAMLOptions, w http.ResponseWriter) (redirectURL string, err error)
+
+	// StartWithEmail - Use to start a SAML login flow when only the user's email (or its domain) is known,
+	// the tenant is resolved from the self-provisioning domains configured on the project tenants that have SSO enabled,
+	// which requires the ManagementKey in the client config. Returns errors.SSOTenantNotFoundError when no SSO-enabled
+	// tenant matches the email domain.
+	StartWithEmail(emailOrDomain string, returnURL string, r *http.Request, loginOptions *LoginOptions, samlOptions *SAMLOptions, w http.ResponseWriter) (redirectURL string, err error)
+
 	// ExchangeToken - Finalize SAML authentication
 	// code should be extracted from the redirect URL of OAth/SAML authentication flow
 	ExchangeToken(code string, w http.ResponseWriter) (*AuthenticationInfo, error)
+
+	// ExchangeTokenFromRequest - Finalize a SAML flow started with SAMLOptions.BindState from the callback request itself,
+	// verifying the state and sending the PKCE code verifier, see OAuth.ExchangeTokenFromRequest.
+	ExchangeTokenFromRequest(r *http.Request, w http.ResponseWriter) (*AuthenticationInfo, error)
 }
 
 type WebAuthn interface {
@@ -196,6 +316,8 @@ type WebAuthn interface {
 
 type Authentication interface {
 	MagicLink() MagicLink
+	EnchantedLink() EnchantedLink
+	Password() Password
 	OTP() OTP
 	TOTP() TOTP
 	OAuth() OAuth
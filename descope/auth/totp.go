@@ -36,7 +36,7 @@ func (auth *totp) UpdateUser(identifier string, r *http.Request) (*TOTPResponse,
 	if err != nil {
 		return nil, err
 	}
-	httpResponse, err := auth.client.DoPostRequest(composeUpdateTOTPURL(), newSignUPTOTPRequestBody(identifier, nil), nil, pswd)
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeUpdateTOTPURL(), newSignUPTOTPRequestBody(identifier, nil), nil, pswd)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +61,7 @@ func (auth *totp) SignInCode(identifier string, code string, r *http.Request, lo
 		}
 	}
 
-	httpResponse, err := auth.client.DoPostRequest(composeVerifyTOTPCodeURL(), newAuthenticationVerifyTOTPRequestBody(identifier, code, loginOptions), nil, pswd)
+	httpResponse, err := auth.clientFor(r).DoPostRequest(composeVerifyTOTPCodeURL(), newAuthenticationVerifyTOTPRequestBody(identifier, code, loginOptions), nil, pswd)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"regexp"
+	"time"
 
 	"github.com/descope/go-sdk/descope/logger"
 	"github.com/lestrrat-go/jwx/v2/jwt"
@@ -17,9 +17,21 @@ type TOTPResponse struct {
 }
 
 type AuthenticationInfo struct {
-	SessionToken *Token        `json:"token,omitempty"`
-	User         *UserResponse `json:"user,omitempty"`
-	FirstSeen    bool          `json:"firstSeen,omitempty"`
+	SessionToken  *Token         `json:"token,omitempty"`
+	User          *UserResponse  `json:"user,omitempty"`
+	FirstSeen     bool           `json:"firstSeen,omitempty"`
+	ProviderToken *ProviderToken `json:"providerToken,omitempty"` // Only returned by OAuth ExchangeToken when the provider tokens are enabled
+}
+
+// ProviderToken - the tokens issued by the OAuth provider during the authentication, which can be used to
+// call the provider APIs on behalf of the user.
+type ProviderToken struct {
+	Provider       string   `json:"provider,omitempty"`
+	ProviderUserID string   `json:"providerUserId,omitempty"`
+	AccessToken    string   `json:"accessToken,omitempty"`
+	RefreshToken   string   `json:"refreshToken,omitempty"`
+	Expiration     int64    `json:"expiration,omitempty"`
+	Scopes         []string `json:"scopes,omitempty"`
 }
 
 type WebAuthnTransactionResponse struct {
@@ -111,6 +123,46 @@ func (to *Token) IsMFA() bool {
 	return len(to.AuthFactors()) > 1
 }
 
+// OAuthOptions - optional provider specific settings for an OAuth authentication
+type OAuthOptions struct {
+	// Scopes (optional, nil) - additional scopes to request from the provider, on top of the ones configured in the project settings.
+	Scopes []string
+	// Prompt (optional, nil) - the prompt values to send to the provider (e.g. "consent", "select_account").
+	Prompt []string
+	// LoginHint (optional, "") - a hint for the provider about the identifier the user might use to log in.
+	LoginHint string
+	// BindState (optional, false) - bind the flow to the browser that started it, against login CSRF and code injection.
+	// A state value is added to the redirect URL and a PKCE code challenge to the request, and the code verifier is kept
+	// in a short-lived cookie written to the response writer, which is then required. The flow must be finalized with
+	// ExchangeTokenFromRequest, which rejects callbacks whose state does not match a flow started in the same browser.
+	BindState bool
+}
+
+// SAMLOptions - optional settings for a SAML authentication
+type SAMLOptions struct {
+	// BindState (optional, false) - bind the flow to the browser that started it, same as OAuthOptions.BindState.
+	BindState bool
+}
+
+// SSOTenantResolver - resolves the tenant of an email domain for SAML.StartWithEmail
+type SSOTenantResolver interface {
+	// ResolveSSOTenant - returns the ID of the SSO-enabled tenant whose self-provisioning domains include the given
+	// lower cased domain, or an empty ID when no such tenant matches.
+	ResolveSSOTenant(domain string) (tenantID string, err error)
+}
+
+// WaitForSessionOptions - configures how MagicLink.WaitForSession and EnchantedLink.WaitForSession poll for a pending session
+type WaitForSessionOptions struct {
+	// Interval (optional, 2 seconds) - the time to wait between the first polls.
+	Interval time.Duration
+	// Backoff (optional, 1) - the factor the interval is multiplied by after every poll, 1 means a fixed interval.
+	Backoff float64
+	// MaxInterval (optional, 10 seconds) - the maximal time to wait between polls when using a backoff.
+	MaxInterval time.Duration
+	// Timeout (optional, 10 minutes) - the total time to wait for the session, on top of the given context deadline.
+	Timeout time.Duration
+}
+
 type LoginOptions struct {
 	Stepup       bool                   `json:"stepup,omitempty"`
 	MFA          bool                   `json:"mfa,omitempty"`
@@ -122,14 +174,32 @@ func (lo *LoginOptions) IsJWTRequired() bool {
 }
 
 type JWTResponse struct {
-	SessionJwt       string        `json:"sessionJwt,omitempty"`
-	RefreshJwt       string        `json:"refreshJwt,omitempty"`
-	CookieDomain     string        `json:"cookieDomain,omitempty"`
-	CookiePath       string        `json:"cookiePath,omitempty"`
-	CookieMaxAge     int32         `json:"cookieMaxAge,omitempty"`
-	CookieExpiration int32         `json:"cookieExpiration,omitempty"`
-	User             *UserResponse `json:"user,omitempty"`
-	FirstSeen        bool          `json:"firstSeen,omitempty"`
+	SessionJwt       string         `json:"sessionJwt,omitempty"`
+	RefreshJwt       string         `json:"refreshJwt,omitempty"`
+	CookieDomain     string         `json:"cookieDomain,omitempty"`
+	CookiePath       string         `json:"cookiePath,omitempty"`
+	CookieMaxAge     int32          `json:"cookieMaxAge,omitempty"`
+	CookieExpiration int32          `json:"cookieExpiration,omitempty"`
+	User             *UserResponse  `json:"user,omitempty"`
+	FirstSeen        bool           `json:"firstSeen,omitempty"`
+	ProviderToken    *ProviderToken `json:"providerToken,omitempty"`
+}
+
+// PasswordPolicy - the password requirements configured in the project settings
+type PasswordPolicy struct {
+	MinLength       int32 `json:"minLength,omitempty"`
+	Lowercase       bool  `json:"lowercase,omitempty"`
+	Uppercase       bool  `json:"uppercase,omitempty"`
+	Number          bool  `json:"number,omitempty"`
+	NonAlphanumeric bool  `json:"nonAlphanumeric,omitempty"`
+}
+
+// EnchantedLinkResponse - returned when an enchanted link is sent, the link ID should be displayed to the user
+// so they can pick the matching link from the email.
+type EnchantedLinkResponse struct {
+	PendingRef  string `json:"pendingRef,omitempty"`  // Pending referral code used to poll enchanted link authentication status
+	LinkID      string `json:"linkId,omitempty"`      // Link ID the user should pick in the email
+	MaskedEmail string `json:"maskedEmail,omitempty"` // Masked email the links were sent to
 }
 
 type MagicLinkResponse struct {
@@ -140,7 +210,7 @@ func NewAuthenticationInfo(jRes *JWTResponse, token *Token) *AuthenticationInfo
 	if jRes == nil {
 		jRes = &JWTResponse{}
 	}
-	return &AuthenticationInfo{SessionToken: token, User: jRes.User, FirstSeen: jRes.FirstSeen}
+	return &AuthenticationInfo{SessionToken: token, User: jRes.User, FirstSeen: jRes.FirstSeen, ProviderToken: jRes.ProviderToken}
 }
 
 func NewToken(JWT string, token jwt.Token) *Token {
@@ -263,6 +333,43 @@ type magicLinkUpdatePhoneRequestBody struct {
 	CrossDevice bool   `json:"crossDevice,omitempty"`
 }
 
+type enchantedLinkAuthenticationRequestBody struct {
+	*authenticationRequestBody `json:",inline"`
+	URI                        string `json:"URI,omitempty"`
+}
+
+type enchantedLinkAuthenticationSignUpRequestBody struct {
+	*authenticationSignUpRequestBody `json:",inline"`
+	URI                              string `json:"URI,omitempty"`
+}
+
+type passwordSignUpRequestBody struct {
+	ExternalID string `json:"externalId,omitempty"`
+	User       *User  `json:"user"`
+	Password   string `json:"password"`
+}
+
+type passwordSignInRequestBody struct {
+	*authenticationRequestBody `json:",inline"`
+	Password                   string `json:"password"`
+}
+
+type passwordResetRequestBody struct {
+	ExternalID  string `json:"externalId,omitempty"`
+	RedirectURL string `json:"redirectUrl,omitempty"`
+}
+
+type passwordUpdateRequestBody struct {
+	ExternalID  string `json:"externalId,omitempty"`
+	NewPassword string `json:"newPassword"`
+}
+
+type passwordReplaceRequestBody struct {
+	ExternalID  string `json:"externalId,omitempty"`
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+}
+
 type magicLinkAuthenticationVerifyRequestBody struct {
 	Token string `json:"token"`
 }
@@ -272,7 +379,8 @@ type authenticationGetMagicLinkSessionBody struct {
 }
 
 type exchangeTokenBody struct {
-	Code string `json:"code"`
+	Code         string `json:"code"`
+	CodeVerifier string `json:"codeVerifier,omitempty"`
 }
 
 func newSignInRequestBody(externalID string, loginOptions *LoginOptions) *authenticationRequestBody {
@@ -281,10 +389,12 @@ func newSignInRequestBody(externalID string, loginOptions *LoginOptions) *authen
 
 func newSignUpRequestBody(method DeliveryMethod, user *User) *authenticationSignUpRequestBody {
 	switch method {
-	case MethodSMS:
+	case MethodSMS, MethodVoice:
 		return &authenticationSignUpRequestBody{Phone: user.Phone}
 	case MethodWhatsApp:
 		return &authenticationSignUpRequestBody{WhatsApp: user.Phone}
+	case MethodEmbedded:
+		return &authenticationSignUpRequestBody{}
 	}
 
 	return &authenticationSignUpRequestBody{Email: user.Email}
@@ -313,6 +423,34 @@ func newMagicLinkAuthenticationSignUpRequestBody(method DeliveryMethod, external
 	return &magicLinkAuthenticationSignUpRequestBody{authenticationSignUpRequestBody: b, CrossDevice: crossDevice, URI: URI}
 }
 
+func newEnchantedLinkAuthenticationRequestBody(value, URI string, loginOptions *LoginOptions) *enchantedLinkAuthenticationRequestBody {
+	return &enchantedLinkAuthenticationRequestBody{authenticationRequestBody: newSignInRequestBody(value, loginOptions), URI: URI}
+}
+
+func newEnchantedLinkAuthenticationSignUpRequestBody(externalID, URI string, user *User) *enchantedLinkAuthenticationSignUpRequestBody {
+	return &enchantedLinkAuthenticationSignUpRequestBody{authenticationSignUpRequestBody: newAuthenticationSignUpRequestBody(MethodEmail, externalID, user), URI: URI}
+}
+
+func newPasswordSignUpRequestBody(externalID string, user *User, password string) *passwordSignUpRequestBody {
+	return &passwordSignUpRequestBody{ExternalID: externalID, User: user, Password: password}
+}
+
+func newPasswordSignInRequestBody(externalID, password string, loginOptions *LoginOptions) *passwordSignInRequestBody {
+	return &passwordSignInRequestBody{authenticationRequestBody: newSignInRequestBody(externalID, loginOptions), Password: password}
+}
+
+func newPasswordResetRequestBody(externalID, redirectURL string) *passwordResetRequestBody {
+	return &passwordResetRequestBody{ExternalID: externalID, RedirectURL: redirectURL}
+}
+
+func newPasswordUpdateRequestBody(externalID, newPassword string) *passwordUpdateRequestBody {
+	return &passwordUpdateRequestBody{ExternalID: externalID, NewPassword: newPassword}
+}
+
+func newPasswordReplaceRequestBody(externalID, oldPassword, newPassword string) *passwordReplaceRequestBody {
+	return &passwordReplaceRequestBody{ExternalID: externalID, OldPassword: oldPassword, NewPassword: newPassword}
+}
+
 func newMagicLinkAuthenticationVerifyRequestBody(token string) *magicLinkAuthenticationVerifyRequestBody {
 	return &magicLinkAuthenticationVerifyRequestBody{Token: token}
 }
@@ -344,8 +482,8 @@ func newAuthenticationGetMagicLinkSessionBody(pendingRef string) *authentication
 	return &authenticationGetMagicLinkSessionBody{PendingRef: pendingRef}
 }
 
-func newExchangeTokenBody(code string) *exchangeTokenBody {
-	return &exchangeTokenBody{Code: code}
+func newExchangeTokenBody(code, codeVerifier string) *exchangeTokenBody {
+	return &exchangeTokenBody{Code: code, CodeVerifier: codeVerifier}
 }
 
 type DeliveryMethod string
@@ -358,6 +496,12 @@ const (
 	MethodWhatsApp DeliveryMethod = "whatsapp"
 	MethodSMS      DeliveryMethod = "sms"
 	MethodEmail    DeliveryMethod = "email"
+	// MethodVoice - the OTP code is read to the user in a phone call
+	MethodVoice DeliveryMethod = "voice"
+	// MethodEmbedded - the OTP code is not delivered by Descope but returned by the OTP functions with the Embedded
+	// suffix, the application is responsible for delivering it to the user (e.g. in its own email or push notification),
+	// so the identifier can be any login ID
+	MethodEmbedded DeliveryMethod = "embedded"
 
 	OAuthFacebook  OAuthProvider = "facebook"
 	OAuthGithub    OAuthProvider = "github"
@@ -365,22 +509,23 @@ const (
 	OAuthMicrosoft OAuthProvider = "microsoft"
 	OAuthGitlab    OAuthProvider = "gitlab"
 	OAuthApple     OAuthProvider = "apple"
+	OAuthDiscord   OAuthProvider = "discord"
+	OAuthLinkedIn  OAuthProvider = "linkedin"
+	OAuthSlack     OAuthProvider = "slack"
 
 	SessionCookieName = "DS"
 	RefreshCookieName = "DSR"
 
 	RedirectLocationCookieName = "Location"
+	RedirectStateCookiePrefix  = "DSRS_"
 
 	ContextUserIDProperty               = "DESCOPE_USER_ID"
 	ContextUserIDPropertyKey ContextKey = ContextUserIDProperty
+	ContextTokenProperty                = "DESCOPE_TOKEN"
+	ContextTokenPropertyKey  ContextKey = ContextTokenProperty
 	ClaimAuthorizedTenants              = "tenants"
 
 	claimAttributeName = "drn"
 	claimPermissions   = "permissions"
 	claimRoles         = "roles"
 )
-
-var (
-	phoneRegex = regexp.MustCompile(`^(?:(?:\(?(?:00|\+)([1-4]\d\d|[1-9]\d?)\)?)?[\-\.\ \\\/]?)?((?:\(?\d{1,}\)?[\-\.\ \\\/]?){0,})(?:[\-\.\ \\\/]?(?:#|ext\.?|extension|x)[\-\.\ \\\/]?(\d+))?$`)
-	emailRegex = regexp.MustCompile("^(((([a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+(\\.([a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+)*)|((\\x22)((((\\x20|\\x09)*(\\x0d\\x0a))?(\\x20|\\x09)+)?(([\\x01-\\x08\\x0b\\x0c\\x0e-\\x1f\\x7f]|\\x21|[\\x23-\\x5b]|[\\x5d-\\x7e]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(\\([\\x01-\\x09\\x0b\\x0c\\x0d-\\x7f]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}]))))*(((\\x20|\\x09)*(\\x0d\\x0a))?(\\x20|\\x09)+)?(\\x22)))@((([a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(([a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])([a-zA-Z]|\\d|-|\\.|_|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*([a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.)+(([a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(([a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])([a-zA-Z]|\\d|-|_|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*([a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.?$")
-)
@@ -51,7 +51,7 @@ func (auth *webAuthn) SignInStart(identifier string, origin string, r *http.Requ
 		}
 	}
 
-	res, err := auth.client.DoPostRequest(api.Routes.WebAuthnSignInStart(), authenticationWebAuthnSignInRequestBody{ExternalID: identifier, Origin: origin, LoginOptions: loginOptions}, nil, pswd)
+	res, err := auth.clientFor(r).DoPostRequest(api.Routes.WebAuthnSignInStart(), authenticationWebAuthnSignInRequestBody{ExternalID: identifier, Origin: origin, LoginOptions: loginOptions}, nil, pswd)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +95,7 @@ func (auth *webAuthn) UpdateUserDeviceStart(identifier string, origin string, r
 		return nil, err
 	}
 
-	res, err := auth.client.DoPostRequest(api.Routes.WebAuthnUpdateUserDeviceStart(), authenticationWebAuthnAddDeviceRequestBody{ExternalID: identifier, Origin: origin}, nil, pswd)
+	res, err := auth.clientFor(r).DoPostRequest(api.Routes.WebAuthnUpdateUserDeviceStart(), authenticationWebAuthnAddDeviceRequestBody{ExternalID: identifier, Origin: origin}, nil, pswd)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,89 @@
+package chi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/go-chi/chi/v5"
+)
+
+// AuthenticationMiddleware - middleware used to validate session and invoke if provided a failure and
+// success callbacks after calling ValidateSession().
+// onFailure will be called when the authentication failed, if empty, will write unauthorized (401) on the response writer.
+// onSuccess will be called when the authentication succeeded, if empty, it will store the validated token in the
+// request context (see auth.TokenFromContext) and runs next.
+func AuthenticationMiddleware(client auth.Authentication, onFailure func(http.ResponseWriter, *http.Request, error), onSuccess func(http.ResponseWriter, *http.Request, http.Handler, *auth.Token)) func(next http.Handler) http.Handler {
+	return auth.AuthenticationMiddleware(client, onFailure, onSuccess)
+}
+
+// RefreshMiddleware - middleware used to force a refresh of the session by calling RefreshSession(), the
+// new session cookies are written to the response. Uses the same callback semantics as AuthenticationMiddleware.
+func RefreshMiddleware(client auth.Authentication, onFailure func(http.ResponseWriter, *http.Request, error), onSuccess func(http.ResponseWriter, *http.Request, http.Handler, *auth.Token)) func(next http.Handler) http.Handler {
+	return auth.AuthenticationMiddleware(refreshValidator{client: client}, onFailure, onSuccess)
+}
+
+// RolesMiddleware - middleware used to ensure that the token stored by AuthenticationMiddleware has been
+// granted the given roles, so it must be used after it.
+// tenantParam is an optional chi URL parameter name that holds the tenant to validate the roles for,
+// if empty, the roles are validated at the project level.
+// onFailure will be called when the validation failed, if empty, will write unauthorized (401) when there is no
+// token and forbidden (403) when the roles are missing, with an error body.
+func RolesMiddleware(client auth.Authentication, tenantParam string, roles []string, onFailure func(http.ResponseWriter, *http.Request, error)) func(next http.Handler) http.Handler {
+	return authorizationMiddleware(tenantParam, onFailure, func(token *auth.Token, tenant string) bool {
+		return client.ValidateTenantRoles(token, tenant, roles)
+	})
+}
+
+// PermissionsMiddleware - middleware used to ensure that the token stored by AuthenticationMiddleware has been
+// granted the given permissions, so it must be used after it.
+// tenantParam and onFailure follow the same convention as those for RolesMiddleware.
+func PermissionsMiddleware(client auth.Authentication, tenantParam string, permissions []string, onFailure func(http.ResponseWriter, *http.Request, error)) func(next http.Handler) http.Handler {
+	return authorizationMiddleware(tenantParam, onFailure, func(token *auth.Token, tenant string) bool {
+		return client.ValidateTenantPermissions(token, tenant, permissions)
+	})
+}
+
+// refreshValidator adapts RefreshSession to the session validation of auth.AuthenticationMiddleware
+type refreshValidator struct {
+	client auth.Authentication
+}
+
+func (v refreshValidator) ValidateSession(r *http.Request, w http.ResponseWriter) (bool, *auth.Token, error) {
+	return v.client.RefreshSession(r, w)
+}
+
+func authorizationMiddleware(tenantParam string, onFailure func(http.ResponseWriter, *http.Request, error), validate func(*auth.Token, string) bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := auth.TokenFromContext(r.Context())
+			if !ok {
+				failAuthorization(w, r, http.StatusUnauthorized, errors.NewUnauthorizedError(), onFailure)
+				return
+			}
+			if !validate(token, tenantFromRequest(r, tenantParam)) {
+				failAuthorization(w, r, http.StatusForbidden, errors.NewForbiddenError(), onFailure)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func failAuthorization(w http.ResponseWriter, r *http.Request, status int, err *errors.WebError, onFailure func(http.ResponseWriter, *http.Request, error)) {
+	if onFailure != nil {
+		onFailure(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(err)
+}
+
+func tenantFromRequest(r *http.Request, tenantParam string) string {
+	if tenantParam == "" {
+		return ""
+	}
+	return chi.URLParam(r, tenantParam)
+}
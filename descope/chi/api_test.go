@@ -0,0 +1,115 @@
+package chi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/descopemock"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, handler http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func assertWebError(t *testing.T, w *httptest.ResponseRecorder, status int, expected *errors.WebError) {
+	assert.EqualValues(t, status, w.Code)
+	assert.EqualValues(t, "application/json", w.Header().Get("Content-Type"))
+	webErr := &errors.WebError{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), webErr))
+	assert.Equal(t, expected, webErr)
+}
+
+func tokenHandler(t *testing.T, expected *auth.Token) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := auth.TokenFromContext(r.Context())
+		require.True(t, ok)
+		assert.Equal(t, expected, token)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateSession(true, token, nil)
+	authMock.QueueValidateSession(false, nil, errors.UnauthorizedError)
+
+	r := chi.NewRouter()
+	r.Use(AuthenticationMiddleware(authMock, nil, nil))
+	r.Get("/", tokenHandler(t, token).ServeHTTP)
+	assert.EqualValues(t, http.StatusOK, serve(t, r, "/").Code)
+	assert.EqualValues(t, http.StatusUnauthorized, serve(t, r, "/").Code)
+	assert.Len(t, authMock.CallsTo("ValidateSession"), 2)
+}
+
+func TestRefreshMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueRefreshSession(true, token, nil)
+	authMock.QueueRefreshSession(false, nil, errors.UnauthorizedError)
+
+	var failure error
+	r := chi.NewRouter()
+	r.Use(RefreshMiddleware(authMock, func(w http.ResponseWriter, r *http.Request, err error) {
+		failure = err
+		w.WriteHeader(http.StatusTeapot)
+	}, nil))
+	r.Get("/", tokenHandler(t, token).ServeHTTP)
+	assert.EqualValues(t, http.StatusOK, serve(t, r, "/").Code)
+	assert.EqualValues(t, http.StatusTeapot, serve(t, r, "/").Code)
+	assert.ErrorIs(t, failure, errors.UnauthorizedError)
+	assert.Len(t, authMock.CallsTo("RefreshSession"), 2)
+	assert.Empty(t, authMock.CallsTo("ValidateSession"))
+}
+
+func TestRolesMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.ValidateSessionFunc = func(_ *http.Request, _ http.ResponseWriter) (bool, *auth.Token, error) {
+		return true, token, nil
+	}
+	authMock.QueueValidateTenantRoles(true)
+	authMock.QueueValidateTenantRoles(false)
+
+	r := chi.NewRouter()
+	r.With(RolesMiddleware(authMock, "tenant", []string{"admin"}, nil)).Get("/unauthenticated/{tenant}", tokenHandler(t, token).ServeHTTP)
+	r.With(AuthenticationMiddleware(authMock, nil, nil), RolesMiddleware(authMock, "tenant", []string{"admin"}, nil)).Get("/{tenant}", tokenHandler(t, token).ServeHTTP)
+	assert.EqualValues(t, http.StatusOK, serve(t, r, "/t1").Code)
+	assertWebError(t, serve(t, r, "/t1"), http.StatusForbidden, errors.ForbiddenError)
+	assertWebError(t, serve(t, r, "/unauthenticated/t1"), http.StatusUnauthorized, errors.UnauthorizedError)
+
+	calls := authMock.CallsTo("ValidateTenantRoles")
+	require.Len(t, calls, 2)
+	assert.EqualValues(t, "t1", calls[0].Args[1])
+	assert.EqualValues(t, []string{"admin"}, calls[0].Args[2])
+}
+
+func TestPermissionsMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateTenantPermissions(true)
+	authMock.QueueValidateTenantPermissions(false)
+
+	handler := PermissionsMiddleware(authMock, "", []string{"read"}, nil)(tokenHandler(t, token))
+	request := func() int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r.WithContext(auth.ContextWithToken(r.Context(), token)))
+		return w.Code
+	}
+	assert.EqualValues(t, http.StatusOK, request())
+	assert.EqualValues(t, http.StatusForbidden, request())
+
+	calls := authMock.CallsTo("ValidateTenantPermissions")
+	require.Len(t, calls, 2)
+	assert.EqualValues(t, "", calls[0].Args[1])
+}
@@ -1,6 +1,7 @@
 package descope
 
 import (
+	"context"
 	"strings"
 
 	"github.com/descope/go-sdk/descope/api"
@@ -17,19 +18,71 @@ type Config struct {
 	ProjectID string
 	// PublicKey (optional, "") - used to override or implicitly use a dedicated public key in order to decrypt and validate the JWT tokens
 	// during ValidateSessionRequest(). If empty, will attempt to fetch all public keys from the specified project id.
+	// Accepts a single JWK, a JWK set, a JSON array of JWKs, or PEM encoded keys, see auth.ParsePublicKeys.
 	PublicKey string
+	// PublicKeys (optional, nil) - a pinned set of public keys used to validate the JWT tokens, in addition to PublicKey,
+	// such as a key bundle loaded with auth.LoadPublicKeys. The set can be updated while in use during key rotation.
+	PublicKeys *auth.PublicKeySet
+	// FetchPublicKeys (optional, false) - when PublicKey or PublicKeys are set, fetch the public keys of the project from
+	// Descope for tokens signed by a key that is not pinned, instead of rejecting them.
+	FetchPublicKeys bool
+	// ManagementKey (optional, "") - used by the SDK itself for authentication functions that need management APIs,
+	// such as SAML.StartWithEmail, which loads and caches the SSO-enabled tenants of the project to resolve the tenant of the email domain.
+	ManagementKey string
 	// DescopeBaseURL (optional, "https://api.descope.com") - override the default base URL used to communicate with descope services.
 	DescopeBaseURL string
 	// DefaultClient (optional, http.DefaultClient) - override the default client used to Do the actual http request.
 	DefaultClient api.IHttpClient
 	// CustomDefaultHeaders (optional, nil) - add custom headers to all requests used to communicate with descope services.
 	CustomDefaultHeaders map[string]string
+	// AuthRateLimit (optional, no limit) - limit the rate and concurrency of the requests sent by the authentication functions,
+	// requests wait for their turn until the limit allows them or their context is done, see AuthWithContext.
+	AuthRateLimit api.RateLimit
+	// ManagementRateLimit (optional, no limit) - limit the rate and concurrency of the requests sent by the management functions,
+	// such as to avoid being rate limited by Descope while creating many users in a batch job.
+	ManagementRateLimit api.RateLimit
+	// Middlewares (optional, nil) - wrap the sending of requests to descope services, such as to add tracing headers, logging or metrics,
+	// the first middleware wraps all the others. See api.HeadersMiddleware, api.LoggingMiddleware and api.FaultInjectionMiddleware.
+	Middlewares []api.Middleware
+	// Observers (optional, nil) - observe the requests sent to descope services and the sessions validated by the client,
+	// such as to trace or measure them, see the descope/otel and descope/prometheus modules.
+	Observers []Observer
+	// DefaultPhoneRegion (optional, "") - the ISO 3166-1 alpha-2 region (e.g. "US") of phone numbers given without a country
+	// calling code, used to normalize them to E.164 format. If empty, such numbers are sent unchanged.
+	DefaultPhoneRegion string
 	// LogLevel (optional, LogNone) - set a log level (Debug/Info/None) for the sdk to use when logging.
 	LogLevel logger.LogLevel
 	// LoggerInterface (optional, log.Default()) - set the logger instance to use for logging with the sdk.
 	Logger logger.LoggerInterface
 }
 
+// Observer - observes the requests a client sends to descope services and the sessions it validates
+type Observer interface {
+	api.RequestObserver
+	auth.ValidationObserver
+}
+
+// observers calls each of the observers in turn
+type observers []Observer
+
+func (o observers) ObserveRequest(event api.RequestEvent) {
+	for i := range o {
+		o[i].ObserveRequest(event)
+	}
+}
+
+func (o observers) ObserveValidation(event auth.ValidationEvent) {
+	for i := range o {
+		o[i].ObserveValidation(event)
+	}
+}
+
+func (o observers) ObserveKeysFetch(event auth.KeysEvent) {
+	for i := range o {
+		o[i].ObserveKeysFetch(event)
+	}
+}
+
 func (c *Config) setProjectID() string {
 	if c.ProjectID == "" {
 		if projectID := utils.GetProjectIDEnvVariable(); projectID != "" {
@@ -64,6 +117,7 @@ type DescopeClient struct {
 	Management mgmt.Management
 
 	config *Config
+	client *api.Client
 }
 
 // Creates a new DescopeClient object. The value for the Descope projectID must be set
@@ -80,19 +134,56 @@ func NewDescopeClientWithConfig(config *Config) (*DescopeClient, error) {
 		return nil, errors.NewInvalidArgumentError("config")
 	}
 	logger.Init(config.LogLevel, config.Logger)
+	config.setProjectID()
+	config.setPublicKey()
+	return newDescopeClient(config)
+}
 
-	if strings.TrimSpace(config.setProjectID()) == "" {
+// newDescopeClient creates a client with the given config, without reading the environment variables or initializing the logger
+func newDescopeClient(config *Config) (*DescopeClient, error) {
+	if strings.TrimSpace(config.ProjectID) == "" {
 		return nil, errors.NewValidationError("project id is missing. Make sure to add it in the Config struct or the environment variable \"%s\"", utils.EnvironmentVariableProjectID)
 	}
-	if config.setPublicKey() != "" {
+	if (config.PublicKey != "" || config.PublicKeys != nil) && !config.FetchPublicKeys {
 		logger.LogInfo("provided public key is set, forcing only provided public key validation")
 	}
-	c := api.NewClient(api.ClientParams{BaseURL: config.DescopeBaseURL, CustomDefaultHeaders: config.CustomDefaultHeaders, DefaultClient: config.DefaultClient, ProjectID: config.ProjectID})
+	var observer Observer
+	if len(config.Observers) > 0 {
+		observer = observers(config.Observers)
+	}
+	c := api.NewClient(api.ClientParams{BaseURL: config.DescopeBaseURL, CustomDefaultHeaders: config.CustomDefaultHeaders, DefaultClient: config.DefaultClient, AuthRateLimit: config.AuthRateLimit, ManagementRateLimit: config.ManagementRateLimit, Middlewares: config.Middlewares, Observer: observer, ProjectID: config.ProjectID})
 
-	authService, err := auth.NewAuth(auth.AuthParams{ProjectID: config.ProjectID, PublicKey: config.PublicKey}, c)
+	managementService := mgmt.NewManagement(mgmt.MgmtParams{ProjectID: config.ProjectID}, c)
+	var tenantResolver auth.SSOTenantResolver
+	if config.ManagementKey != "" {
+		tenantResolver = mgmt.NewSSOTenantResolver(managementService, config.ManagementKey, 0)
+	}
+	authService, err := auth.NewAuth(auth.AuthParams{ProjectID: config.ProjectID, PublicKey: config.PublicKey, PublicKeys: config.PublicKeys, FetchPublicKeys: config.FetchPublicKeys, DefaultRegion: config.DefaultPhoneRegion, Observer: observer, TenantResolver: tenantResolver}, c)
 	if err != nil {
 		return nil, err
 	}
-	managementService := mgmt.NewManagement(mgmt.MgmtParams{ProjectID: config.ProjectID}, c)
-	return &DescopeClient{Auth: authService, Management: managementService, config: config}, nil
+	return &DescopeClient{Auth: authService, Management: managementService, config: config, client: c}, nil
+}
+
+// AuthWithContext - returns the authentication functions that send their requests with the given context, so a canceled
+// context also ends waiting for the AuthRateLimit, and the context reaches the middlewares of the requests. Without it,
+// the functions that take an http request send their requests with its context, and the others with none. The public
+// keys and the rate limits are shared with the Auth of the client. Returns the Auth of the client when it was replaced,
+// such as with a mock.
+func (c *DescopeClient) AuthWithContext(ctx context.Context) auth.Authentication {
+	if a, ok := c.Auth.(interface {
+		WithContext(ctx context.Context) auth.Authentication
+	}); ok {
+		return a.WithContext(ctx)
+	}
+	return c.Auth
+}
+
+// ManagementWithContext - returns the management APIs that send their requests with the given context, so a canceled
+// context also ends waiting for the ManagementRateLimit. The rate limits are shared with the Management of the client.
+func (c *DescopeClient) ManagementWithContext(ctx context.Context) mgmt.Management {
+	if c.client == nil {
+		return c.Management
+	}
+	return mgmt.NewManagement(mgmt.MgmtParams{ProjectID: c.config.ProjectID}, c.client.WithContext(ctx))
 }
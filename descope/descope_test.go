@@ -1,11 +1,16 @@
 package descope
 
 import (
+	"context"
+	"net/http"
 	"os"
 	"testing"
 
+	"github.com/descope/go-sdk/descope/api"
 	"github.com/descope/go-sdk/descope/auth"
 	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/descopemock"
+	"github.com/descope/go-sdk/descope/tests/mocks"
 	"github.com/descope/go-sdk/descope/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -54,15 +59,117 @@ func TestEmptyConfig(t *testing.T) {
 }
 
 func TestDescopeSDKMock(t *testing.T) {
-	api := DescopeClient{
-		Auth: auth.MockDescopeAuthentication{
-			ValidateSessionResponseNotOK: true,
-			ValidateSessionResponseInfo:  &auth.Token{JWT: "test"},
-			ValidateSessionResponseError: errors.NoPublicKeyError,
-		},
-	}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateSession(false, &auth.Token{JWT: "test"}, errors.NoPublicKeyError)
+	api := DescopeClient{Auth: authMock}
 	ok, info, err := api.Auth.ValidateSession(nil, nil)
 	assert.False(t, ok)
 	assert.NotEmpty(t, info)
 	assert.ErrorIs(t, err, errors.NoPublicKeyError)
 }
+
+func TestManagementWithContext(t *testing.T) {
+	requests := 0
+	client, err := NewDescopeClientWithConfig(&Config{
+		ProjectID:           "a",
+		ManagementRateLimit: api.RateLimit{RequestsPerSecond: 0.1},
+		DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+			requests++
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Management.Tenant().Delete("key", "t1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.ManagementWithContext(ctx).Tenant().Delete("key", "t1")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.EqualValues(t, 1, requests)
+
+	mock := DescopeClient{Management: client.Management}
+	assert.Equal(t, client.Management, mock.ManagementWithContext(ctx))
+}
+
+func TestAuthWithContext(t *testing.T) {
+	requests := 0
+	client, err := NewDescopeClientWithConfig(&Config{
+		ProjectID:     "a",
+		AuthRateLimit: api.RateLimit{RequestsPerSecond: 0.1},
+		DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+			requests++
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Auth.OTP().SignUpOrIn(auth.MethodEmail, "dude@example.com"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.AuthWithContext(ctx).OTP().SignUpOrIn(auth.MethodEmail, "dude@example.com")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.EqualValues(t, 1, requests)
+
+	authMock := descopemock.NewAuthentication()
+	mock := DescopeClient{Auth: authMock}
+	assert.Equal(t, authMock, mock.AuthWithContext(ctx))
+}
+
+func TestConfigMiddlewares(t *testing.T) {
+	called := false
+	client, err := NewDescopeClientWithConfig(&Config{
+		ProjectID: "a",
+		Middlewares: []api.Middleware{func(next api.Doer) api.Doer {
+			return api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				called = true
+				return next.Do(req)
+			})
+		}},
+		DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Management.Tenant().Delete("key", "t1"))
+	assert.True(t, called)
+}
+
+type testObserver struct {
+	requests    []api.RequestEvent
+	validations []auth.ValidationEvent
+	fetches     []auth.KeysEvent
+}
+
+func (o *testObserver) ObserveRequest(event api.RequestEvent) {
+	o.requests = append(o.requests, event)
+}
+
+func (o *testObserver) ObserveValidation(event auth.ValidationEvent) {
+	o.validations = append(o.validations, event)
+}
+
+func (o *testObserver) ObserveKeysFetch(event auth.KeysEvent) {
+	o.fetches = append(o.fetches, event)
+}
+
+func TestConfigObservers(t *testing.T) {
+	first, second := &testObserver{}, &testObserver{}
+	client, err := NewDescopeClientWithConfig(&Config{
+		ProjectID: "a",
+		Observers: []Observer{first, second},
+		DefaultClient: mocks.NewTestClient(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+	})
+	require.NoError(t, err)
+	ok, _, _ := client.Auth.ValidateSessionTokens("not a jwt", "")
+	assert.False(t, ok)
+	require.NoError(t, client.Management.Tenant().Delete("key", "t1"))
+
+	for _, o := range []*testObserver{first, second} {
+		require.Len(t, o.validations, 1)
+		assert.EqualValues(t, "a", o.validations[0].ProjectID)
+		require.NotEmpty(t, o.requests)
+		assert.EqualValues(t, api.Routes.ManagementTenantDelete(), o.requests[len(o.requests)-1].Route)
+	}
+}
@@ -0,0 +1,123 @@
+package echo
+
+import (
+	"net/http"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// AuthenticationMiddleware - middleware used to validate session and invoke if provided a failure and
+// success callbacks after calling ValidateSession().
+// onFailure will be called when the authentication failed, if empty, will respond with unauthorized (401) and an error body.
+// onSuccess will be called when the authentication succeeded, if empty, it will store the validated token and the descope
+// user id on the echo context and the request context (see TokenFromContext) and runs next.
+func AuthenticationMiddleware(client auth.Authentication, onFailure func(echo.Context, error) error, onSuccess func(echo.Context, echo.HandlerFunc, *auth.Token) error) echo.MiddlewareFunc {
+	return sessionMiddleware(client.ValidateSession, onFailure, onSuccess)
+}
+
+// RefreshMiddleware - middleware used to force a refresh of the session by calling RefreshSession(), the
+// new session cookies are written to the response. Uses the same callback semantics as AuthenticationMiddleware.
+func RefreshMiddleware(client auth.Authentication, onFailure func(echo.Context, error) error, onSuccess func(echo.Context, echo.HandlerFunc, *auth.Token) error) echo.MiddlewareFunc {
+	return sessionMiddleware(client.RefreshSession, onFailure, onSuccess)
+}
+
+// RolesMiddleware - middleware used to ensure that the token stored by AuthenticationMiddleware has been
+// granted the given roles, so it must be used after it.
+// tenantParam is an optional echo path parameter name that holds the tenant to validate the roles for,
+// if empty, the roles are validated at the project level.
+// onFailure will be called when the validation failed, if empty, will respond with unauthorized (401) when there is no
+// token and forbidden (403) when the roles are missing.
+func RolesMiddleware(client auth.Authentication, tenantParam string, roles []string, onFailure func(echo.Context, error) error) echo.MiddlewareFunc {
+	return authorizationMiddleware(tenantParam, onFailure, func(token *auth.Token, tenant string) bool {
+		return client.ValidateTenantRoles(token, tenant, roles)
+	})
+}
+
+// PermissionsMiddleware - middleware used to ensure that the token stored by AuthenticationMiddleware has been
+// granted the given permissions, so it must be used after it.
+// tenantParam and onFailure follow the same convention as those for RolesMiddleware.
+func PermissionsMiddleware(client auth.Authentication, tenantParam string, permissions []string, onFailure func(echo.Context, error) error) echo.MiddlewareFunc {
+	return authorizationMiddleware(tenantParam, onFailure, func(token *auth.Token, tenant string) bool {
+		return client.ValidateTenantPermissions(token, tenant, permissions)
+	})
+}
+
+// SetToken - stores the validated token and its user id on the echo context, and on the request context
+// so that auth.TokenFromContext works for handlers that only receive the *http.Request.
+func SetToken(c echo.Context, token *auth.Token) {
+	if token == nil {
+		return
+	}
+	c.Set(auth.ContextUserIDProperty, token.ID)
+	c.Set(auth.ContextTokenProperty, token)
+	c.SetRequest(c.Request().WithContext(auth.ContextWithToken(c.Request().Context(), token)))
+}
+
+// TokenFromContext - returns the validated token stored by AuthenticationMiddleware.
+// returns false if the request was not authenticated by the middleware.
+func TokenFromContext(c echo.Context) (*auth.Token, bool) {
+	if token, ok := c.Get(auth.ContextTokenProperty).(*auth.Token); ok && token != nil {
+		return token, true
+	}
+	if c.Request() == nil {
+		return nil, false
+	}
+	return auth.TokenFromContext(c.Request().Context())
+}
+
+// UserIDFromContext - returns the descope user id stored by AuthenticationMiddleware, or an empty string.
+func UserIDFromContext(c echo.Context) string {
+	userID, _ := c.Get(auth.ContextUserIDProperty).(string)
+	return userID
+}
+
+func sessionMiddleware(validate func(*http.Request, http.ResponseWriter) (bool, *auth.Token, error), onFailure func(echo.Context, error) error, onSuccess func(echo.Context, echo.HandlerFunc, *auth.Token) error) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ok, token, err := validate(c.Request(), c.Response())
+			if !ok {
+				if err != nil {
+					logger.LogError("request failed because token is invalid", err)
+				}
+				if onFailure != nil {
+					return onFailure(c, err)
+				}
+				return c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError())
+			}
+			if onSuccess != nil {
+				return onSuccess(c, next, token)
+			}
+			SetToken(c, token)
+			return next(c)
+		}
+	}
+}
+
+func authorizationMiddleware(tenantParam string, onFailure func(echo.Context, error) error, validate func(*auth.Token, string) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := TokenFromContext(c)
+			if !ok {
+				return failAuthorization(c, http.StatusUnauthorized, errors.NewUnauthorizedError(), onFailure)
+			}
+			tenant := ""
+			if tenantParam != "" {
+				tenant = c.Param(tenantParam)
+			}
+			if !validate(token, tenant) {
+				return failAuthorization(c, http.StatusForbidden, errors.NewForbiddenError(), onFailure)
+			}
+			return next(c)
+		}
+	}
+}
+
+func failAuthorization(c echo.Context, status int, err *errors.WebError, onFailure func(echo.Context, error) error) error {
+	if onFailure != nil {
+		return onFailure(c, err)
+	}
+	return c.JSON(status, err)
+}
@@ -0,0 +1,103 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/descopemock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(e *echo.Echo, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func tokenHandler(t *testing.T, expected *auth.Token) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token, ok := TokenFromContext(c)
+		require.True(t, ok)
+		assert.Equal(t, expected, token)
+		assert.EqualValues(t, expected.ID, UserIDFromContext(c))
+		requestToken, ok := auth.TokenFromContext(c.Request().Context())
+		require.True(t, ok)
+		assert.Equal(t, expected, requestToken)
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateSession(true, token, nil)
+	authMock.QueueValidateSession(false, nil, errors.UnauthorizedError)
+
+	e := echo.New()
+	e.GET("/", tokenHandler(t, token), AuthenticationMiddleware(authMock, nil, nil))
+	assert.EqualValues(t, http.StatusOK, serve(e, "/").Code)
+	w := serve(e, "/")
+	assert.EqualValues(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), errors.UnauthorizedError.Code)
+}
+
+func TestRefreshMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueRefreshSession(true, token, nil)
+	authMock.QueueRefreshSession(false, nil, errors.UnauthorizedError)
+
+	var failure error
+	e := echo.New()
+	e.GET("/", tokenHandler(t, token), RefreshMiddleware(authMock, func(c echo.Context, err error) error {
+		failure = err
+		return c.NoContent(http.StatusTeapot)
+	}, nil))
+	assert.EqualValues(t, http.StatusOK, serve(e, "/").Code)
+	assert.EqualValues(t, http.StatusTeapot, serve(e, "/").Code)
+	assert.ErrorIs(t, failure, errors.UnauthorizedError)
+	assert.Empty(t, authMock.CallsTo("ValidateSession"))
+}
+
+func TestRolesMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.ValidateSessionFunc = func(_ *http.Request, _ http.ResponseWriter) (bool, *auth.Token, error) {
+		return true, token, nil
+	}
+	authMock.QueueValidateTenantRoles(true)
+	authMock.QueueValidateTenantRoles(false)
+
+	e := echo.New()
+	e.GET("/unauthenticated/:tenant", tokenHandler(t, token), RolesMiddleware(authMock, "tenant", []string{"admin"}, nil))
+	e.GET("/:tenant", tokenHandler(t, token), AuthenticationMiddleware(authMock, nil, nil), RolesMiddleware(authMock, "tenant", []string{"admin"}, nil))
+	assert.EqualValues(t, http.StatusOK, serve(e, "/t1").Code)
+	assert.EqualValues(t, http.StatusForbidden, serve(e, "/t1").Code)
+	assert.EqualValues(t, http.StatusUnauthorized, serve(e, "/unauthenticated/t1").Code)
+
+	calls := authMock.CallsTo("ValidateTenantRoles")
+	require.Len(t, calls, 2)
+	assert.EqualValues(t, "t1", calls[0].Args[1])
+	assert.EqualValues(t, []string{"admin"}, calls[0].Args[2])
+}
+
+func TestPermissionsMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateSession(true, token, nil)
+	authMock.QueueValidateTenantPermissions(false)
+
+	var failure error
+	e := echo.New()
+	e.GET("/", tokenHandler(t, token), AuthenticationMiddleware(authMock, nil, nil), PermissionsMiddleware(authMock, "", []string{"read"}, func(c echo.Context, err error) error {
+		failure = err
+		return c.NoContent(http.StatusTeapot)
+	}))
+	assert.EqualValues(t, http.StatusTeapot, serve(e, "/").Code)
+	assert.ErrorIs(t, failure, errors.ForbiddenError)
+}
@@ -3,7 +3,8 @@ package errors
 import "fmt"
 
 const (
-	BadRequestErrorCode = "E01000"
+	BadRequestErrorCode        = "E01000"
+	SSOTenantNotFoundErrorCode = "E062101"
 )
 
 var (
@@ -14,10 +15,20 @@ var (
 	InvalidPendingRefError     = NewValidationError("Invalid pending reference")
 	InvalidAccessKeyResponse   = NewValidationError("invalid access key response received")
 	MagicLinkUnauthorized      = NewValidationError("pending session token")
+	EnchantedLinkUnauthorized  = NewValidationError("enchanted link pending session token")
 	UnauthorizedError          = NewError(BadRequestErrorCode, "unauthorized access")
+	ForbiddenError             = NewError(BadRequestErrorCode, "insufficient roles or permissions")
 	MissingRequestError        = NewValidationError("nil request provided")
 	MissingResponseWriterError = NewValidationError("nil response writer provided")
 	InvalidStepupJwtError      = NewValidationError("refresh JWT must be provided for stepup actions")
+	InvalidStateError          = NewValidationError("authentication state is missing or does not match")
+	SSOTenantNotFoundError     = NewError(SSOTenantNotFoundErrorCode, "no tenant with SSO configured matches the email domain")
+	UnknownProjectError        = NewValidationError("no project was added for the issuer of the token")
+)
+
+var (
+	InvalidEmbeddedCodeResponse   = NewValidationError("invalid embedded code response received")
+	MissingSSOTenantResolverError = NewValidationError("a management key is required to resolve the tenant of an email domain")
 )
 
 type WebError struct {
@@ -38,6 +49,10 @@ func NewUnauthorizedError() *WebError {
 	return UnauthorizedError
 }
 
+func NewForbiddenError() *WebError {
+	return ForbiddenError
+}
+
 func NewNoPublicKeyError() *PublicKeyValidationError {
 	return NoPublicKeyError
 }
@@ -0,0 +1,150 @@
+package fiber
+
+import (
+	"net/http"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// AuthenticationMiddleware - middleware used to validate session and invoke if provided a failure and
+// success callbacks after calling ValidateSession().
+// onFailure will be called when the authentication failed, if empty, will respond with unauthorized (401) and an error body.
+// onSuccess will be called when the authentication succeeded, if empty, it will store the validated token and the descope
+// user id on the fiber context locals and user context (see TokenFromContext) and runs next.
+func AuthenticationMiddleware(client auth.Authentication, onFailure func(*fiber.Ctx, error) error, onSuccess func(*fiber.Ctx, *auth.Token) error) fiber.Handler {
+	return sessionMiddleware(client.ValidateSession, onFailure, onSuccess)
+}
+
+// RefreshMiddleware - middleware used to force a refresh of the session by calling RefreshSession(), the
+// new session cookies are written to the response. Uses the same callback semantics as AuthenticationMiddleware.
+func RefreshMiddleware(client auth.Authentication, onFailure func(*fiber.Ctx, error) error, onSuccess func(*fiber.Ctx, *auth.Token) error) fiber.Handler {
+	return sessionMiddleware(client.RefreshSession, onFailure, onSuccess)
+}
+
+// RolesMiddleware - middleware used to ensure that the token stored by AuthenticationMiddleware has been
+// granted the given roles, so it must be used after it.
+// tenantParam is an optional fiber route parameter name that holds the tenant to validate the roles for,
+// if empty, the roles are validated at the project level.
+// onFailure will be called when the validation failed, if empty, will respond with unauthorized (401) when there is no
+// token and forbidden (403) when the roles are missing.
+func RolesMiddleware(client auth.Authentication, tenantParam string, roles []string, onFailure func(*fiber.Ctx, error) error) fiber.Handler {
+	return authorizationMiddleware(tenantParam, onFailure, func(token *auth.Token, tenant string) bool {
+		return client.ValidateTenantRoles(token, tenant, roles)
+	})
+}
+
+// PermissionsMiddleware - middleware used to ensure that the token stored by AuthenticationMiddleware has been
+// granted the given permissions, so it must be used after it.
+// tenantParam and onFailure follow the same convention as those for RolesMiddleware.
+func PermissionsMiddleware(client auth.Authentication, tenantParam string, permissions []string, onFailure func(*fiber.Ctx, error) error) fiber.Handler {
+	return authorizationMiddleware(tenantParam, onFailure, func(token *auth.Token, tenant string) bool {
+		return client.ValidateTenantPermissions(token, tenant, permissions)
+	})
+}
+
+// SetToken - stores the validated token and its user id on the fiber context locals, and on the user context
+// so that auth.TokenFromContext works with c.UserContext().
+func SetToken(c *fiber.Ctx, token *auth.Token) {
+	if token == nil {
+		return
+	}
+	c.Locals(auth.ContextUserIDProperty, token.ID)
+	c.Locals(auth.ContextTokenProperty, token)
+	c.SetUserContext(auth.ContextWithToken(c.UserContext(), token))
+}
+
+// TokenFromContext - returns the validated token stored by AuthenticationMiddleware.
+// returns false if the request was not authenticated by the middleware.
+func TokenFromContext(c *fiber.Ctx) (*auth.Token, bool) {
+	if token, ok := c.Locals(auth.ContextTokenProperty).(*auth.Token); ok && token != nil {
+		return token, true
+	}
+	return auth.TokenFromContext(c.UserContext())
+}
+
+// UserIDFromContext - returns the descope user id stored by AuthenticationMiddleware, or an empty string.
+func UserIDFromContext(c *fiber.Ctx) string {
+	userID, _ := c.Locals(auth.ContextUserIDProperty).(string)
+	return userID
+}
+
+func sessionMiddleware(validate func(*http.Request, http.ResponseWriter) (bool, *auth.Token, error), onFailure func(*fiber.Ctx, error) error, onSuccess func(*fiber.Ctx, *auth.Token) error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		r := &http.Request{}
+		if err := fasthttpadaptor.ConvertRequest(c.Context(), r, true); err != nil {
+			logger.LogError("failed to convert fiber request", err)
+			return err
+		}
+		w := newCookieWriter()
+		ok, token, err := validate(r.WithContext(c.UserContext()), w)
+		w.applyCookies(c)
+		if !ok {
+			if err != nil {
+				logger.LogError("request failed because token is invalid", err)
+			}
+			if onFailure != nil {
+				return onFailure(c, err)
+			}
+			return c.Status(http.StatusUnauthorized).JSON(errors.NewUnauthorizedError())
+		}
+		if onSuccess != nil {
+			return onSuccess(c, token)
+		}
+		SetToken(c, token)
+		return c.Next()
+	}
+}
+
+func authorizationMiddleware(tenantParam string, onFailure func(*fiber.Ctx, error) error, validate func(*auth.Token, string) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := TokenFromContext(c)
+		if !ok {
+			return failAuthorization(c, http.StatusUnauthorized, errors.NewUnauthorizedError(), onFailure)
+		}
+		tenant := ""
+		if tenantParam != "" {
+			tenant = c.Params(tenantParam)
+		}
+		if !validate(token, tenant) {
+			return failAuthorization(c, http.StatusForbidden, errors.NewForbiddenError(), onFailure)
+		}
+		return c.Next()
+	}
+}
+
+func failAuthorization(c *fiber.Ctx, status int, err *errors.WebError, onFailure func(*fiber.Ctx, error) error) error {
+	if onFailure != nil {
+		return onFailure(c, err)
+	}
+	return c.Status(status).JSON(err)
+}
+
+// cookieWriter is a minimal http.ResponseWriter that collects the cookies written by the SDK
+// (e.g. after a session refresh) so they can be copied to the fasthttp response.
+type cookieWriter struct {
+	header http.Header
+}
+
+func newCookieWriter() *cookieWriter {
+	return &cookieWriter{header: http.Header{}}
+}
+
+func (w *cookieWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *cookieWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *cookieWriter) WriteHeader(int) {}
+
+func (w *cookieWriter) applyCookies(c *fiber.Ctx) {
+	for _, cookie := range w.header.Values("Set-Cookie") {
+		c.Response().Header.Add(fiber.HeaderSetCookie, cookie)
+	}
+}
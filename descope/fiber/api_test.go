@@ -0,0 +1,115 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/descopemock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, app *fiber.App, path string, cookies ...*http.Cookie) *http.Response {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+	res, err := app.Test(r)
+	require.NoError(t, err)
+	return res
+}
+
+func tokenHandler(t *testing.T, expected *auth.Token) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := TokenFromContext(c)
+		require.True(t, ok)
+		assert.Equal(t, expected, token)
+		assert.EqualValues(t, expected.ID, UserIDFromContext(c))
+		userToken, ok := auth.TokenFromContext(c.UserContext())
+		require.True(t, ok)
+		assert.Equal(t, expected, userToken)
+		return c.SendStatus(http.StatusOK)
+	}
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.ValidateSessionFunc = func(r *http.Request, w http.ResponseWriter) (bool, *auth.Token, error) {
+		cookie, err := r.Cookie(auth.SessionCookieName)
+		if err != nil || cookie.Value != "session" {
+			return false, nil, errors.UnauthorizedError
+		}
+		http.SetCookie(w, &http.Cookie{Name: auth.SessionCookieName, Value: "refreshed"})
+		return true, token, nil
+	}
+
+	app := fiber.New()
+	app.Get("/", AuthenticationMiddleware(authMock, nil, nil), tokenHandler(t, token))
+	res := serve(t, app, "/", &http.Cookie{Name: auth.SessionCookieName, Value: "session"})
+	assert.EqualValues(t, http.StatusOK, res.StatusCode)
+	// the cookies written by the validation are copied to the response
+	require.Len(t, res.Cookies(), 1)
+	assert.EqualValues(t, "refreshed", res.Cookies()[0].Value)
+	assert.EqualValues(t, http.StatusUnauthorized, serve(t, app, "/").StatusCode)
+}
+
+func TestRefreshMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueRefreshSession(true, token, nil)
+	authMock.QueueRefreshSession(false, nil, errors.UnauthorizedError)
+
+	var failure error
+	app := fiber.New()
+	app.Get("/", RefreshMiddleware(authMock, func(c *fiber.Ctx, err error) error {
+		failure = err
+		return c.SendStatus(http.StatusTeapot)
+	}, nil), tokenHandler(t, token))
+	assert.EqualValues(t, http.StatusOK, serve(t, app, "/").StatusCode)
+	assert.EqualValues(t, http.StatusTeapot, serve(t, app, "/").StatusCode)
+	assert.ErrorIs(t, failure, errors.UnauthorizedError)
+	assert.Empty(t, authMock.CallsTo("ValidateSession"))
+}
+
+func TestRolesMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.ValidateSessionFunc = func(_ *http.Request, _ http.ResponseWriter) (bool, *auth.Token, error) {
+		return true, token, nil
+	}
+	authMock.QueueValidateTenantRoles(true)
+	authMock.QueueValidateTenantRoles(false)
+
+	app := fiber.New()
+	app.Get("/unauthenticated/:tenant", RolesMiddleware(authMock, "tenant", []string{"admin"}, nil), tokenHandler(t, token))
+	app.Get("/:tenant", AuthenticationMiddleware(authMock, nil, nil), RolesMiddleware(authMock, "tenant", []string{"admin"}, nil), tokenHandler(t, token))
+	assert.EqualValues(t, http.StatusOK, serve(t, app, "/t1").StatusCode)
+	assert.EqualValues(t, http.StatusForbidden, serve(t, app, "/t1").StatusCode)
+	assert.EqualValues(t, http.StatusUnauthorized, serve(t, app, "/unauthenticated/t1").StatusCode)
+
+	calls := authMock.CallsTo("ValidateTenantRoles")
+	require.Len(t, calls, 2)
+	assert.EqualValues(t, "t1", calls[0].Args[1])
+	assert.EqualValues(t, []string{"admin"}, calls[0].Args[2])
+}
+
+func TestPermissionsMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateSession(true, token, nil)
+	authMock.QueueValidateTenantPermissions(false)
+
+	var failure error
+	app := fiber.New()
+	app.Get("/", AuthenticationMiddleware(authMock, nil, nil), PermissionsMiddleware(authMock, "", []string{"read"}, func(c *fiber.Ctx, err error) error {
+		failure = err
+		return c.SendStatus(http.StatusTeapot)
+	}), tokenHandler(t, token))
+	assert.EqualValues(t, http.StatusTeapot, serve(t, app, "/").StatusCode)
+	assert.ErrorIs(t, failure, errors.ForbiddenError)
+}
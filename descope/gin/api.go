@@ -4,24 +4,63 @@ import (
 	"net/http"
 
 	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
 	"github.com/gin-gonic/gin"
 )
 
+// AuthneticationMiddleware - middleware used to validate session and invoke if provided a failure and
+// success callbacks after calling ValidateSession().
+// onFailure will be called when the authentication failed, if empty, will abort with unauthorized (401) and an error body.
+// onSuccess will be called when the authentication succeeded, if empty, it will store the validated token and the descope
+// user id on the gin context and the request context (see TokenFromContext) and runs next.
 func AuthneticationMiddleware(client auth.Authentication, onFailure func(*gin.Context, error), onSuccess func(*gin.Context, *auth.Token)) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, token, err := client.ValidateSession(c.Request, c.Writer); ok {
 			if onSuccess != nil {
 				onSuccess(c, token)
 			} else {
-				c.Set(auth.ContextUserIDProperty, token.ID)
+				SetToken(c, token)
 				c.Next()
 			}
 		} else {
 			if onFailure != nil {
 				onFailure(c, err)
 			} else {
-				c.AbortWithError(http.StatusUnauthorized, err)
+				if err != nil {
+					_ = c.Error(err)
+				}
+				c.AbortWithStatusJSON(http.StatusUnauthorized, errors.NewUnauthorizedError())
 			}
 		}
 	}
 }
+
+// SetToken - stores the validated token and its user id on the gin context, and on the request context
+// so that auth.TokenFromContext works for handlers that only receive the *http.Request.
+func SetToken(c *gin.Context, token *auth.Token) {
+	if token == nil {
+		return
+	}
+	c.Set(auth.ContextUserIDProperty, token.ID)
+	c.Set(auth.ContextTokenProperty, token)
+	c.Request = c.Request.WithContext(auth.ContextWithToken(c.Request.Context(), token))
+}
+
+// TokenFromContext - returns the validated token stored by AuthneticationMiddleware.
+// returns false if the request was not authenticated by the middleware.
+func TokenFromContext(c *gin.Context) (*auth.Token, bool) {
+	if v, ok := c.Get(auth.ContextTokenProperty); ok {
+		if token, ok := v.(*auth.Token); ok && token != nil {
+			return token, true
+		}
+	}
+	if c.Request == nil {
+		return nil, false
+	}
+	return auth.TokenFromContext(c.Request.Context())
+}
+
+// UserIDFromContext - returns the descope user id stored by AuthneticationMiddleware, or an empty string.
+func UserIDFromContext(c *gin.Context) string {
+	return c.GetString(auth.ContextUserIDProperty)
+}
@@ -0,0 +1,113 @@
+package gin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/descopemock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func serve(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func tokenHandler(t *testing.T, expected *auth.Token) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := TokenFromContext(c)
+		require.True(t, ok)
+		assert.Equal(t, expected, token)
+		assert.EqualValues(t, expected.ID, UserIDFromContext(c))
+		requestToken, ok := auth.TokenFromContext(c.Request.Context())
+		require.True(t, ok)
+		assert.Equal(t, expected, requestToken)
+		c.Status(http.StatusOK)
+	}
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateSession(true, token, nil)
+	authMock.QueueValidateSession(false, nil, errors.UnauthorizedError)
+
+	r := gin.New()
+	r.GET("/", AuthneticationMiddleware(authMock, nil, nil), tokenHandler(t, token))
+	assert.EqualValues(t, http.StatusOK, serve(r, "/").Code)
+	w := serve(r, "/")
+	assert.EqualValues(t, http.StatusUnauthorized, w.Code)
+	webErr := &errors.WebError{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), webErr))
+	assert.EqualValues(t, errors.UnauthorizedError.Code, webErr.Code)
+	assert.Len(t, authMock.CallsTo("ValidateSession"), 2)
+}
+
+func TestAuthenticationMiddlewareCallbacks(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateSession(true, token, nil)
+	authMock.QueueValidateSession(false, nil, errors.UnauthorizedError)
+
+	var success *auth.Token
+	var failure error
+	r := gin.New()
+	r.GET("/", AuthneticationMiddleware(authMock, func(c *gin.Context, err error) {
+		failure = err
+		c.AbortWithStatus(http.StatusTeapot)
+	}, func(c *gin.Context, token *auth.Token) {
+		success = token
+		c.AbortWithStatus(http.StatusAccepted)
+	}), func(c *gin.Context) {
+		require.Fail(t, "handler should not be called")
+	})
+	assert.EqualValues(t, http.StatusAccepted, serve(r, "/").Code)
+	assert.Equal(t, token, success)
+	assert.EqualValues(t, http.StatusTeapot, serve(r, "/").Code)
+	assert.ErrorIs(t, failure, errors.UnauthorizedError)
+}
+
+func TestSetToken(t *testing.T) {
+	token := &auth.Token{ID: "u1"}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_, ok := TokenFromContext(c)
+	assert.False(t, ok)
+	assert.Empty(t, UserIDFromContext(c))
+	SetToken(c, nil)
+	_, ok = TokenFromContext(c)
+	assert.False(t, ok)
+
+	SetToken(c, token)
+	res, ok := TokenFromContext(c)
+	require.True(t, ok)
+	assert.Equal(t, token, res)
+	assert.EqualValues(t, "u1", UserIDFromContext(c))
+	res, ok = auth.TokenFromContext(c.Request.Context())
+	require.True(t, ok)
+	assert.Equal(t, token, res)
+
+	// a token that is only on the request context, e.g. stored by a net/http middleware
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request = c.Request.WithContext(auth.ContextWithToken(c.Request.Context(), token))
+	res, ok = TokenFromContext(c)
+	require.True(t, ok)
+	assert.Equal(t, token, res)
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	_, ok = TokenFromContext(c)
+	assert.False(t, ok)
+}
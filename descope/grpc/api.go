@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/logger"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// AuthorizationMetadataKey is the metadata key holding the session token, as "Bearer <jwt>".
+	AuthorizationMetadataKey = "authorization"
+	// RefreshTokenMetadataKey is the optional metadata key holding the refresh token, used to
+	// refresh the session when the session token has expired.
+	RefreshTokenMetadataKey = "descope-refresh-token"
+)
+
+// InterceptorOptions - optional configuration for the server interceptors.
+type InterceptorOptions struct {
+	// Roles (optional, nil) - roles the validated token must be granted, otherwise the call fails with PermissionDenied.
+	Roles []string
+	// Permissions (optional, nil) - permissions the validated token must be granted, otherwise the call fails with PermissionDenied.
+	Permissions []string
+	// Tenant (optional, "") - the tenant to validate the roles and permissions for, if empty they are validated at the project level.
+	Tenant string
+	// SkipMethods (optional, nil) - full method names (e.g. "/grpc.health.v1.Health/Check") that do not require authentication.
+	SkipMethods []string
+}
+
+// UnaryServerInterceptor - validates the session token sent in the incoming metadata with ValidateSessionTokens(),
+// and stores the validated token in the handler context (see auth.TokenFromContext).
+// When the session was refreshed with the refresh token, the new session token is sent back in the authorization
+// header metadata of the response, as "Bearer <jwt>".
+// Fails with codes.Unauthenticated when the token is missing or invalid, and with codes.PermissionDenied when
+// the token was not granted the roles or permissions required by options.
+func UnaryServerInterceptor(client auth.Authentication, options *InterceptorOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, refreshed, err := authenticate(ctx, client, info.FullMethod, options)
+		if err != nil {
+			return nil, err
+		}
+		if refreshed != nil {
+			if err := grpc.SetHeader(ctx, refreshed); err != nil {
+				logger.LogDebug("unable to send the refreshed session token of [%s] [%s]", info.FullMethod, err)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor - the streaming equivalent of UnaryServerInterceptor, the validated token
+// is available from the context of the wrapped stream.
+func StreamServerInterceptor(client auth.Authentication, options *InterceptorOptions) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, refreshed, err := authenticate(ss.Context(), client, info.FullMethod, options)
+		if err != nil {
+			return err
+		}
+		if refreshed != nil {
+			if err := ss.SetHeader(refreshed); err != nil {
+				logger.LogDebug("unable to send the refreshed session token of [%s] [%s]", info.FullMethod, err)
+			}
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate returns the context with the validated token, and the header metadata to send when the session was
+// refreshed, nil otherwise
+func authenticate(ctx context.Context, client auth.Authentication, fullMethod string, options *InterceptorOptions) (context.Context, metadata.MD, error) {
+	if options == nil {
+		options = &InterceptorOptions{}
+	}
+	if slices.Contains(options.SkipMethods, fullMethod) {
+		return ctx, nil, nil
+	}
+
+	sessionToken, refreshToken := provideTokens(ctx)
+	if sessionToken == "" && refreshToken == "" {
+		logger.LogDebug("unable to find tokens in metadata for [%s]", fullMethod)
+		return ctx, nil, status.Error(codes.Unauthenticated, "missing session token")
+	}
+	ok, token, err := client.ValidateSessionTokens(sessionToken, refreshToken)
+	if !ok || token == nil {
+		if err != nil {
+			logger.LogError("request to [%s] failed because token is invalid", err, fullMethod)
+		}
+		return ctx, nil, status.Error(codes.Unauthenticated, "invalid session token")
+	}
+	if len(options.Roles) > 0 && !client.ValidateTenantRoles(token, options.Tenant, options.Roles) {
+		return ctx, nil, status.Error(codes.PermissionDenied, "missing required roles")
+	}
+	if len(options.Permissions) > 0 && !client.ValidateTenantPermissions(token, options.Tenant, options.Permissions) {
+		return ctx, nil, status.Error(codes.PermissionDenied, "missing required permissions")
+	}
+	var refreshed metadata.MD
+	if token.JWT != "" && token.JWT != sessionToken {
+		refreshed = metadata.Pairs(AuthorizationMetadataKey, api.BearerAuthorizationPrefix+token.JWT)
+	}
+	return auth.ContextWithToken(ctx, token), refreshed, nil
+}
+
+func provideTokens(ctx context.Context) (string, string) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", ""
+	}
+	sessionToken := ""
+	for _, value := range md.Get(AuthorizationMetadataKey) {
+		if strings.HasPrefix(value, api.BearerAuthorizationPrefix) {
+			sessionToken = strings.TrimPrefix(value, api.BearerAuthorizationPrefix)
+			break
+		}
+	}
+	refreshToken := ""
+	if values := md.Get(RefreshTokenMetadataKey); len(values) > 0 {
+		refreshToken = values[0]
+	}
+	return sessionToken, refreshToken
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,195 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/descopemock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/test.Service/Method"
+
+// transportStream - records the header metadata sent by unary interceptors
+type transportStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *transportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// serverStreamMock - a server stream with the given context, that records the header metadata sent on it
+type serverStreamMock struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *serverStreamMock) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStreamMock) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func incomingContext(kv ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+}
+
+// callUnary calls the interceptor with the given context, and returns the token the handler was given and the header
+// metadata sent with the response
+func callUnary(t *testing.T, interceptor grpc.UnaryServerInterceptor, ctx context.Context) (*auth.Token, metadata.MD, error) {
+	stream := &transportStream{}
+	var token *auth.Token
+	res, err := interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), "req", &grpc.UnaryServerInfo{FullMethod: testMethod}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.EqualValues(t, "req", req)
+		token, _ = auth.TokenFromContext(ctx)
+		return "res", nil
+	})
+	if err == nil {
+		assert.EqualValues(t, "res", res)
+	}
+	return token, stream.header, err
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	token := &auth.Token{ID: "u1", JWT: "jwt"}
+	authMock := descopemock.NewAuthentication()
+	authMock.ValidateSessionTokensFunc = func(sessionToken, refreshToken string) (bool, *auth.Token, error) {
+		assert.EqualValues(t, "jwt", sessionToken)
+		assert.Empty(t, refreshToken)
+		return true, token, nil
+	}
+	interceptor := UnaryServerInterceptor(authMock, nil)
+
+	res, header, err := callUnary(t, interceptor, incomingContext(AuthorizationMetadataKey, "Bearer jwt"))
+	require.NoError(t, err)
+	assert.Equal(t, token, res)
+	assert.Empty(t, header)
+}
+
+func TestUnaryServerInterceptorUnauthenticated(t *testing.T) {
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateSessionTokens(false, nil, errors.UnauthorizedError)
+	interceptor := UnaryServerInterceptor(authMock, nil)
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		incomingContext(),
+		incomingContext(AuthorizationMetadataKey, "Basic jwt"),
+	} {
+		_, _, err := callUnary(t, interceptor, ctx)
+		assert.EqualValues(t, codes.Unauthenticated, status.Code(err))
+	}
+	assert.Empty(t, authMock.CallsTo("ValidateSessionTokens"))
+
+	_, _, err := callUnary(t, interceptor, incomingContext(AuthorizationMetadataKey, "Bearer invalid"))
+	assert.EqualValues(t, codes.Unauthenticated, status.Code(err))
+	assert.Len(t, authMock.CallsTo("ValidateSessionTokens"), 1)
+}
+
+func TestUnaryServerInterceptorPermissionDenied(t *testing.T) {
+	token := &auth.Token{ID: "u1", JWT: "jwt"}
+	authMock := descopemock.NewAuthentication()
+	authMock.ValidateSessionTokensFunc = func(string, string) (bool, *auth.Token, error) {
+		return true, token, nil
+	}
+	authMock.ValidateTenantRolesFunc = func(_ *auth.Token, tenant string, roles []string) bool {
+		assert.EqualValues(t, "t1", tenant)
+		return len(roles) == 1 && roles[0] == "admin"
+	}
+	authMock.ValidateTenantPermissionsFunc = func(_ *auth.Token, tenant string, permissions []string) bool {
+		assert.EqualValues(t, "t1", tenant)
+		return len(permissions) == 1 && permissions[0] == "write"
+	}
+	ctx := incomingContext(AuthorizationMetadataKey, "Bearer jwt")
+
+	_, _, err := callUnary(t, UnaryServerInterceptor(authMock, &InterceptorOptions{Tenant: "t1", Roles: []string{"admin"}, Permissions: []string{"write"}}), ctx)
+	require.NoError(t, err)
+	_, _, err = callUnary(t, UnaryServerInterceptor(authMock, &InterceptorOptions{Tenant: "t1", Roles: []string{"owner"}}), ctx)
+	assert.EqualValues(t, codes.PermissionDenied, status.Code(err))
+	_, _, err = callUnary(t, UnaryServerInterceptor(authMock, &InterceptorOptions{Tenant: "t1", Permissions: []string{"delete"}}), ctx)
+	assert.EqualValues(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestUnaryServerInterceptorSkipMethods(t *testing.T) {
+	authMock := descopemock.NewAuthentication()
+	interceptor := UnaryServerInterceptor(authMock, &InterceptorOptions{SkipMethods: []string{testMethod}})
+
+	token, _, err := callUnary(t, interceptor, context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, token)
+	assert.Empty(t, authMock.CallsTo("ValidateSessionTokens"))
+}
+
+func TestUnaryServerInterceptorRefreshedToken(t *testing.T) {
+	token := &auth.Token{ID: "u1", JWT: "refreshed"}
+	authMock := descopemock.NewAuthentication()
+	authMock.ValidateSessionTokensFunc = func(sessionToken, refreshToken string) (bool, *auth.Token, error) {
+		assert.EqualValues(t, "expired", sessionToken)
+		assert.EqualValues(t, "refresh", refreshToken)
+		return true, token, nil
+	}
+	interceptor := UnaryServerInterceptor(authMock, nil)
+
+	res, header, err := callUnary(t, interceptor, incomingContext(AuthorizationMetadataKey, "Bearer expired", RefreshTokenMetadataKey, "refresh"))
+	require.NoError(t, err)
+	assert.Equal(t, token, res)
+	assert.EqualValues(t, []string{"Bearer refreshed"}, header.Get(AuthorizationMetadataKey))
+
+	// only a refresh token was sent
+	authMock.ValidateSessionTokensFunc = func(sessionToken, refreshToken string) (bool, *auth.Token, error) {
+		assert.Empty(t, sessionToken)
+		assert.EqualValues(t, "refresh", refreshToken)
+		return true, token, nil
+	}
+	_, header, err = callUnary(t, interceptor, incomingContext(RefreshTokenMetadataKey, "refresh"))
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{"Bearer refreshed"}, header.Get(AuthorizationMetadataKey))
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	token := &auth.Token{ID: "u1", JWT: "refreshed"}
+	authMock := descopemock.NewAuthentication()
+	authMock.QueueValidateSessionTokens(true, token, nil)
+	authMock.QueueValidateSessionTokens(false, nil, errors.UnauthorizedError)
+	interceptor := StreamServerInterceptor(authMock, nil)
+	info := &grpc.StreamServerInfo{FullMethod: testMethod}
+
+	ss := &serverStreamMock{ctx: incomingContext(AuthorizationMetadataKey, "Bearer expired", RefreshTokenMetadataKey, "refresh")}
+	called := false
+	err := interceptor("srv", ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		called = true
+		res, ok := auth.TokenFromContext(stream.Context())
+		require.True(t, ok)
+		assert.Equal(t, token, res)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.EqualValues(t, []string{"Bearer refreshed"}, ss.header.Get(AuthorizationMetadataKey))
+
+	err = interceptor("srv", &serverStreamMock{ctx: incomingContext(AuthorizationMetadataKey, "Bearer invalid")}, info, func(interface{}, grpc.ServerStream) error {
+		require.Fail(t, "handler should not be called")
+		return nil
+	})
+	assert.EqualValues(t, codes.Unauthenticated, status.Code(err))
+
+	err = interceptor("srv", &serverStreamMock{ctx: context.Background()}, info, func(interface{}, grpc.ServerStream) error {
+		require.Fail(t, "handler should not be called")
+		return nil
+	})
+	assert.EqualValues(t, codes.Unauthenticated, status.Code(err))
+}
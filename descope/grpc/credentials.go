@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
+	"google.golang.org/grpc/credentials"
+)
+
+// PerRPCCredentials - grpc credentials that stamp every outgoing call with a bearer session token
+// provided by a token source (e.g. auth.AccessKeyTokenSource), to be validated by UnaryServerInterceptor
+// or StreamServerInterceptor on the receiving service.
+type PerRPCCredentials struct {
+	source                   auth.TokenSource
+	requireTransportSecurity bool
+}
+
+var _ credentials.PerRPCCredentials = &PerRPCCredentials{}
+
+// NewPerRPCCredentials - creates PerRPCCredentials for the given token source, use it with grpc.WithPerRPCCredentials.
+// insecure should only be set to true for plaintext connections in local development and tests.
+func NewPerRPCCredentials(source auth.TokenSource, insecure bool) *PerRPCCredentials {
+	return &PerRPCCredentials{source: source, requireTransportSecurity: !insecure}
+}
+
+// GetRequestMetadata - returns the authorization metadata of a call, the token is obtained with the context of the
+// call when the source is an auth.ContextTokenSource.
+func (c *PerRPCCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := auth.SourceToken(ctx, c.source)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{AuthorizationMetadataKey: api.BearerAuthorizationPrefix + token.JWT}, nil
+}
+
+func (c *PerRPCCredentials) RequireTransportSecurity() bool {
+	return c.requireTransportSecurity
+}
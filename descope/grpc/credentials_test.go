@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contextKey struct{}
+
+// tokenSource - a token source that returns the given results, and records the context it was given
+type tokenSource struct {
+	token *auth.Token
+	err   error
+	ctx   context.Context
+}
+
+func (s *tokenSource) Token() (*auth.Token, error) {
+	return s.token, s.err
+}
+
+func (s *tokenSource) TokenWithContext(ctx context.Context) (*auth.Token, error) {
+	s.ctx = ctx
+	return s.token, s.err
+}
+
+func TestPerRPCCredentials(t *testing.T) {
+	source := &tokenSource{token: &auth.Token{JWT: "jwt"}}
+	creds := NewPerRPCCredentials(source, false)
+	ctx := context.WithValue(context.Background(), contextKey{}, "call")
+
+	md, err := creds.GetRequestMetadata(ctx, "https://example.com")
+	require.NoError(t, err)
+	assert.EqualValues(t, map[string]string{AuthorizationMetadataKey: "Bearer jwt"}, md)
+	require.NotNil(t, source.ctx)
+	assert.EqualValues(t, "call", source.ctx.Value(contextKey{}))
+}
+
+func TestPerRPCCredentialsSourceError(t *testing.T) {
+	creds := NewPerRPCCredentials(&tokenSource{err: errors.InvalidAccessKeyResponse}, false)
+	md, err := creds.GetRequestMetadata(context.Background())
+	assert.ErrorIs(t, err, errors.InvalidAccessKeyResponse)
+	assert.Nil(t, md)
+}
+
+func TestPerRPCCredentialsRequireTransportSecurity(t *testing.T) {
+	source := &tokenSource{token: &auth.Token{JWT: "jwt"}}
+	assert.True(t, NewPerRPCCredentials(source, false).RequireTransportSecurity())
+	assert.False(t, NewPerRPCCredentials(source, true).RequireTransportSecurity())
+}
@@ -1,5 +1,7 @@
 package mgmt
 
+import "github.com/descope/go-sdk/descope/auth"
+
 // Provides functions for managing tenants in a project.
 type Tenant interface {
 	// Create a new tenant with the given name.
@@ -29,6 +31,16 @@ type Tenant interface {
 	//
 	// IMPORTANT: This action is irreversible. Use carefully.
 	Delete(managementKey, id string) error
+
+	// Load all the tenants of the project.
+	LoadAll(managementKey string) ([]*TenantResponse, error)
+}
+
+// Represents a tenant of the project, as returned by Tenant.LoadAll.
+type TenantResponse struct {
+	ID                      string   `json:"id"`
+	Name                    string   `json:"name"`
+	SelfProvisioningDomains []string `json:"selfProvisioningDomains"`
 }
 
 // Represents a tenant association for a User. The tenant ID is required to denote
@@ -52,6 +64,14 @@ type User interface {
 	// user has in each one.
 	Create(managementKey, identifier, email, phone, displayName string, roles []string, tenants []UserTenants) error
 
+	// Create a new test user.
+	//
+	// The parameters follow the same convention as those for the Create function. Test users
+	// can't log in through the regular delivery methods, instead their OTP codes and magic links
+	// are generated with GenerateOTPForTestUser and GenerateMagicLinkForTestUser, which makes
+	// them useful for end to end tests that don't have access to real inboxes or phones.
+	CreateTestUser(managementKey, identifier, email, phone, displayName string, roles []string, tenants []UserTenants) error
+
 	// Update an existing user.
 	//
 	// The parameters follow the same convention as those for the Create function.
@@ -64,6 +84,33 @@ type User interface {
 	//
 	// IMPORTANT: This action is irreversible. Use carefully.
 	Delete(managementKey, identifier string) error
+
+	// Delete all the test users in the project, e.g. at the end of a test run.
+	//
+	// IMPORTANT: This action is irreversible. Regular users are not affected.
+	DeleteAllTestUsers(managementKey string) error
+
+	// Generate an embedded magic link token for an existing user, to be delivered by your own
+	// means (e.g. branded emails or push notifications) instead of by Descope.
+	//
+	// The token is verified with the MagicLink.Verify function of the auth package, and the
+	// optional customClaims are added to the session JWT issued once it is verified.
+	GenerateEmbeddedLink(managementKey, identifier string, customClaims map[string]any) (token string, err error)
+
+	// Generate an OTP code for a test user, so automated tests can authenticate without
+	// receiving the code through the delivery method.
+	//
+	// The code is verified with the OTP.VerifyCode function of the auth package. This
+	// function only works for test users created with CreateTestUser.
+	GenerateOTPForTestUser(managementKey string, method auth.DeliveryMethod, identifier string) (code string, err error)
+
+	// Generate a magic link for a test user, so automated tests can authenticate without
+	// receiving the link through the delivery method.
+	//
+	// The URI is the optional address the link points to, the token in the returned link is
+	// verified with the MagicLink.Verify function of the auth package. This function only
+	// works for test users created with CreateTestUser.
+	GenerateMagicLinkForTestUser(managementKey string, method auth.DeliveryMethod, identifier, URI string) (link string, err error)
 }
 
 // Represents a mapping between a set of groups of users and a role that will be assigned to them.
@@ -72,6 +119,16 @@ type RoleMapping struct {
 	Role   string
 }
 
+// Represents the SSO setting of a tenant, as returned by SSO.LoadSettings.
+type SSOSettingsResponse struct {
+	TenantID       string `json:"tenantId"`
+	Enabled        bool   `json:"enabled"`
+	IdpURL         string `json:"idpURL"`
+	EntityID       string `json:"entityId"`
+	IdpMetadataURL string `json:"idpMetadataURL"`
+	RedirectURL    string `json:"redirectURL"`
+}
+
 // Provides functions for configuring SSO for a project.
 type SSO interface {
 	// Configure SSO setting for a tenant manually.
@@ -80,6 +137,11 @@ type SSO interface {
 	// is the certificated provided by the identity provider.
 	ConfigureSettings(managementKey, tenantID string, enabled bool, idpURL, idpCert, entityID, redirectURL string) error
 
+	// Load the SSO setting of a tenant.
+	//
+	// The tenantID is required.
+	LoadSettings(managementKey, tenantID string) (*SSOSettingsResponse, error)
+
 	// Configure SSO setting for a tenant by fetching SSO settings from an IDP metadata URL.
 	ConfigureMetadata(managementKey, tenantID string, enabled bool, idpMetadataURL string) error
 
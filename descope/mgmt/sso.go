@@ -34,6 +34,18 @@ func (s *sso) ConfigureSettings(managementKey, tenantID string, enabled bool, id
 	return err
 }
 
+func (s *sso) LoadSettings(managementKey, tenantID string) (*SSOSettingsResponse, error) {
+	if tenantID == "" {
+		return nil, errors.NewInvalidArgumentError("tenantID")
+	}
+	res := &SSOSettingsResponse{}
+	options := &api.HTTPRequest{QueryParams: map[string]string{"tenantId": tenantID}, ResBodyObj: res}
+	if _, err := s.client.DoGetRequest(api.Routes.ManagementSSOLoadSettings(), options, managementKey); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (s *sso) ConfigureMetadata(managementKey, tenantID string, enabled bool, idpMetadataURL string) error {
 	if tenantID == "" {
 		return errors.NewInvalidArgumentError("tenantID")
@@ -36,6 +36,30 @@ func TestSSOConfigureSettingsError(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestSSOLoadSettingsSuccess(t *testing.T) {
+	response := map[string]any{"tenantId": "abc", "enabled": true, "idpMetadataURL": "http://idpURL"}
+	mgmt := newTestMgmt(nil, helpers.DoOkWithBody(func(r *http.Request) {
+		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "abc", r.URL.Query().Get("tenantId"))
+	}, response))
+	settings, err := mgmt.SSO().LoadSettings("key", "abc")
+	require.NoError(t, err)
+	require.Equal(t, "abc", settings.TenantID)
+	require.True(t, settings.Enabled)
+	require.Equal(t, "http://idpURL", settings.IdpMetadataURL)
+}
+
+func TestSSOLoadSettingsError(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOk(nil))
+	_, err := mgmt.SSO().LoadSettings("key", "")
+	require.Error(t, err)
+	mgmt = newTestMgmt(nil, helpers.DoBadRequest(nil))
+	settings, err := mgmt.SSO().LoadSettings("key", "abc")
+	require.Error(t, err)
+	require.Nil(t, settings)
+}
+
 func TestSSOConfigureMetadataSuccess(t *testing.T) {
 	mgmt := newTestMgmt(nil, helpers.DoOk(func(r *http.Request) {
 		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
@@ -1,7 +1,12 @@
 package mgmt
 
 import (
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
 	"github.com/descope/go-sdk/descope/errors"
 	"github.com/descope/go-sdk/descope/utils"
 )
@@ -61,6 +66,79 @@ func (t *tenant) Delete(managementKey, id string) error {
 	return err
 }
 
+func (t *tenant) LoadAll(managementKey string) ([]*TenantResponse, error) {
+	res := &struct {
+		Tenants []*TenantResponse `json:"tenants"`
+	}{}
+	if _, err := t.client.DoGetRequest(api.Routes.ManagementTenantLoadAll(), &api.HTTPRequest{ResBodyObj: res}, managementKey); err != nil {
+		return nil, err
+	}
+	return res.Tenants, nil
+}
+
 func makeCreateUpdateTenantRequest(id, name string, selfProvisioningDomains []string) map[string]any {
 	return map[string]any{"id": id, "name": name, "selfProvisioningDomains": selfProvisioningDomains}
 }
+
+// DefaultSSOTenantsTTL - how long the SSO tenant resolver caches the SSO-enabled tenants of the project
+const DefaultSSOTenantsTTL = 5 * time.Minute
+
+// NewSSOTenantResolver - returns a resolver for SAML.StartWithEmail that matches the email domain against the
+// self-provisioning domains of the tenants that have SSO enabled. The tenants and their SSO settings are loaded with the
+// given management key and cached for the given ttl, or DefaultSSOTenantsTTL when 0, so logins do not load them again.
+func NewSSOTenantResolver(management Management, managementKey string, ttl time.Duration) auth.SSOTenantResolver {
+	if ttl == 0 {
+		ttl = DefaultSSOTenantsTTL
+	}
+	return &ssoTenantResolver{management: management, managementKey: managementKey, ttl: ttl, now: time.Now}
+}
+
+type ssoTenantResolver struct {
+	management    Management
+	managementKey string
+	ttl           time.Duration
+	now           func() time.Time
+
+	mu       sync.Mutex
+	domains  map[string]string // the lower cased self-provisioning domains of the SSO-enabled tenants, to their IDs
+	loadedAt time.Time
+}
+
+func (r *ssoTenantResolver) ResolveSSOTenant(domain string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.domains == nil || r.now().Sub(r.loadedAt) >= r.ttl {
+		domains, err := r.loadDomains()
+		if err != nil {
+			return "", err
+		}
+		r.domains, r.loadedAt = domains, r.now()
+	}
+	return r.domains[strings.ToLower(domain)], nil
+}
+
+func (r *ssoTenantResolver) loadDomains() (map[string]string, error) {
+	tenants, err := r.management.Tenant().LoadAll(r.managementKey)
+	if err != nil {
+		return nil, err
+	}
+	domains := map[string]string{}
+	for _, t := range tenants {
+		if len(t.SelfProvisioningDomains) == 0 {
+			continue
+		}
+		settings, err := r.management.SSO().LoadSettings(r.managementKey, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !settings.Enabled {
+			continue
+		}
+		for _, d := range t.SelfProvisioningDomains {
+			if _, ok := domains[strings.ToLower(d)]; !ok {
+				domains[strings.ToLower(d)] = t.ID
+			}
+		}
+	}
+	return domains, nil
+}
@@ -2,8 +2,11 @@ package mgmt
 
 import (
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/descope/go-sdk/descope/api"
 	"github.com/descope/go-sdk/descope/tests/helpers"
 	"github.com/stretchr/testify/require"
 )
@@ -98,3 +101,71 @@ func TestTenantDeleteError(t *testing.T) {
 	err := mgmt.Tenant().Delete("key", "")
 	require.Error(t, err)
 }
+
+func TestTenantLoadAllSuccess(t *testing.T) {
+	response := map[string]any{"tenants": []map[string]any{{"id": "t1", "name": "abc", "selfProvisioningDomains": []string{"foo.com"}}}}
+	mgmt := newTestMgmt(nil, helpers.DoOkWithBody(func(r *http.Request) {
+		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
+		require.Equal(t, http.MethodGet, r.Method)
+	}, response))
+	tenants, err := mgmt.Tenant().LoadAll("key")
+	require.NoError(t, err)
+	require.Len(t, tenants, 1)
+	require.Equal(t, "t1", tenants[0].ID)
+	require.Equal(t, "abc", tenants[0].Name)
+	require.Equal(t, []string{"foo.com"}, tenants[0].SelfProvisioningDomains)
+}
+
+func TestTenantLoadAllError(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoBadRequest(nil))
+	tenants, err := mgmt.Tenant().LoadAll("key")
+	require.Error(t, err)
+	require.Nil(t, tenants)
+}
+
+func TestSSOTenantResolver(t *testing.T) {
+	loads := 0
+	mgmt := newTestMgmt(nil, func(r *http.Request) (*http.Response, error) {
+		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
+		if strings.HasSuffix(r.URL.Path, api.Routes.ManagementTenantLoadAll()) {
+			loads++
+			return helpers.DoOkWithBody(nil, map[string]any{"tenants": []map[string]any{
+				{"id": "t1", "selfProvisioningDomains": []string{"Foo.com", "bar.com"}},
+				{"id": "t2", "selfProvisioningDomains": []string{"other.com"}},
+				{"id": "t3"},
+			}})(r)
+		}
+		tenantID := r.URL.Query().Get("tenantId")
+		require.NotEqual(t, "t3", tenantID, "the settings of tenants without domains are not loaded")
+		return helpers.DoOkWithBody(nil, map[string]any{"tenantId": tenantID, "enabled": tenantID == "t1"})(r)
+	})
+	resolver := NewSSOTenantResolver(mgmt, "key", time.Minute)
+	now := time.Now()
+	resolver.(*ssoTenantResolver).now = func() time.Time { return now }
+
+	tenantID, err := resolver.ResolveSSOTenant("foo.com")
+	require.NoError(t, err)
+	require.Equal(t, "t1", tenantID)
+	// tenants without SSO enabled are not matched
+	tenantID, err = resolver.ResolveSSOTenant("other.com")
+	require.NoError(t, err)
+	require.Empty(t, tenantID)
+	tenantID, err = resolver.ResolveSSOTenant("baz.com")
+	require.NoError(t, err)
+	require.Empty(t, tenantID)
+	require.Equal(t, 1, loads)
+
+	// loaded again once the cache expires
+	now = now.Add(time.Minute)
+	tenantID, err = resolver.ResolveSSOTenant("bar.com")
+	require.NoError(t, err)
+	require.Equal(t, "t1", tenantID)
+	require.Equal(t, 2, loads)
+}
+
+func TestSSOTenantResolverError(t *testing.T) {
+	resolver := NewSSOTenantResolver(newTestMgmt(nil, helpers.DoBadRequest(nil)), "key", 0)
+	tenantID, err := resolver.ResolveSSOTenant("foo.com")
+	require.Error(t, err)
+	require.Empty(t, tenantID)
+}
@@ -2,7 +2,9 @@ package mgmt
 
 import (
 	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
 	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/utils"
 )
 
 type user struct {
@@ -18,6 +20,16 @@ func (u *user) Create(managementKey, identifier, email, phone, displayName strin
 	return err
 }
 
+func (u *user) CreateTestUser(managementKey, identifier, email, phone, displayName string, roles []string, tenants []UserTenants) error {
+	if identifier == "" {
+		return errors.NewInvalidArgumentError("identifier")
+	}
+	req := makeCreateUpdateUserRequest(identifier, email, phone, displayName, roles, tenants)
+	req["test"] = true
+	_, err := u.client.DoPostRequest(api.Routes.ManagementUserCreate(), req, nil, managementKey)
+	return err
+}
+
 func (u *user) Update(managementKey, identifier, email, phone, displayName string, roles []string, tenants []UserTenants) error {
 	if identifier == "" {
 		return errors.NewInvalidArgumentError("identifier")
@@ -36,6 +48,67 @@ func (u *user) Delete(managementKey, identifier string) error {
 	return err
 }
 
+func (u *user) DeleteAllTestUsers(managementKey string) error {
+	_, err := u.client.DoDeleteRequest(api.Routes.ManagementUserDeleteAllTestUsers(), nil, managementKey)
+	return err
+}
+
+func (u *user) GenerateEmbeddedLink(managementKey, identifier string, customClaims map[string]any) (string, error) {
+	if identifier == "" {
+		return "", errors.NewInvalidArgumentError("identifier")
+	}
+	req := map[string]any{"identifier": identifier, "customClaims": customClaims}
+	res := &struct {
+		Token string `json:"token"`
+	}{}
+	if err := u.generate(api.Routes.ManagementUserGenerateEmbeddedLink(), req, res, managementKey); err != nil {
+		return "", err
+	}
+	return res.Token, nil
+}
+
+func (u *user) GenerateOTPForTestUser(managementKey string, method auth.DeliveryMethod, identifier string) (string, error) {
+	if identifier == "" {
+		return "", errors.NewInvalidArgumentError("identifier")
+	}
+	if method == "" {
+		return "", errors.NewInvalidArgumentError("method")
+	}
+	req := map[string]any{"identifier": identifier, "deliveryMethod": method}
+	res := &struct {
+		Code string `json:"code"`
+	}{}
+	if err := u.generate(api.Routes.ManagementUserGenerateOTPForTest(), req, res, managementKey); err != nil {
+		return "", err
+	}
+	return res.Code, nil
+}
+
+func (u *user) GenerateMagicLinkForTestUser(managementKey string, method auth.DeliveryMethod, identifier, URI string) (string, error) {
+	if identifier == "" {
+		return "", errors.NewInvalidArgumentError("identifier")
+	}
+	if method == "" {
+		return "", errors.NewInvalidArgumentError("method")
+	}
+	req := map[string]any{"identifier": identifier, "deliveryMethod": method, "URI": URI}
+	res := &struct {
+		Link string `json:"link"`
+	}{}
+	if err := u.generate(api.Routes.ManagementUserGenerateMagicLinkForTest(), req, res, managementKey); err != nil {
+		return "", err
+	}
+	return res.Link, nil
+}
+
+func (u *user) generate(uri string, req map[string]any, res any, managementKey string) error {
+	httpRes, err := u.client.DoPostRequest(uri, req, nil, managementKey)
+	if err != nil {
+		return err
+	}
+	return utils.Unmarshal([]byte(httpRes.BodyStr), res)
+}
+
 func makeCreateUpdateUserRequest(identifier, email, phone, displayName string, roles []string, tenants []UserTenants) map[string]any {
 	return map[string]any{
 		"identifier":  identifier,
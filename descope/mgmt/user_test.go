@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
 	"github.com/descope/go-sdk/descope/tests/helpers"
 	"github.com/stretchr/testify/require"
 )
@@ -29,6 +31,25 @@ func TestUserCreateError(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestUserCreateTestUserSuccess(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOk(func(r *http.Request) {
+		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
+		req := map[string]any{}
+		require.NoError(t, helpers.ReadBody(r, &req))
+		require.Equal(t, "abc", req["identifier"])
+		require.Equal(t, "foo@bar.com", req["email"])
+		require.Equal(t, true, req["test"])
+	}))
+	err := mgmt.User().CreateTestUser("key", "abc", "foo@bar.com", "", "", nil, nil)
+	require.NoError(t, err)
+}
+
+func TestUserCreateTestUserError(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOk(nil))
+	err := mgmt.User().CreateTestUser("key", "", "foo@bar.com", "", "", nil, nil)
+	require.Error(t, err)
+}
+
 func TestUserUpdateSuccess(t *testing.T) {
 	mgmt := newTestMgmt(nil, helpers.DoOk(func(r *http.Request) {
 		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
@@ -77,3 +98,84 @@ func TestUserDeleteError(t *testing.T) {
 	err := mgmt.User().Delete("key", "")
 	require.Error(t, err)
 }
+
+func TestUserDeleteAllTestUsersSuccess(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOk(func(r *http.Request) {
+		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
+		require.Equal(t, http.MethodDelete, r.Method)
+		require.Equal(t, api.Routes.ManagementUserDeleteAllTestUsers(), r.URL.Path)
+	}))
+	err := mgmt.User().DeleteAllTestUsers("key")
+	require.NoError(t, err)
+}
+
+func TestUserDeleteAllTestUsersError(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoBadRequest(nil))
+	err := mgmt.User().DeleteAllTestUsers("key")
+	require.Error(t, err)
+}
+
+func TestUserGenerateEmbeddedLinkSuccess(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOkWithBody(func(r *http.Request) {
+		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
+		req := map[string]any{}
+		require.NoError(t, helpers.ReadBody(r, &req))
+		require.Equal(t, "abc", req["identifier"])
+		require.Equal(t, map[string]any{"k1": "v1"}, req["customClaims"])
+	}, map[string]any{"token": "tkn"}))
+	token, err := mgmt.User().GenerateEmbeddedLink("key", "abc", map[string]any{"k1": "v1"})
+	require.NoError(t, err)
+	require.Equal(t, "tkn", token)
+}
+
+func TestUserGenerateEmbeddedLinkError(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOk(nil))
+	_, err := mgmt.User().GenerateEmbeddedLink("key", "", nil)
+	require.Error(t, err)
+	mgmt = newTestMgmt(nil, helpers.DoBadRequest(nil))
+	_, err = mgmt.User().GenerateEmbeddedLink("key", "abc", nil)
+	require.Error(t, err)
+}
+
+func TestUserGenerateOTPForTestUserSuccess(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOkWithBody(func(r *http.Request) {
+		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
+		req := map[string]any{}
+		require.NoError(t, helpers.ReadBody(r, &req))
+		require.Equal(t, "abc", req["identifier"])
+		require.Equal(t, "email", req["deliveryMethod"])
+	}, map[string]any{"code": "123456"}))
+	code, err := mgmt.User().GenerateOTPForTestUser("key", auth.MethodEmail, "abc")
+	require.NoError(t, err)
+	require.Equal(t, "123456", code)
+}
+
+func TestUserGenerateOTPForTestUserError(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOk(nil))
+	_, err := mgmt.User().GenerateOTPForTestUser("key", auth.MethodEmail, "")
+	require.Error(t, err)
+	_, err = mgmt.User().GenerateOTPForTestUser("key", "", "abc")
+	require.Error(t, err)
+}
+
+func TestUserGenerateMagicLinkForTestUserSuccess(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOkWithBody(func(r *http.Request) {
+		require.Equal(t, r.Header.Get("Authorization"), "Bearer a:key")
+		req := map[string]any{}
+		require.NoError(t, helpers.ReadBody(r, &req))
+		require.Equal(t, "abc", req["identifier"])
+		require.Equal(t, "sms", req["deliveryMethod"])
+		require.Equal(t, "https://example.com/verify", req["URI"])
+	}, map[string]any{"link": "https://example.com/verify?t=tkn"}))
+	link, err := mgmt.User().GenerateMagicLinkForTestUser("key", auth.MethodSMS, "abc", "https://example.com/verify")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/verify?t=tkn", link)
+}
+
+func TestUserGenerateMagicLinkForTestUserError(t *testing.T) {
+	mgmt := newTestMgmt(nil, helpers.DoOk(nil))
+	_, err := mgmt.User().GenerateMagicLinkForTestUser("key", auth.MethodSMS, "", "")
+	require.Error(t, err)
+	_, err = mgmt.User().GenerateMagicLinkForTestUser("key", "", "abc", "")
+	require.Error(t, err)
+}
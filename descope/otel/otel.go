@@ -0,0 +1,239 @@
+// Package otel provides OpenTelemetry instrumentation for the Descope SDK, as an observer and a middleware to be added to
+// the client configuration:
+//
+//	observer, err := otel.NewObserver(nil)
+//	descopeClient, err := descope.NewDescopeClientWithConfig(&descope.Config{
+//		ProjectID:   projectID,
+//		Observers:   []descope.Observer{observer},
+//		Middlewares: []api.Middleware{observer.Middleware()},
+//	})
+//
+// The middleware traces every request sent to Descope with a client span, a child of the span in the context of the
+// request, and injects the span into the request headers with the propagator. The observer creates a span for every
+// session validation, a child of the span in the context of the validated request, and records counters and latency
+// histograms of both the requests and the validations. Since the validation spans are created when the validation
+// ends, the requests sent while validating a session, such as to refresh it, are siblings of the validation span
+// rather than its children.
+//
+// The authentication functions that take an http request send their requests with its context, use
+// descope.DescopeClient.AuthWithContext and ManagementWithContext to trace the other requests as children of a span.
+package otel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/descope/go-sdk/descope"
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
+)
+
+// InstrumentationName - the name of the tracer and meter of the observer
+const InstrumentationName = "github.com/descope/go-sdk/descope/otel"
+
+const (
+	// ProjectIDKey - the project of the client that sent the request or validated the session
+	ProjectIDKey = attribute.Key("descope.project_id")
+	// RouteKey - the route of a request sent to Descope, such as /v1/auth/otp/signin
+	RouteKey = attribute.Key("descope.route")
+	// ErrorCodeKey - the Descope error code of a failed request
+	ErrorCodeKey = attribute.Key("descope.error_code")
+	// OutcomeKey - the outcome of a session validation, see auth.ValidationOutcome
+	OutcomeKey = attribute.Key("descope.validation.outcome")
+	// RefreshTriggeredKey - whether the session was refreshed, or refreshing it was attempted, during the validation
+	RefreshTriggeredKey = attribute.Key("descope.validation.refresh_triggered")
+	// KeysCacheKey - whether the public keys of the project were cached ("hit") or fetched ("miss") during the validation
+	KeysCacheKey = attribute.Key("descope.keys.cache")
+	// SuccessKey - whether fetching the public keys succeeded
+	SuccessKey = attribute.Key("descope.success")
+)
+
+// Options - optional configuration for the observer
+type Options struct {
+	// TracerProvider (optional, the global provider) - the provider of the tracer the spans are created with.
+	TracerProvider trace.TracerProvider
+	// MeterProvider (optional, the global provider) - the provider of the meter the metrics are recorded with.
+	MeterProvider metric.MeterProvider
+	// Propagator (optional, the global propagator) - injects the spans of the requests into their headers.
+	Propagator propagation.TextMapPropagator
+}
+
+// Observer - a descope.Observer that traces and measures the requests sent to Descope and the validated sessions
+type Observer struct {
+	tracer             trace.Tracer
+	propagator         propagation.TextMapPropagator
+	requests           syncint64.Counter
+	requestDuration    syncfloat64.Histogram
+	validations        syncint64.Counter
+	validationDuration syncfloat64.Histogram
+	keyFetches         syncint64.Counter
+}
+
+var _ descope.Observer = &Observer{}
+
+// NewObserver - creates an observer with the given options, or with the global providers when nil
+func NewObserver(options *Options) (*Observer, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	tracerProvider := options.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	meterProvider := options.MeterProvider
+	if meterProvider == nil {
+		meterProvider = global.MeterProvider()
+	}
+	meter := meterProvider.Meter(InstrumentationName)
+	propagator := options.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	o := &Observer{tracer: tracerProvider.Tracer(InstrumentationName), propagator: propagator}
+	var err error
+	if o.requests, err = meter.SyncInt64().Counter("descope.client.requests", instrument.WithDescription("The number of requests sent to Descope")); err != nil {
+		return nil, err
+	}
+	if o.requestDuration, err = meter.SyncFloat64().Histogram("descope.client.request.duration", instrument.WithDescription("The duration of requests sent to Descope"), instrument.WithUnit(unit.Milliseconds)); err != nil {
+		return nil, err
+	}
+	if o.validations, err = meter.SyncInt64().Counter("descope.session.validations", instrument.WithDescription("The number of session validations")); err != nil {
+		return nil, err
+	}
+	if o.validationDuration, err = meter.SyncFloat64().Histogram("descope.session.validation.duration", instrument.WithDescription("The duration of session validations"), instrument.WithUnit(unit.Milliseconds)); err != nil {
+		return nil, err
+	}
+	if o.keyFetches, err = meter.SyncInt64().Counter("descope.keys.fetches", instrument.WithDescription("The number of times the public keys of a project were fetched")); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Middleware - returns the middleware that traces the requests sent to Descope, to be added to the Middlewares of the
+// client configuration. The span is started before the request is sent, and its context is injected into the request
+// headers.
+func (o *Observer) Middleware() api.Middleware {
+	return func(next api.Doer) api.Doer {
+		return api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			projectID := requestProjectID(req)
+			route := strings.TrimSuffix(req.URL.Path, "/"+projectID)
+			ctx, span := o.tracer.Start(req.Context(), fmt.Sprintf("descope %s %s", req.Method, route), trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(ProjectIDKey.String(projectID), semconv.HTTPMethodKey.String(req.Method), RouteKey.String(route)))
+			defer span.End()
+			req = req.WithContext(ctx)
+			o.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			res, err := next.Do(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return res, err
+			}
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+			if res.StatusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, http.StatusText(res.StatusCode))
+				if errorCode := responseErrorCode(res); errorCode != "" {
+					span.SetAttributes(ErrorCodeKey.String(errorCode))
+				}
+			}
+			return res, nil
+		})
+	}
+}
+
+// ObserveRequest - records the count and duration of the request, which is traced by the Middleware
+func (o *Observer) ObserveRequest(event api.RequestEvent) {
+	routeAttrs := []attribute.KeyValue{ProjectIDKey.String(event.ProjectID), semconv.HTTPMethodKey.String(event.Method), RouteKey.String(event.Route)}
+	attrs := append([]attribute.KeyValue{}, routeAttrs...)
+	if event.StatusCode != 0 {
+		attrs = append(attrs, semconv.HTTPStatusCodeKey.Int(event.StatusCode))
+	}
+	if event.ErrorCode != "" {
+		attrs = append(attrs, ErrorCodeKey.String(event.ErrorCode))
+	}
+	o.requests.Add(event.Context, 1, attrs...)
+	o.requestDuration.Record(event.Context, milliseconds(event.Duration), routeAttrs...)
+}
+
+// ObserveValidation - creates a span for the validation and records its count and duration
+func (o *Observer) ObserveValidation(event auth.ValidationEvent) {
+	keysCache := "hit"
+	if event.KeysFetched {
+		keysCache = "miss"
+	}
+	attrs := []attribute.KeyValue{
+		ProjectIDKey.String(event.ProjectID),
+		OutcomeKey.String(string(event.Outcome)),
+		RefreshTriggeredKey.Bool(event.RefreshTriggered),
+		KeysCacheKey.String(keysCache),
+	}
+
+	_, span := o.tracer.Start(event.Context, "descope ValidateSession", trace.WithTimestamp(event.Start), trace.WithAttributes(attrs...))
+	endSpan(span, event.Start.Add(event.Duration), event.Err)
+
+	o.validations.Add(event.Context, 1, attrs...)
+	o.validationDuration.Record(event.Context, milliseconds(event.Duration), ProjectIDKey.String(event.ProjectID), OutcomeKey.String(string(event.Outcome)))
+}
+
+// ObserveKeysFetch - counts the fetches of the public keys, which are also traced as requests
+func (o *Observer) ObserveKeysFetch(event auth.KeysEvent) {
+	o.keyFetches.Add(context.Background(), 1, ProjectIDKey.String(event.ProjectID), SuccessKey.Bool(event.Err == nil))
+}
+
+func endSpan(span trace.Span, end time.Time, err error) {
+	if err != nil {
+		span.RecordError(err, trace.WithTimestamp(end))
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// requestProjectID returns the project ID of the authorization header the client sets on its requests,
+// the project ID optionally followed by a colon and a key or token
+func requestProjectID(req *http.Request) string {
+	bearer := strings.TrimPrefix(req.Header.Get(api.AuthorizationHeaderName), api.BearerAuthorizationPrefix)
+	projectID, _, _ := strings.Cut(bearer, ":")
+	return projectID
+}
+
+// responseErrorCode returns the Descope error code of a failed response, leaving its body to be read again
+func responseErrorCode(res *http.Response) string {
+	if res.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var webErr struct {
+		ErrorCode string `json:"errorCode"`
+	}
+	_ = json.Unmarshal(body, &webErr)
+	return webErr.ErrorCode
+}
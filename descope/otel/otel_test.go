@@ -0,0 +1,181 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/descope/go-sdk/descope"
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/tests/descopetest"
+)
+
+type testObserver struct {
+	*Observer
+	exporter *tracetest.InMemoryExporter
+	reader   sdkmetric.Reader
+	tracer   trace.Tracer
+}
+
+func newTestObserver(t *testing.T) *testObserver {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	observer, err := NewObserver(&Options{
+		TracerProvider: tracerProvider,
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		Propagator:     propagation.TraceContext{},
+	})
+	require.NoError(t, err)
+	return &testObserver{Observer: observer, exporter: exporter, reader: reader, tracer: tracerProvider.Tracer("test")}
+}
+
+func (o *testObserver) span(t *testing.T, name string) tracetest.SpanStub {
+	for _, span := range o.exporter.GetSpans() {
+		if span.Name == name {
+			return span
+		}
+	}
+	require.Failf(t, "span not found", name)
+	return tracetest.SpanStub{}
+}
+
+// counter returns the value of the data point of the given counter that has all the given attributes
+func (o *testObserver) counter(t *testing.T, name string, attrs ...attribute.KeyValue) int64 {
+	metrics, err := o.reader.Collect(context.Background())
+	require.NoError(t, err)
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if m.Name != name || !ok {
+				continue
+			}
+			for _, point := range sum.DataPoints {
+				if hasAttributes(point.Attributes, attrs) {
+					return point.Value
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func hasAttributes(set attribute.Set, attrs []attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if v, ok := set.Value(attr.Key); !ok || v != attr.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMiddleware(t *testing.T) {
+	traceparents := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		switch r.URL.Path {
+		case "/v1/auth/otp/signup-in/email":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errorCode":"E011002","errorDescription":"bad request"}`))
+		default:
+			_, _ = w.Write([]byte(`{"keys":[]}`))
+		}
+	}))
+	defer server.Close()
+	observer := newTestObserver(t)
+	client, err := descope.NewDescopeClientWithConfig(&descope.Config{
+		ProjectID:      "P1",
+		DescopeBaseURL: server.URL,
+		Observers:      []descope.Observer{observer},
+		Middlewares:    []api.Middleware{observer.Middleware()},
+	})
+	require.NoError(t, err)
+
+	ctx, parent := observer.tracer.Start(context.Background(), "parent")
+	err = client.AuthWithContext(ctx).OTP().SignUpOrIn(auth.MethodEmail, "dude@example.com")
+	parent.End()
+	require.Error(t, err)
+
+	span := observer.span(t, "descope POST /v1/auth/otp/signup-in/email")
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+	assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext.TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	assert.Equal(t, codes.Error, span.Status.Code)
+	assert.Contains(t, span.Attributes, ProjectIDKey.String("P1"))
+	assert.Contains(t, span.Attributes, semconv.HTTPStatusCodeKey.Int(http.StatusBadRequest))
+	assert.Contains(t, span.Attributes, ErrorCodeKey.String("E011002"))
+	// the span is propagated to Descope
+	require.Len(t, traceparents, 1)
+	assert.Contains(t, traceparents[0], span.SpanContext.TraceID().String()+"-"+span.SpanContext.SpanID().String())
+
+	assert.EqualValues(t, 1, observer.counter(t, "descope.client.requests", RouteKey.String("/v1/auth/otp/signup-in/email"), ErrorCodeKey.String("E011002")))
+
+	// the project ID is removed from the route of the public keys
+	minter, err := descopetest.NewMinter("P1")
+	require.NoError(t, err)
+	sessionJwt, err := minter.SessionToken(descopetest.TokenOptions{})
+	require.NoError(t, err)
+	ok, _, _ := client.Auth.ValidateSessionTokens(sessionJwt, "")
+	assert.False(t, ok)
+	span = observer.span(t, "descope GET /v1/keys")
+	assert.Contains(t, span.Attributes, RouteKey.String("/v1/keys"))
+	assert.Equal(t, codes.Unset, span.Status.Code)
+	assert.NotZero(t, observer.counter(t, "descope.keys.fetches", ProjectIDKey.String("P1")))
+}
+
+func TestMiddlewareTransportError(t *testing.T) {
+	observer := newTestObserver(t)
+	client, err := descope.NewDescopeClientWithConfig(&descope.Config{
+		ProjectID:      "P1",
+		DescopeBaseURL: "http://localhost:0",
+		Middlewares:    []api.Middleware{observer.Middleware()},
+	})
+	require.NoError(t, err)
+	require.Error(t, client.Auth.OTP().SignUpOrIn(auth.MethodEmail, "dude@example.com"))
+
+	span := observer.span(t, "descope POST /v1/auth/otp/signup-in/email")
+	assert.Equal(t, codes.Error, span.Status.Code)
+	assert.False(t, span.Parent.IsValid())
+	require.Len(t, span.Events, 1)
+	assert.EqualValues(t, "exception", span.Events[0].Name)
+}
+
+func TestObserveValidation(t *testing.T) {
+	observer := newTestObserver(t)
+	minter, err := descopetest.NewMinter("P1")
+	require.NoError(t, err)
+	config := minter.Config()
+	config.Observers = []descope.Observer{observer}
+	client, err := descope.NewDescopeClientWithConfig(config)
+	require.NoError(t, err)
+	sessionJwt, err := minter.SessionToken(descopetest.TokenOptions{})
+	require.NoError(t, err)
+
+	ctx, parent := observer.tracer.Start(context.Background(), "parent")
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	descopetest.AddCookies(r, sessionJwt, "")
+	ok, _, err := client.Auth.ValidateSession(r, nil)
+	parent.End()
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	span := observer.span(t, "descope ValidateSession")
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	assert.Contains(t, span.Attributes, OutcomeKey.String(string(auth.ValidationValid)))
+	assert.Contains(t, span.Attributes, KeysCacheKey.String("hit"))
+	assert.EqualValues(t, 1, observer.counter(t, "descope.session.validations", OutcomeKey.String(string(auth.ValidationValid))))
+}
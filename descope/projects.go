@@ -0,0 +1,159 @@
+package descope
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/lestrrat-go/jwx/v2/jwt"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/logger"
+)
+
+// Projects - a registry of clients for several Descope projects in a single process, such as a gateway that fronts
+// several projects. Sessions are validated by the client of the project that issued the session token.
+//
+// The clients share one http client, and so one pool of connections, while each client keeps its own public keys.
+// Logging is process wide, so the LogLevel and Logger of added configs are ignored, see NewProjects.
+type Projects struct {
+	httpClient api.IHttpClient
+	mu         sync.RWMutex
+	clients    map[string]*DescopeClient
+}
+
+// NewProjects - creates an empty registry whose clients send requests with the given http client, or with a new
+// client created by api.NewDefaultHttpClient when nil. The log level and logger are set for all the clients.
+func NewProjects(httpClient api.IHttpClient, logLevel logger.LogLevel, log logger.LoggerInterface) *Projects {
+	if httpClient == nil {
+		httpClient = api.NewDefaultHttpClient()
+	}
+	logger.Init(logLevel, log)
+	return &Projects{httpClient: httpClient, clients: map[string]*DescopeClient{}}
+}
+
+// Add - creates a client for the project in the given config and adds it to the registry. The project ID is required
+// and is not read from the environment variables, same as the public key. The shared http client is used unless the
+// config sets a DefaultClient.
+func (p *Projects) Add(config *Config) (*DescopeClient, error) {
+	if config == nil {
+		return nil, errors.NewInvalidArgumentError("config")
+	}
+	// the defaults are filled in a copy, the caller may add the same config to another registry
+	c := *config
+	if c.DefaultClient == nil {
+		c.DefaultClient = p.httpClient
+	}
+	client, err := newDescopeClient(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.clients[c.ProjectID]; ok {
+		return nil, errors.NewValidationError("project %s was already added", c.ProjectID)
+	}
+	p.clients[c.ProjectID] = client
+	return client, nil
+}
+
+// Remove - removes the client of the given project from the registry
+func (p *Projects) Remove(projectID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, projectID)
+}
+
+// Client - returns the client of the given project, and whether it was added
+func (p *Projects) Client(projectID string) (*DescopeClient, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	client, ok := p.clients[projectID]
+	return client, ok
+}
+
+// ProjectIDs - the IDs of the added projects
+func (p *Projects) ProjectIDs() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	projectIDs := []string{}
+	for projectID := range p.clients {
+		projectIDs = append(projectIDs, projectID)
+	}
+	return projectIDs
+}
+
+// ClientForToken - returns the client of the project that issued the given JWT. The token is not verified, which is
+// left to the returned client. Returns UnknownProjectError when the project was not added.
+func (p *Projects) ClientForToken(token string) (*DescopeClient, error) {
+	projectID, err := issuerProjectID(token)
+	if err != nil {
+		return nil, err
+	}
+	client, ok := p.Client(projectID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errors.UnknownProjectError, projectID)
+	}
+	return client, nil
+}
+
+// ValidateSession - validates the session of the given request with the client of the project that issued the session
+// token, or the refresh token when there is no session token. See auth.Authentication.ValidateSession.
+// The registry can be used with auth.AuthenticationMiddleware.
+func (p *Projects) ValidateSession(request *http.Request, w http.ResponseWriter) (bool, *auth.Token, error) {
+	if request == nil {
+		return false, nil, errors.MissingRequestError
+	}
+	sessionToken, refreshToken := auth.RequestTokens(request)
+	if sessionToken == "" && refreshToken == "" {
+		logger.LogDebug("unable to find token from cookies")
+		return false, nil, nil
+	}
+	client, err := p.clientForTokens(sessionToken, refreshToken)
+	if err != nil {
+		return false, nil, err
+	}
+	return client.Auth.ValidateSession(request, w)
+}
+
+// ValidateSessionTokens - validates the given tokens with the client of the project that issued them.
+// See auth.Authentication.ValidateSessionTokens.
+func (p *Projects) ValidateSessionTokens(sessionToken, refreshToken string) (bool, *auth.Token, error) {
+	client, err := p.clientForTokens(sessionToken, refreshToken)
+	if err != nil {
+		return false, nil, err
+	}
+	return client.Auth.ValidateSessionTokens(sessionToken, refreshToken)
+}
+
+func (p *Projects) clientForTokens(sessionToken, refreshToken string) (*DescopeClient, error) {
+	token := sessionToken
+	if token == "" {
+		token = refreshToken
+	}
+	if token == "" {
+		return nil, errors.RefreshTokenError
+	}
+	return p.ClientForToken(token)
+}
+
+// issuerProjectID returns the project ID in the issuer claim of the given JWT without verifying it. The issuer is either
+// the project ID or a URL that ends with it.
+func issuerProjectID(token string) (string, error) {
+	parsed, err := jwt.Parse([]byte(token), jwt.WithVerify(false), jwt.WithValidate(false))
+	if err != nil {
+		return "", errors.NewInvalidArgumentError("token")
+	}
+	issuer := strings.TrimSuffix(parsed.Issuer(), "/")
+	if i := strings.LastIndex(issuer, "/"); i >= 0 {
+		issuer = issuer[i+1:]
+	}
+	if issuer == "" {
+		return "", errors.NewInvalidArgumentError("token")
+	}
+	return issuer, nil
+}
@@ -0,0 +1,166 @@
+package descope
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/logger"
+)
+
+type testProject struct {
+	projectID  string
+	privateKey jwk.Key
+	publicKey  string
+}
+
+func newTestProject(t *testing.T, projectID string) *testProject {
+	raw, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	privateKey, err := jwk.FromRaw(raw)
+	require.NoError(t, err)
+	require.NoError(t, privateKey.Set(jwk.KeyIDKey, projectID+"-key"))
+	require.NoError(t, privateKey.Set(jwk.AlgorithmKey, jwa.ES256))
+	publicKey, err := privateKey.PublicKey()
+	require.NoError(t, err)
+	b, err := json.Marshal(publicKey)
+	require.NoError(t, err)
+	return &testProject{projectID: projectID, privateKey: privateKey, publicKey: string(b)}
+}
+
+func (p *testProject) sessionToken(t *testing.T, issuer string) string {
+	token := jwt.New()
+	require.NoError(t, token.Set(jwt.SubjectKey, "user-of-"+p.projectID))
+	require.NoError(t, token.Set(jwt.IssuerKey, issuer))
+	require.NoError(t, token.Set(jwt.ExpirationKey, time.Now().Add(time.Hour)))
+	require.NoError(t, token.Set("drn", auth.SessionCookieName))
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.ES256, p.privateKey))
+	require.NoError(t, err)
+	return string(signed)
+}
+
+type countingClient struct {
+	requests int
+}
+
+func (c *countingClient) Do(*http.Request) (*http.Response, error) {
+	c.requests++
+	return nil, errors.NoPublicKeyError
+}
+
+func TestProjectsValidateSession(t *testing.T) {
+	p1, p2 := newTestProject(t, "P1"), newTestProject(t, "P2")
+	projects := NewProjects(nil, logger.LogNone, nil)
+	for _, p := range []*testProject{p1, p2} {
+		_, err := projects.Add(&Config{ProjectID: p.projectID, PublicKey: p.publicKey})
+		require.NoError(t, err)
+	}
+	assert.ElementsMatch(t, []string{"P1", "P2"}, projects.ProjectIDs())
+
+	ok, token, err := projects.ValidateSessionTokens(p1.sessionToken(t, "P1"), "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, "user-of-P1", token.ID)
+
+	// issuers can be URLs that end with the project ID
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(api.AuthorizationHeaderName, api.BearerAuthorizationPrefix+p2.sessionToken(t, "https://api.descope.com/P2"))
+	ok, token, err = projects.ValidateSession(r, httptest.NewRecorder())
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, "user-of-P2", token.ID)
+
+	// the key caches are isolated, a token of one project that claims to be issued by another is rejected
+	ok, _, err = projects.ValidateSessionTokens(p1.sessionToken(t, "P2"), "")
+	assert.False(t, ok)
+	assert.Error(t, err)
+
+	ok, _, err = projects.ValidateSessionTokens(newTestProject(t, "P3").sessionToken(t, "P3"), "")
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, errors.UnknownProjectError)
+
+	ok, _, err = projects.ValidateSessionTokens("not a jwt", "")
+	assert.False(t, ok)
+	assert.Error(t, err)
+
+	ok, token, err = projects.ValidateSession(httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	assert.False(t, ok)
+	assert.Nil(t, token)
+	assert.NoError(t, err)
+	_, _, err = projects.ValidateSession(nil, nil)
+	assert.ErrorIs(t, err, errors.MissingRequestError)
+
+	projects.Remove("P1")
+	_, err = projects.ClientForToken(p1.sessionToken(t, "P1"))
+	assert.ErrorIs(t, err, errors.UnknownProjectError)
+}
+
+func TestProjectsAdd(t *testing.T) {
+	httpClient := &countingClient{}
+	projects := NewProjects(httpClient, logger.LogNone, nil)
+
+	_, err := projects.Add(nil)
+	assert.Error(t, err)
+	_, err = projects.Add(&Config{})
+	assert.ErrorContains(t, err, "project id is missing")
+
+	config := &Config{ProjectID: "P1"}
+	client, err := projects.Add(config)
+	require.NoError(t, err)
+	assert.Equal(t, httpClient, client.config.DefaultClient)
+	// the caller's config is not changed
+	assert.Nil(t, config.DefaultClient)
+	assert.NotSame(t, config, client.config)
+	_, err = projects.Add(&Config{ProjectID: "P1"})
+	assert.ErrorContains(t, err, "already added")
+
+	// the public keys are fetched with the shared http client
+	p2 := newTestProject(t, "P2")
+	_, err = projects.Add(&Config{ProjectID: "P2"})
+	require.NoError(t, err)
+	ok, _, _ := projects.ValidateSessionTokens(p2.sessionToken(t, "P2"), "")
+	assert.False(t, ok)
+	assert.NotZero(t, httpClient.requests)
+
+	found, ok := projects.Client("P1")
+	assert.True(t, ok)
+	assert.Same(t, client, found)
+	_, ok = projects.Client("P3")
+	assert.False(t, ok)
+}
+
+func TestProjectsMiddleware(t *testing.T) {
+	p1 := newTestProject(t, "P1")
+	projects := NewProjects(nil, logger.LogNone, nil)
+	_, err := projects.Add(&Config{ProjectID: p1.projectID, PublicKey: p1.publicKey})
+	require.NoError(t, err)
+
+	handler := auth.AuthenticationMiddleware(projects, nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := auth.TokenFromContext(r.Context())
+		require.True(t, ok)
+		assert.EqualValues(t, "P1", token.ProjectID)
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: auth.SessionCookieName, Value: p1.sessionToken(t, "P1")})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.EqualValues(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.EqualValues(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,146 @@
+// Package prometheus provides Prometheus metrics for the Descope SDK, as a collector that observes a client and is
+// registered with a Prometheus registry:
+//
+//	collector := prometheus.NewCollector(nil)
+//	promclient.MustRegister(collector)
+//	descopeClient, err := descope.NewDescopeClientWithConfig(&descope.Config{ProjectID: projectID, Observers: []descope.Observer{collector}})
+//
+// The collector counts session validations by outcome and public key fetches, and measures the latency of the requests
+// sent to Descope by route and counts the failed ones by status and Descope error code. A collector may observe
+// several clients, the metrics are labeled with the project of the client.
+package prometheus
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/descope/go-sdk/descope"
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
+)
+
+const (
+	// ProjectIDLabel - the project of the client that sent the request or validated the session
+	ProjectIDLabel = "project_id"
+	// OutcomeLabel - the outcome of a session validation, see auth.ValidationOutcome
+	OutcomeLabel = "outcome"
+	// ResultLabel - whether fetching the public keys succeeded, "success" or "failure"
+	ResultLabel = "result"
+	// MethodLabel - the http method of a request sent to Descope
+	MethodLabel = "method"
+	// RouteLabel - the route of a request sent to Descope, such as /v1/auth/otp/signin
+	RouteLabel = "route"
+	// StatusLabel - the http status of a failed request, or "0" when no response was received
+	StatusLabel = "status"
+	// ErrorCodeLabel - the Descope error code of a failed request, empty when the response has none
+	ErrorCodeLabel = "error_code"
+)
+
+// Options - optional configuration for the collector
+type Options struct {
+	// Namespace (optional, "descope") - the prefix of the names of the metrics.
+	Namespace string
+	// ConstLabels (optional) - labels added to all the metrics, such as the name of the service.
+	ConstLabels prometheus.Labels
+	// Buckets (optional, prometheus.DefBuckets) - the buckets, in seconds, of the request latency histogram.
+	Buckets []float64
+}
+
+// Collector - a prometheus.Collector and a descope.Observer with the metrics of the observed clients
+type Collector struct {
+	validations     *prometheus.CounterVec
+	keyFetches      *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	requestErrors   *prometheus.CounterVec
+}
+
+var (
+	_ prometheus.Collector = &Collector{}
+	_ descope.Observer     = &Collector{}
+)
+
+// NewCollector - creates a collector with the given options, or the defaults when nil. The collector has to be
+// registered with a registry, such as with prometheus.MustRegister, and added to the observers of the client config.
+func NewCollector(options *Options) *Collector {
+	if options == nil {
+		options = &Options{}
+	}
+	namespace := options.Namespace
+	if namespace == "" {
+		namespace = "descope"
+	}
+	buckets := options.Buckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	return &Collector{
+		validations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "session",
+			Name:        "validations_total",
+			Help:        "The number of session validations by outcome.",
+			ConstLabels: options.ConstLabels,
+		}, []string{ProjectIDLabel, OutcomeLabel}),
+		keyFetches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "keys",
+			Name:        "fetches_total",
+			Help:        "The number of times the public keys of a project were fetched to validate sessions.",
+			ConstLabels: options.ConstLabels,
+		}, []string{ProjectIDLabel, ResultLabel}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        "request_duration_seconds",
+			Help:        "The latency of requests sent to Descope by route.",
+			ConstLabels: options.ConstLabels,
+			Buckets:     buckets,
+		}, []string{ProjectIDLabel, MethodLabel, RouteLabel}),
+		requestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "client",
+			Name:        "request_errors_total",
+			Help:        "The number of failed requests sent to Descope by route, status and error code.",
+			ConstLabels: options.ConstLabels,
+		}, []string{ProjectIDLabel, MethodLabel, RouteLabel, StatusLabel, ErrorCodeLabel}),
+	}
+}
+
+// Describe - implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.validations.Describe(ch)
+	c.keyFetches.Describe(ch)
+	c.requestDuration.Describe(ch)
+	c.requestErrors.Describe(ch)
+}
+
+// Collect - implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.validations.Collect(ch)
+	c.keyFetches.Collect(ch)
+	c.requestDuration.Collect(ch)
+	c.requestErrors.Collect(ch)
+}
+
+// ObserveRequest - measures the latency of the request and counts it when failed
+func (c *Collector) ObserveRequest(event api.RequestEvent) {
+	c.requestDuration.WithLabelValues(event.ProjectID, event.Method, event.Route).Observe(event.Duration.Seconds())
+	if event.Err != nil {
+		c.requestErrors.WithLabelValues(event.ProjectID, event.Method, event.Route, strconv.Itoa(event.StatusCode), event.ErrorCode).Inc()
+	}
+}
+
+// ObserveValidation - counts the validation by its outcome
+func (c *Collector) ObserveValidation(event auth.ValidationEvent) {
+	c.validations.WithLabelValues(event.ProjectID, string(event.Outcome)).Inc()
+}
+
+// ObserveKeysFetch - counts the fetch of the public keys by its result
+func (c *Collector) ObserveKeysFetch(event auth.KeysEvent) {
+	result := "success"
+	if event.Err != nil {
+		result = "failure"
+	}
+	c.keyFetches.WithLabelValues(event.ProjectID, result).Inc()
+}
@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+)
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector(&Options{ConstLabels: prometheus.Labels{"service": "test"}})
+
+	collector.ObserveValidation(auth.ValidationEvent{ProjectID: "P1", Outcome: auth.ValidationValid})
+	collector.ObserveValidation(auth.ValidationEvent{ProjectID: "P1", Outcome: auth.ValidationValid})
+	collector.ObserveValidation(auth.ValidationEvent{ProjectID: "P1", Outcome: auth.ValidationExpired})
+	collector.ObserveValidation(auth.ValidationEvent{ProjectID: "P2", Outcome: auth.ValidationRefreshed})
+	collector.ObserveKeysFetch(auth.KeysEvent{ProjectID: "P1"})
+	collector.ObserveKeysFetch(auth.KeysEvent{ProjectID: "P1", Err: errors.NoPublicKeyError})
+	collector.ObserveRequest(api.RequestEvent{ProjectID: "P1", Method: http.MethodPost, Route: "/v1/auth/otp/signin/email", Duration: 20 * time.Millisecond, StatusCode: http.StatusOK})
+	collector.ObserveRequest(api.RequestEvent{ProjectID: "P1", Method: http.MethodPost, Route: "/v1/auth/otp/signin/email", Duration: 30 * time.Millisecond,
+		StatusCode: http.StatusBadRequest, ErrorCode: "E011002", Err: errors.UnauthorizedError})
+	collector.ObserveRequest(api.RequestEvent{ProjectID: "P1", Method: http.MethodGet, Route: "/v1/keys", Duration: time.Second, Err: errors.NoPublicKeyError})
+
+	expected := `
+# HELP descope_session_validations_total The number of session validations by outcome.
+# TYPE descope_session_validations_total counter
+descope_session_validations_total{outcome="expired",project_id="P1",service="test"} 1
+descope_session_validations_total{outcome="refreshed",project_id="P2",service="test"} 1
+descope_session_validations_total{outcome="valid",project_id="P1",service="test"} 2
+# HELP descope_keys_fetches_total The number of times the public keys of a project were fetched to validate sessions.
+# TYPE descope_keys_fetches_total counter
+descope_keys_fetches_total{project_id="P1",result="failure",service="test"} 1
+descope_keys_fetches_total{project_id="P1",result="success",service="test"} 1
+# HELP descope_client_request_errors_total The number of failed requests sent to Descope by route, status and error code.
+# TYPE descope_client_request_errors_total counter
+descope_client_request_errors_total{error_code="",method="GET",project_id="P1",route="/v1/keys",service="test",status="0"} 1
+descope_client_request_errors_total{error_code="E011002",method="POST",project_id="P1",route="/v1/auth/otp/signin/email",service="test",status="400"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"descope_session_validations_total", "descope_keys_fetches_total", "descope_client_request_errors_total"))
+	// a latency series per route, with all the requests
+	assert.EqualValues(t, 2, testutil.CollectAndCount(collector, "descope_client_request_duration_seconds"))
+}
+
+func TestCollectorNamespaceAndBuckets(t *testing.T) {
+	collector := NewCollector(&Options{Namespace: "auth", Buckets: []float64{0.1, 1}})
+	collector.ObserveRequest(api.RequestEvent{ProjectID: "P1", Method: http.MethodGet, Route: "/v1/keys", Duration: 50 * time.Millisecond})
+
+	expected := `
+# HELP auth_client_request_duration_seconds The latency of requests sent to Descope by route.
+# TYPE auth_client_request_duration_seconds histogram
+auth_client_request_duration_seconds_bucket{method="GET",project_id="P1",route="/v1/keys",le="0.1"} 1
+auth_client_request_duration_seconds_bucket{method="GET",project_id="P1",route="/v1/keys",le="1"} 1
+auth_client_request_duration_seconds_bucket{method="GET",project_id="P1",route="/v1/keys",le="+Inf"} 1
+auth_client_request_duration_seconds_sum{method="GET",project_id="P1",route="/v1/keys"} 0.05
+auth_client_request_duration_seconds_count{method="GET",project_id="P1",route="/v1/keys"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "auth_client_request_duration_seconds"))
+	// successful requests are not counted as errors
+	assert.Zero(t, testutil.CollectAndCount(collector, "auth_client_request_errors_total"))
+}
@@ -0,0 +1,90 @@
+// Package descopemock provides mocks of every public interface of the auth and mgmt packages, to be used by unit tests
+// of code that depends on the Descope SDK.
+//
+// Every mock records the calls made to it, and returns the responses queued for each method in order. Once the queue
+// of a method is empty, the mock calls the matching <Method>Func field when set, or returns zero values otherwise.
+//
+//	authMock := descopemock.NewAuthentication()
+//	authMock.OTPMock.QueueVerifyCode(&auth.AuthenticationInfo{}, nil)
+//	authMock.OTPMock.QueueVerifyCode(nil, errors.ForbiddenError)
+//	client := descope.DescopeClient{Auth: authMock, Management: descopemock.NewManagement()}
+//	...
+//	calls := authMock.OTPMock.CallsTo("VerifyCode")
+//
+// The mocks are generated from the interfaces, run "go generate" in this package after changing them.
+package descopemock
+
+//go:generate go run ./internal/mockgen/cmd
+
+import (
+	"sync"
+)
+
+// Call - a recorded call of a mocked method
+type Call struct {
+	// Method - the name of the called method
+	Method string
+	// Args - the arguments of the call, in order
+	Args []any
+}
+
+// Recorder - records the calls of a mock and holds its queued responses, embedded in every mock
+type Recorder struct {
+	mu        sync.Mutex
+	calls     []Call
+	responses map[string][][]any
+}
+
+// Calls - returns the calls made to the mock, in order
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call{}, r.calls...)
+}
+
+// CallsTo - returns the calls made to the given method of the mock, in order
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := []Call{}
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset - clears the recorded calls and the queued responses of the mock
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+	r.responses = nil
+}
+
+func (r *Recorder) record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+func (r *Recorder) enqueue(method string, response ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.responses == nil {
+		r.responses = map[string][][]any{}
+	}
+	r.responses[method] = append(r.responses[method], response)
+}
+
+func (r *Recorder) dequeue(method string) ([]any, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queue := r.responses[method]
+	if len(queue) == 0 {
+		return nil, false
+	}
+	r.responses[method] = queue[1:]
+	return queue[0], true
+}
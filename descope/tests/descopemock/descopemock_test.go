@@ -0,0 +1,97 @@
+package descopemock
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/descope/go-sdk/descope"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/mgmt"
+	"github.com/descope/go-sdk/descope/tests/descopemock/internal/mockgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedMocksUpToDate(t *testing.T) {
+	expected, err := mockgen.Generate("../..")
+	require.NoError(t, err)
+	actual, err := os.ReadFile(mockgen.Output)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual), "the mocks are out of date, run go generate in the descopemock directory")
+}
+
+func TestQueuedResponses(t *testing.T) {
+	authMock := NewAuthentication()
+	client := descope.DescopeClient{Auth: authMock}
+	info := &auth.AuthenticationInfo{FirstSeen: true}
+	authMock.OTPMock.QueueVerifyCode(info, nil)
+	authMock.OTPMock.QueueVerifyCode(nil, errors.ForbiddenError)
+
+	res, err := client.Auth.OTP().VerifyCode(auth.MethodEmail, "dude@example.com", "123456", nil)
+	require.NoError(t, err)
+	assert.Same(t, info, res)
+	res, err = client.Auth.OTP().VerifyCode(auth.MethodSMS, "+14155550100", "654321", nil)
+	assert.ErrorIs(t, err, errors.ForbiddenError)
+	assert.Nil(t, res)
+	// zero values once the queue is empty
+	res, err = client.Auth.OTP().VerifyCode(auth.MethodEmail, "dude@example.com", "123456", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	calls := authMock.OTPMock.CallsTo("VerifyCode")
+	require.Len(t, calls, 3)
+	assert.EqualValues(t, []any{auth.MethodSMS, "+14155550100", "654321", http.ResponseWriter(nil)}, calls[1].Args)
+	assert.Empty(t, authMock.OTPMock.CallsTo("SignIn"))
+	assert.Empty(t, authMock.Calls())
+}
+
+func TestFuncFallback(t *testing.T) {
+	authMock := NewAuthentication()
+	authMock.QueueExchangeAccessKey(false, nil, errors.UnauthorizedError)
+	authMock.ExchangeAccessKeyFunc = func(accessKey string) (bool, *auth.Token, error) {
+		return true, &auth.Token{ID: accessKey}, nil
+	}
+
+	ok, _, err := authMock.ExchangeAccessKey("key1")
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, errors.UnauthorizedError)
+	ok, token, err := authMock.ExchangeAccessKey("key2")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, "key2", token.ID)
+
+	authMock.QueueLogoutAll(errors.ForbiddenError)
+	assert.ErrorIs(t, authMock.LogoutAll(nil, nil), errors.ForbiddenError)
+	authMock.QueueMe(&auth.UserResponse{UserID: "U1"}, nil)
+	me, err := authMock.Me(nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, "U1", me.UserID)
+	assert.Len(t, authMock.Calls(), 4)
+
+	authMock.Reset()
+	assert.Empty(t, authMock.Calls())
+}
+
+func TestManagementMock(t *testing.T) {
+	mgmtMock := NewManagement()
+	client := descope.DescopeClient{Management: mgmtMock}
+	mgmtMock.TenantMock.QueueCreate("T1", nil)
+	mgmtMock.UserMock.QueueGenerateOTPForTestUser("123456", nil)
+	mgmtMock.SSOMock.QueueConfigureRoleMapping(errors.ForbiddenError)
+
+	id, err := client.Management.Tenant().Create("key", "Acme", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, "T1", id)
+	code, err := client.Management.User().GenerateOTPForTestUser("key", auth.MethodEmail, "dude@example.com")
+	require.NoError(t, err)
+	assert.EqualValues(t, "123456", code)
+	err = client.Management.SSO().ConfigureRoleMapping("key", "T1", []mgmt.RoleMapping{{Groups: []string{"g"}, Role: "admin"}})
+	assert.ErrorIs(t, err, errors.ForbiddenError)
+
+	require.NoError(t, client.Management.User().Create("key", "dude", "", "", "", nil, []mgmt.UserTenants{{TenantID: "T1"}}))
+	calls := mgmtMock.UserMock.CallsTo("Create")
+	require.Len(t, calls, 1)
+	assert.EqualValues(t, []mgmt.UserTenants{{TenantID: "T1"}}, calls[0].Args[6])
+}
@@ -0,0 +1,19 @@
+// Command cmd writes the descopemock mocks, it is run by "go generate" in the descopemock directory.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/descope/go-sdk/descope/tests/descopemock/internal/mockgen"
+)
+
+func main() {
+	src, err := mockgen.Generate("../..")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(mockgen.Output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,394 @@
+// Package mockgen generates the descopemock mocks from the interfaces declared in the sdk.go file of the auth and mgmt packages.
+package mockgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const modulePath = "github.com/descope/go-sdk/descope/"
+
+// Packages - the packages whose interfaces are mocked, relative to the descope directory
+var Packages = []string{"auth", "mgmt"}
+
+// Output - the generated file, relative to the descopemock directory
+const Output = "mocks_generated.go"
+
+type param struct {
+	name string
+	typ  string
+}
+
+type method struct {
+	name     string
+	params   []param
+	results  []param
+	accessor string // the mocked interface returned by an accessor method, such as Authentication.OTP
+}
+
+type mock struct {
+	name    string
+	pkg     string
+	methods []method
+}
+
+type generator struct {
+	imports map[string]string // import path by package name
+	used    map[string]bool
+	mocks   []*mock
+}
+
+// Generate - returns the source of the mocks, given the path of the descope directory
+func Generate(descopeDir string) ([]byte, error) {
+	g := &generator{imports: map[string]string{}, used: map[string]bool{}}
+	for _, pkg := range Packages {
+		if err := g.parsePackage(filepath.Join(descopeDir, pkg), pkg); err != nil {
+			return nil, err
+		}
+	}
+	return g.write()
+}
+
+func (g *generator) parsePackage(dir, pkg string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }, 0)
+	if err != nil {
+		return err
+	}
+	files, ok := pkgs[pkg]
+	if !ok {
+		return fmt.Errorf("package %s not found in %s", pkg, dir)
+	}
+	g.imports[pkg] = modulePath + pkg
+
+	localTypes := map[string]bool{}
+	interfaces := map[string]bool{}
+	for _, f := range files.Files {
+		for _, decl := range f.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					localTypes[ts.Name.Name] = true
+					if _, ok := ts.Type.(*ast.InterfaceType); ok {
+						interfaces[ts.Name.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	sdk, ok := files.Files[filepath.Join(dir, "sdk.go")]
+	if !ok {
+		return fmt.Errorf("sdk.go not found in %s", dir)
+	}
+	for _, imp := range sdk.Imports {
+		path := strings.Trim(imp.Path.Value, `"`)
+		name := filepath.Base(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		g.imports[name] = path
+	}
+
+	q := &qualifier{g: g, pkg: pkg, localTypes: localTypes}
+	for _, decl := range sdk.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			it, ok := ts.Type.(*ast.InterfaceType)
+			if !ok || !ts.Name.IsExported() {
+				continue
+			}
+			m := &mock{name: ts.Name.Name, pkg: pkg}
+			for _, field := range it.Methods.List {
+				ft, ok := field.Type.(*ast.FuncType)
+				if !ok {
+					return fmt.Errorf("embedded interfaces are not supported in %s.%s", pkg, ts.Name.Name)
+				}
+				meth := method{name: field.Names[0].Name, params: q.fields(ft.Params, "p"), results: q.fields(ft.Results, "")}
+				if len(meth.params) == 0 && ft.Results != nil && len(ft.Results.List) == 1 {
+					if ident, ok := ft.Results.List[0].Type.(*ast.Ident); ok && interfaces[ident.Name] {
+						meth.accessor = ident.Name
+					}
+				}
+				for _, p := range meth.params {
+					if p.name == "m" {
+						return fmt.Errorf("parameter name m is reserved for the receiver in %s.%s", ts.Name.Name, meth.name)
+					}
+				}
+				m.methods = append(m.methods, meth)
+			}
+			g.mocks = append(g.mocks, m)
+		}
+	}
+	return nil
+}
+
+type qualifier struct {
+	g          *generator
+	pkg        string
+	localTypes map[string]bool
+}
+
+// fields returns the names and qualified types of the given parameters or results, unnamed parameters are named
+// by the given prefix and their index, and unnamed results are left unnamed.
+func (q *qualifier) fields(list *ast.FieldList, prefix string) []param {
+	if list == nil {
+		return nil
+	}
+	var params []param
+	for _, field := range list.List {
+		typ := q.typeString(field.Type)
+		if len(field.Names) == 0 {
+			name := ""
+			if prefix != "" {
+				name = fmt.Sprintf("%s%d", prefix, len(params))
+			}
+			params = append(params, param{name: name, typ: typ})
+			continue
+		}
+		for _, n := range field.Names {
+			name := n.Name
+			if name == "_" && prefix != "" {
+				name = fmt.Sprintf("%s%d", prefix, len(params))
+			}
+			params = append(params, param{name: name, typ: typ})
+		}
+	}
+	return params
+}
+
+func (q *qualifier) typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if q.localTypes[t.Name] {
+			q.g.used[q.pkg] = true
+			return q.pkg + "." + t.Name
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + q.typeString(t.X)
+	case *ast.ArrayType:
+		if t.Len != nil {
+			panic(fmt.Sprintf("arrays are not supported: %T", t.Len))
+		}
+		return "[]" + q.typeString(t.Elt)
+	case *ast.MapType:
+		return "map[" + q.typeString(t.Key) + "]" + q.typeString(t.Value)
+	case *ast.Ellipsis:
+		return "..." + q.typeString(t.Elt)
+	case *ast.SelectorExpr:
+		pkg := t.X.(*ast.Ident).Name
+		q.g.used[pkg] = true
+		return pkg + "." + t.Sel.Name
+	case *ast.InterfaceType:
+		return "interface{}"
+	default:
+		panic(fmt.Sprintf("unsupported type expression %T", expr))
+	}
+}
+
+func (g *generator) write() ([]byte, error) {
+	b := &bytes.Buffer{}
+	b.WriteString("// Code generated by mockgen from the auth and mgmt interfaces. DO NOT EDIT.\n\n")
+	b.WriteString("package descopemock\n\nimport (\n")
+	names := []string{}
+	for name := range g.used {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// standard library packages first
+		si, sj := isStandard(g.imports[names[i]]), isStandard(g.imports[names[j]])
+		if si != sj {
+			return si
+		}
+		return g.imports[names[i]] < g.imports[names[j]]
+	})
+	for i, name := range names {
+		path, ok := g.imports[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown package %s", name)
+		}
+		if i > 0 && isStandard(g.imports[names[i-1]]) && !isStandard(path) {
+			b.WriteString("\n")
+		}
+		if filepath.Base(path) == name {
+			fmt.Fprintf(b, "\t%q\n", path)
+		} else {
+			fmt.Fprintf(b, "\t%s %q\n", name, path)
+		}
+	}
+	b.WriteString(")\n\nvar (\n")
+	for _, m := range g.mocks {
+		fmt.Fprintf(b, "\t_ %s.%s = &%s{}\n", m.pkg, m.name, m.name)
+	}
+	b.WriteString(")\n")
+	for _, m := range g.mocks {
+		g.writeMock(b, m)
+	}
+	return format.Source(b.Bytes())
+}
+
+func (g *generator) writeMock(b *bytes.Buffer, m *mock) {
+	fmt.Fprintf(b, "\n// %s - a mock of %s.%s, see the package documentation\n", m.name, m.pkg, m.name)
+	fmt.Fprintf(b, "type %s struct {\n\tRecorder\n\n", m.name)
+	for _, meth := range m.methods {
+		if meth.accessor != "" {
+			fmt.Fprintf(b, "\t// %sMock - returned by %s, set by New%s\n", meth.accessor, meth.name, m.name)
+			fmt.Fprintf(b, "\t%sMock *%s\n", meth.accessor, meth.accessor)
+		}
+	}
+	for _, meth := range m.methods {
+		if meth.accessor == "" {
+			fmt.Fprintf(b, "\t// %sFunc (optional, nil) - called by %s once its queued responses are used\n", meth.name, meth.name)
+			fmt.Fprintf(b, "\t%sFunc func(%s) %s\n", meth.name, paramList(meth.params), resultList(meth.results))
+		}
+	}
+	b.WriteString("}\n")
+
+	fmt.Fprintf(b, "\n// New%s - creates a %s mock\n", m.name, m.name)
+	fmt.Fprintf(b, "func New%s() *%s {\n\treturn &%s{\n", m.name, m.name, m.name)
+	for _, meth := range m.methods {
+		if meth.accessor != "" {
+			fmt.Fprintf(b, "\t\t%sMock: New%s(),\n", meth.accessor, meth.accessor)
+		}
+	}
+	b.WriteString("\t}\n}\n")
+
+	for _, meth := range m.methods {
+		signature := fmt.Sprintf("(%s) %s", paramList(meth.params), resultList(meth.results))
+		if meth.accessor != "" {
+			fmt.Fprintf(b, "\n// %s - returns %sMock\n", meth.name, meth.accessor)
+			fmt.Fprintf(b, "func (m *%s) %s%s {\n\treturn m.%sMock\n}\n", m.name, meth.name, signature, meth.accessor)
+			continue
+		}
+		args := argList(meth.params)
+		fmt.Fprintf(b, "\n// %s - records the call and returns the next queued response\n", meth.name)
+		fmt.Fprintf(b, "func (m *%s) %s%s {\n", m.name, meth.name, signature)
+		if args == "" {
+			fmt.Fprintf(b, "\tm.record(%q)\n", meth.name)
+		} else {
+			fmt.Fprintf(b, "\tm.record(%q, %s)\n", meth.name, args)
+		}
+		if len(meth.results) > 0 {
+			fmt.Fprintf(b, "\tif res, ok := m.dequeue(%q); ok {\n", meth.name)
+			rs := []string{}
+			for i, r := range meth.results {
+				fmt.Fprintf(b, "\t\tr%d, _ := res[%d].(%s)\n", i, i, r.typ)
+				rs = append(rs, fmt.Sprintf("r%d", i))
+			}
+			fmt.Fprintf(b, "\t\treturn %s\n\t}\n", strings.Join(rs, ", "))
+		} else {
+			fmt.Fprintf(b, "\tif _, ok := m.dequeue(%q); ok {\n\t\treturn\n\t}\n", meth.name)
+		}
+		fmt.Fprintf(b, "\tif m.%sFunc != nil {\n", meth.name)
+		if len(meth.results) > 0 {
+			fmt.Fprintf(b, "\t\treturn m.%sFunc(%s)\n\t}\n", meth.name, args)
+			zeros := []string{}
+			for _, r := range meth.results {
+				zeros = append(zeros, zeroValue(r.typ))
+			}
+			fmt.Fprintf(b, "\treturn %s\n}\n", strings.Join(zeros, ", "))
+		} else {
+			fmt.Fprintf(b, "\t\tm.%sFunc(%s)\n\t}\n}\n", meth.name, args)
+		}
+
+		queued := queueParams(meth.results)
+		fmt.Fprintf(b, "\n// Queue%s - queues a response to be returned by a call of %s\n", meth.name, meth.name)
+		fmt.Fprintf(b, "func (m *%s) Queue%s(%s) {\n", m.name, meth.name, paramList(queued))
+		if len(queued) == 0 {
+			fmt.Fprintf(b, "\tm.enqueue(%q)\n}\n", meth.name)
+		} else {
+			fmt.Fprintf(b, "\tm.enqueue(%q, %s)\n}\n", meth.name, argList(queued))
+		}
+	}
+}
+
+func isStandard(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+func paramList(params []param) string {
+	list := []string{}
+	for _, p := range params {
+		list = append(list, p.name+" "+p.typ)
+	}
+	return strings.Join(list, ", ")
+}
+
+func resultList(results []param) string {
+	types := []string{}
+	for _, r := range results {
+		types = append(types, r.typ)
+	}
+	if len(types) > 1 {
+		return "(" + strings.Join(types, ", ") + ")"
+	}
+	return strings.Join(types, "")
+}
+
+func argList(params []param) string {
+	list := []string{}
+	for _, p := range params {
+		if strings.HasPrefix(p.typ, "...") {
+			list = append(list, p.name+"...")
+		} else {
+			list = append(list, p.name)
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// queueParams names the results of a method, using the declared names when available
+func queueParams(results []param) []param {
+	params := []param{}
+	for _, r := range results {
+		name := r.name
+		if name == "" {
+			switch r.typ {
+			case "error":
+				name = "err"
+			case "bool":
+				name = "ok"
+			default:
+				base := r.typ[strings.LastIndexAny(r.typ, "*.]")+1:]
+				if base == r.typ {
+					name = "value"
+				} else {
+					runes := []rune(base)
+					runes[0] = unicode.ToLower(runes[0])
+					name = string(runes)
+				}
+			}
+		}
+		params = append(params, param{name: name, typ: r.typ})
+	}
+	return params
+}
+
+func zeroValue(typ string) string {
+	switch {
+	case typ == "string":
+		return `""`
+	case typ == "bool":
+		return "false"
+	case strings.HasPrefix(typ, "int") || strings.HasPrefix(typ, "uint") || strings.HasPrefix(typ, "float"):
+		return "0"
+	case typ == "error" || typ == "any" || strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map["):
+		return "nil"
+	default:
+		return "*new(" + typ + ")"
+	}
+}
@@ -0,0 +1,1955 @@
+// Code generated by mockgen from the auth and mgmt interfaces. DO NOT EDIT.
+
+package descopemock
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/mgmt"
+)
+
+var (
+	_ auth.MagicLink      = &MagicLink{}
+	_ auth.EnchantedLink  = &EnchantedLink{}
+	_ auth.Password       = &Password{}
+	_ auth.OTP            = &OTP{}
+	_ auth.TOTP           = &TOTP{}
+	_ auth.OAuth          = &OAuth{}
+	_ auth.SAML           = &SAML{}
+	_ auth.WebAuthn       = &WebAuthn{}
+	_ auth.Authentication = &Authentication{}
+	_ mgmt.Tenant         = &Tenant{}
+	_ mgmt.User           = &User{}
+	_ mgmt.SSO            = &SSO{}
+	_ mgmt.Management     = &Management{}
+)
+
+// MagicLink - a mock of auth.MagicLink, see the package documentation
+type MagicLink struct {
+	Recorder
+
+	// SignInFunc (optional, nil) - called by SignIn once its queued responses are used
+	SignInFunc func(method auth.DeliveryMethod, identifier string, URI string, r *http.Request, loginOptions *auth.LoginOptions) error
+	// SignUpFunc (optional, nil) - called by SignUp once its queued responses are used
+	SignUpFunc func(method auth.DeliveryMethod, identifier string, URI string, user *auth.User) error
+	// SignUpOrInFunc (optional, nil) - called by SignUpOrIn once its queued responses are used
+	SignUpOrInFunc func(method auth.DeliveryMethod, identifier string, URI string) error
+	// SignInCrossDeviceFunc (optional, nil) - called by SignInCrossDevice once its queued responses are used
+	SignInCrossDeviceFunc func(method auth.DeliveryMethod, identifier string, URI string, r *http.Request, loginOptions *auth.LoginOptions) (*auth.MagicLinkResponse, error)
+	// SignUpCrossDeviceFunc (optional, nil) - called by SignUpCrossDevice once its queued responses are used
+	SignUpCrossDeviceFunc func(method auth.DeliveryMethod, identifier string, URI string, user *auth.User) (*auth.MagicLinkResponse, error)
+	// SignUpOrInCrossDeviceFunc (optional, nil) - called by SignUpOrInCrossDevice once its queued responses are used
+	SignUpOrInCrossDeviceFunc func(method auth.DeliveryMethod, identifier string, URI string) (*auth.MagicLinkResponse, error)
+	// GetSessionFunc (optional, nil) - called by GetSession once its queued responses are used
+	GetSessionFunc func(pendingRef string, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// WaitForSessionFunc (optional, nil) - called by WaitForSession once its queued responses are used
+	WaitForSessionFunc func(ctx context.Context, pendingRef string, opts *auth.WaitForSessionOptions) (*auth.AuthenticationInfo, error)
+	// VerifyFunc (optional, nil) - called by Verify once its queued responses are used
+	VerifyFunc func(token string, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// UpdateUserEmailFunc (optional, nil) - called by UpdateUserEmail once its queued responses are used
+	UpdateUserEmailFunc func(identifier string, email string, URI string, request *http.Request) error
+	// UpdateUserEmailCrossDeviceFunc (optional, nil) - called by UpdateUserEmailCrossDevice once its queued responses are used
+	UpdateUserEmailCrossDeviceFunc func(identifier string, email string, URI string, request *http.Request) (*auth.MagicLinkResponse, error)
+	// UpdateUserPhoneFunc (optional, nil) - called by UpdateUserPhone once its queued responses are used
+	UpdateUserPhoneFunc func(method auth.DeliveryMethod, identifier string, phone string, URI string, request *http.Request) error
+	// UpdateUserPhoneCrossDeviceFunc (optional, nil) - called by UpdateUserPhoneCrossDevice once its queued responses are used
+	UpdateUserPhoneCrossDeviceFunc func(method auth.DeliveryMethod, identifier string, phone string, URI string, request *http.Request) (*auth.MagicLinkResponse, error)
+}
+
+// NewMagicLink - creates a MagicLink mock
+func NewMagicLink() *MagicLink {
+	return &MagicLink{}
+}
+
+// SignIn - records the call and returns the next queued response
+func (m *MagicLink) SignIn(method auth.DeliveryMethod, identifier string, URI string, r *http.Request, loginOptions *auth.LoginOptions) error {
+	m.record("SignIn", method, identifier, URI, r, loginOptions)
+	if res, ok := m.dequeue("SignIn"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.SignInFunc != nil {
+		return m.SignInFunc(method, identifier, URI, r, loginOptions)
+	}
+	return nil
+}
+
+// QueueSignIn - queues a response to be returned by a call of SignIn
+func (m *MagicLink) QueueSignIn(err error) {
+	m.enqueue("SignIn", err)
+}
+
+// SignUp - records the call and returns the next queued response
+func (m *MagicLink) SignUp(method auth.DeliveryMethod, identifier string, URI string, user *auth.User) error {
+	m.record("SignUp", method, identifier, URI, user)
+	if res, ok := m.dequeue("SignUp"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.SignUpFunc != nil {
+		return m.SignUpFunc(method, identifier, URI, user)
+	}
+	return nil
+}
+
+// QueueSignUp - queues a response to be returned by a call of SignUp
+func (m *MagicLink) QueueSignUp(err error) {
+	m.enqueue("SignUp", err)
+}
+
+// SignUpOrIn - records the call and returns the next queued response
+func (m *MagicLink) SignUpOrIn(method auth.DeliveryMethod, identifier string, URI string) error {
+	m.record("SignUpOrIn", method, identifier, URI)
+	if res, ok := m.dequeue("SignUpOrIn"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.SignUpOrInFunc != nil {
+		return m.SignUpOrInFunc(method, identifier, URI)
+	}
+	return nil
+}
+
+// QueueSignUpOrIn - queues a response to be returned by a call of SignUpOrIn
+func (m *MagicLink) QueueSignUpOrIn(err error) {
+	m.enqueue("SignUpOrIn", err)
+}
+
+// SignInCrossDevice - records the call and returns the next queued response
+func (m *MagicLink) SignInCrossDevice(method auth.DeliveryMethod, identifier string, URI string, r *http.Request, loginOptions *auth.LoginOptions) (*auth.MagicLinkResponse, error) {
+	m.record("SignInCrossDevice", method, identifier, URI, r, loginOptions)
+	if res, ok := m.dequeue("SignInCrossDevice"); ok {
+		r0, _ := res[0].(*auth.MagicLinkResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignInCrossDeviceFunc != nil {
+		return m.SignInCrossDeviceFunc(method, identifier, URI, r, loginOptions)
+	}
+	return nil, nil
+}
+
+// QueueSignInCrossDevice - queues a response to be returned by a call of SignInCrossDevice
+func (m *MagicLink) QueueSignInCrossDevice(magicLinkResponse *auth.MagicLinkResponse, err error) {
+	m.enqueue("SignInCrossDevice", magicLinkResponse, err)
+}
+
+// SignUpCrossDevice - records the call and returns the next queued response
+func (m *MagicLink) SignUpCrossDevice(method auth.DeliveryMethod, identifier string, URI string, user *auth.User) (*auth.MagicLinkResponse, error) {
+	m.record("SignUpCrossDevice", method, identifier, URI, user)
+	if res, ok := m.dequeue("SignUpCrossDevice"); ok {
+		r0, _ := res[0].(*auth.MagicLinkResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpCrossDeviceFunc != nil {
+		return m.SignUpCrossDeviceFunc(method, identifier, URI, user)
+	}
+	return nil, nil
+}
+
+// QueueSignUpCrossDevice - queues a response to be returned by a call of SignUpCrossDevice
+func (m *MagicLink) QueueSignUpCrossDevice(magicLinkResponse *auth.MagicLinkResponse, err error) {
+	m.enqueue("SignUpCrossDevice", magicLinkResponse, err)
+}
+
+// SignUpOrInCrossDevice - records the call and returns the next queued response
+func (m *MagicLink) SignUpOrInCrossDevice(method auth.DeliveryMethod, identifier string, URI string) (*auth.MagicLinkResponse, error) {
+	m.record("SignUpOrInCrossDevice", method, identifier, URI)
+	if res, ok := m.dequeue("SignUpOrInCrossDevice"); ok {
+		r0, _ := res[0].(*auth.MagicLinkResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpOrInCrossDeviceFunc != nil {
+		return m.SignUpOrInCrossDeviceFunc(method, identifier, URI)
+	}
+	return nil, nil
+}
+
+// QueueSignUpOrInCrossDevice - queues a response to be returned by a call of SignUpOrInCrossDevice
+func (m *MagicLink) QueueSignUpOrInCrossDevice(magicLinkResponse *auth.MagicLinkResponse, err error) {
+	m.enqueue("SignUpOrInCrossDevice", magicLinkResponse, err)
+}
+
+// GetSession - records the call and returns the next queued response
+func (m *MagicLink) GetSession(pendingRef string, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("GetSession", pendingRef, w)
+	if res, ok := m.dequeue("GetSession"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.GetSessionFunc != nil {
+		return m.GetSessionFunc(pendingRef, w)
+	}
+	return nil, nil
+}
+
+// QueueGetSession - queues a response to be returned by a call of GetSession
+func (m *MagicLink) QueueGetSession(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("GetSession", authenticationInfo, err)
+}
+
+// WaitForSession - records the call and returns the next queued response
+func (m *MagicLink) WaitForSession(ctx context.Context, pendingRef string, opts *auth.WaitForSessionOptions) (*auth.AuthenticationInfo, error) {
+	m.record("WaitForSession", ctx, pendingRef, opts)
+	if res, ok := m.dequeue("WaitForSession"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.WaitForSessionFunc != nil {
+		return m.WaitForSessionFunc(ctx, pendingRef, opts)
+	}
+	return nil, nil
+}
+
+// QueueWaitForSession - queues a response to be returned by a call of WaitForSession
+func (m *MagicLink) QueueWaitForSession(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("WaitForSession", authenticationInfo, err)
+}
+
+// Verify - records the call and returns the next queued response
+func (m *MagicLink) Verify(token string, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("Verify", token, w)
+	if res, ok := m.dequeue("Verify"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.VerifyFunc != nil {
+		return m.VerifyFunc(token, w)
+	}
+	return nil, nil
+}
+
+// QueueVerify - queues a response to be returned by a call of Verify
+func (m *MagicLink) QueueVerify(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("Verify", authenticationInfo, err)
+}
+
+// UpdateUserEmail - records the call and returns the next queued response
+func (m *MagicLink) UpdateUserEmail(identifier string, email string, URI string, request *http.Request) error {
+	m.record("UpdateUserEmail", identifier, email, URI, request)
+	if res, ok := m.dequeue("UpdateUserEmail"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.UpdateUserEmailFunc != nil {
+		return m.UpdateUserEmailFunc(identifier, email, URI, request)
+	}
+	return nil
+}
+
+// QueueUpdateUserEmail - queues a response to be returned by a call of UpdateUserEmail
+func (m *MagicLink) QueueUpdateUserEmail(err error) {
+	m.enqueue("UpdateUserEmail", err)
+}
+
+// UpdateUserEmailCrossDevice - records the call and returns the next queued response
+func (m *MagicLink) UpdateUserEmailCrossDevice(identifier string, email string, URI string, request *http.Request) (*auth.MagicLinkResponse, error) {
+	m.record("UpdateUserEmailCrossDevice", identifier, email, URI, request)
+	if res, ok := m.dequeue("UpdateUserEmailCrossDevice"); ok {
+		r0, _ := res[0].(*auth.MagicLinkResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.UpdateUserEmailCrossDeviceFunc != nil {
+		return m.UpdateUserEmailCrossDeviceFunc(identifier, email, URI, request)
+	}
+	return nil, nil
+}
+
+// QueueUpdateUserEmailCrossDevice - queues a response to be returned by a call of UpdateUserEmailCrossDevice
+func (m *MagicLink) QueueUpdateUserEmailCrossDevice(magicLinkResponse *auth.MagicLinkResponse, err error) {
+	m.enqueue("UpdateUserEmailCrossDevice", magicLinkResponse, err)
+}
+
+// UpdateUserPhone - records the call and returns the next queued response
+func (m *MagicLink) UpdateUserPhone(method auth.DeliveryMethod, identifier string, phone string, URI string, request *http.Request) error {
+	m.record("UpdateUserPhone", method, identifier, phone, URI, request)
+	if res, ok := m.dequeue("UpdateUserPhone"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.UpdateUserPhoneFunc != nil {
+		return m.UpdateUserPhoneFunc(method, identifier, phone, URI, request)
+	}
+	return nil
+}
+
+// QueueUpdateUserPhone - queues a response to be returned by a call of UpdateUserPhone
+func (m *MagicLink) QueueUpdateUserPhone(err error) {
+	m.enqueue("UpdateUserPhone", err)
+}
+
+// UpdateUserPhoneCrossDevice - records the call and returns the next queued response
+func (m *MagicLink) UpdateUserPhoneCrossDevice(method auth.DeliveryMethod, identifier string, phone string, URI string, request *http.Request) (*auth.MagicLinkResponse, error) {
+	m.record("UpdateUserPhoneCrossDevice", method, identifier, phone, URI, request)
+	if res, ok := m.dequeue("UpdateUserPhoneCrossDevice"); ok {
+		r0, _ := res[0].(*auth.MagicLinkResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.UpdateUserPhoneCrossDeviceFunc != nil {
+		return m.UpdateUserPhoneCrossDeviceFunc(method, identifier, phone, URI, request)
+	}
+	return nil, nil
+}
+
+// QueueUpdateUserPhoneCrossDevice - queues a response to be returned by a call of UpdateUserPhoneCrossDevice
+func (m *MagicLink) QueueUpdateUserPhoneCrossDevice(magicLinkResponse *auth.MagicLinkResponse, err error) {
+	m.enqueue("UpdateUserPhoneCrossDevice", magicLinkResponse, err)
+}
+
+// EnchantedLink - a mock of auth.EnchantedLink, see the package documentation
+type EnchantedLink struct {
+	Recorder
+
+	// SignInFunc (optional, nil) - called by SignIn once its queued responses are used
+	SignInFunc func(identifier string, URI string, r *http.Request, loginOptions *auth.LoginOptions) (*auth.EnchantedLinkResponse, error)
+	// SignUpFunc (optional, nil) - called by SignUp once its queued responses are used
+	SignUpFunc func(identifier string, URI string, user *auth.User) (*auth.EnchantedLinkResponse, error)
+	// SignUpOrInFunc (optional, nil) - called by SignUpOrIn once its queued responses are used
+	SignUpOrInFunc func(identifier string, URI string) (*auth.EnchantedLinkResponse, error)
+	// GetSessionFunc (optional, nil) - called by GetSession once its queued responses are used
+	GetSessionFunc func(pendingRef string, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// WaitForSessionFunc (optional, nil) - called by WaitForSession once its queued responses are used
+	WaitForSessionFunc func(ctx context.Context, pendingRef string, opts *auth.WaitForSessionOptions) (*auth.AuthenticationInfo, error)
+	// VerifyFunc (optional, nil) - called by Verify once its queued responses are used
+	VerifyFunc func(token string) error
+	// UpdateUserEmailFunc (optional, nil) - called by UpdateUserEmail once its queued responses are used
+	UpdateUserEmailFunc func(identifier string, email string, URI string, request *http.Request) (*auth.EnchantedLinkResponse, error)
+}
+
+// NewEnchantedLink - creates a EnchantedLink mock
+func NewEnchantedLink() *EnchantedLink {
+	return &EnchantedLink{}
+}
+
+// SignIn - records the call and returns the next queued response
+func (m *EnchantedLink) SignIn(identifier string, URI string, r *http.Request, loginOptions *auth.LoginOptions) (*auth.EnchantedLinkResponse, error) {
+	m.record("SignIn", identifier, URI, r, loginOptions)
+	if res, ok := m.dequeue("SignIn"); ok {
+		r0, _ := res[0].(*auth.EnchantedLinkResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignInFunc != nil {
+		return m.SignInFunc(identifier, URI, r, loginOptions)
+	}
+	return nil, nil
+}
+
+// QueueSignIn - queues a response to be returned by a call of SignIn
+func (m *EnchantedLink) QueueSignIn(enchantedLinkResponse *auth.EnchantedLinkResponse, err error) {
+	m.enqueue("SignIn", enchantedLinkResponse, err)
+}
+
+// SignUp - records the call and returns the next queued response
+func (m *EnchantedLink) SignUp(identifier string, URI string, user *auth.User) (*auth.EnchantedLinkResponse, error) {
+	m.record("SignUp", identifier, URI, user)
+	if res, ok := m.dequeue("SignUp"); ok {
+		r0, _ := res[0].(*auth.EnchantedLinkResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpFunc != nil {
+		return m.SignUpFunc(identifier, URI, user)
+	}
+	return nil, nil
+}
+
+// QueueSignUp - queues a response to be returned by a call of SignUp
+func (m *EnchantedLink) QueueSignUp(enchantedLinkResponse *auth.EnchantedLinkResponse, err error) {
+	m.enqueue("SignUp", enchantedLinkResponse, err)
+}
+
+// SignUpOrIn - records the call and returns the next queued response
+func (m *EnchantedLink) SignUpOrIn(identifier string, URI string) (*auth.EnchantedLinkResponse, error) {
+	m.record("SignUpOrIn", identifier, URI)
+	if res, ok := m.dequeue("SignUpOrIn"); ok {
+		r0, _ := res[0].(*auth.EnchantedLinkResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpOrInFunc != nil {
+		return m.SignUpOrInFunc(identifier, URI)
+	}
+	return nil, nil
+}
+
+// QueueSignUpOrIn - queues a response to be returned by a call of SignUpOrIn
+func (m *EnchantedLink) QueueSignUpOrIn(enchantedLinkResponse *auth.EnchantedLinkResponse, err error) {
+	m.enqueue("SignUpOrIn", enchantedLinkResponse, err)
+}
+
+// GetSession - records the call and returns the next queued response
+func (m *EnchantedLink) GetSession(pendingRef string, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("GetSession", pendingRef, w)
+	if res, ok := m.dequeue("GetSession"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.GetSessionFunc != nil {
+		return m.GetSessionFunc(pendingRef, w)
+	}
+	return nil, nil
+}
+
+// QueueGetSession - queues a response to be returned by a call of GetSession
+func (m *EnchantedLink) QueueGetSession(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("GetSession", authenticationInfo, err)
+}
+
+// WaitForSession - records the call and returns the next queued response
+func (m *EnchantedLink) WaitForSession(ctx context.Context, pendingRef string, opts *auth.WaitForSessionOptions) (*auth.AuthenticationInfo, error) {
+	m.record("WaitForSession", ctx, pendingRef, opts)
+	if res, ok := m.dequeue("WaitForSession"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.WaitForSessionFunc != nil {
+		return m.WaitForSessionFunc(ctx, pendingRef, opts)
+	}
+	return nil, nil
+}
+
+// QueueWaitForSession - queues a response to be returned by a call of WaitForSession
+func (m *EnchantedLink) QueueWaitForSession(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("WaitForSession", authenticationInfo, err)
+}
+
+// Verify - records the call and returns the next queued response
+func (m *EnchantedLink) Verify(token string) error {
+	m.record("Verify", token)
+	if res, ok := m.dequeue("Verify"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.VerifyFunc != nil {
+		return m.VerifyFunc(token)
+	}
+	return nil
+}
+
+// QueueVerify - queues a response to be returned by a call of Verify
+func (m *EnchantedLink) QueueVerify(err error) {
+	m.enqueue("Verify", err)
+}
+
+// UpdateUserEmail - records the call and returns the next queued response
+func (m *EnchantedLink) UpdateUserEmail(identifier string, email string, URI string, request *http.Request) (*auth.EnchantedLinkResponse, error) {
+	m.record("UpdateUserEmail", identifier, email, URI, request)
+	if res, ok := m.dequeue("UpdateUserEmail"); ok {
+		r0, _ := res[0].(*auth.EnchantedLinkResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.UpdateUserEmailFunc != nil {
+		return m.UpdateUserEmailFunc(identifier, email, URI, request)
+	}
+	return nil, nil
+}
+
+// QueueUpdateUserEmail - queues a response to be returned by a call of UpdateUserEmail
+func (m *EnchantedLink) QueueUpdateUserEmail(enchantedLinkResponse *auth.EnchantedLinkResponse, err error) {
+	m.enqueue("UpdateUserEmail", enchantedLinkResponse, err)
+}
+
+// Password - a mock of auth.Password, see the package documentation
+type Password struct {
+	Recorder
+
+	// SignUpFunc (optional, nil) - called by SignUp once its queued responses are used
+	SignUpFunc func(identifier string, user *auth.User, password string, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// SignInFunc (optional, nil) - called by SignIn once its queued responses are used
+	SignInFunc func(identifier string, password string, r *http.Request, loginOptions *auth.LoginOptions, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// SendPasswordResetFunc (optional, nil) - called by SendPasswordReset once its queued responses are used
+	SendPasswordResetFunc func(identifier string, redirectURL string) error
+	// UpdateUserPasswordFunc (optional, nil) - called by UpdateUserPassword once its queued responses are used
+	UpdateUserPasswordFunc func(identifier string, newPassword string, request *http.Request) error
+	// ReplaceUserPasswordFunc (optional, nil) - called by ReplaceUserPassword once its queued responses are used
+	ReplaceUserPasswordFunc func(identifier string, oldPassword string, newPassword string, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// GetPasswordPolicyFunc (optional, nil) - called by GetPasswordPolicy once its queued responses are used
+	GetPasswordPolicyFunc func() (*auth.PasswordPolicy, error)
+}
+
+// NewPassword - creates a Password mock
+func NewPassword() *Password {
+	return &Password{}
+}
+
+// SignUp - records the call and returns the next queued response
+func (m *Password) SignUp(identifier string, user *auth.User, password string, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("SignUp", identifier, user, password, w)
+	if res, ok := m.dequeue("SignUp"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpFunc != nil {
+		return m.SignUpFunc(identifier, user, password, w)
+	}
+	return nil, nil
+}
+
+// QueueSignUp - queues a response to be returned by a call of SignUp
+func (m *Password) QueueSignUp(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("SignUp", authenticationInfo, err)
+}
+
+// SignIn - records the call and returns the next queued response
+func (m *Password) SignIn(identifier string, password string, r *http.Request, loginOptions *auth.LoginOptions, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("SignIn", identifier, password, r, loginOptions, w)
+	if res, ok := m.dequeue("SignIn"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignInFunc != nil {
+		return m.SignInFunc(identifier, password, r, loginOptions, w)
+	}
+	return nil, nil
+}
+
+// QueueSignIn - queues a response to be returned by a call of SignIn
+func (m *Password) QueueSignIn(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("SignIn", authenticationInfo, err)
+}
+
+// SendPasswordReset - records the call and returns the next queued response
+func (m *Password) SendPasswordReset(identifier string, redirectURL string) error {
+	m.record("SendPasswordReset", identifier, redirectURL)
+	if res, ok := m.dequeue("SendPasswordReset"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.SendPasswordResetFunc != nil {
+		return m.SendPasswordResetFunc(identifier, redirectURL)
+	}
+	return nil
+}
+
+// QueueSendPasswordReset - queues a response to be returned by a call of SendPasswordReset
+func (m *Password) QueueSendPasswordReset(err error) {
+	m.enqueue("SendPasswordReset", err)
+}
+
+// UpdateUserPassword - records the call and returns the next queued response
+func (m *Password) UpdateUserPassword(identifier string, newPassword string, request *http.Request) error {
+	m.record("UpdateUserPassword", identifier, newPassword, request)
+	if res, ok := m.dequeue("UpdateUserPassword"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.UpdateUserPasswordFunc != nil {
+		return m.UpdateUserPasswordFunc(identifier, newPassword, request)
+	}
+	return nil
+}
+
+// QueueUpdateUserPassword - queues a response to be returned by a call of UpdateUserPassword
+func (m *Password) QueueUpdateUserPassword(err error) {
+	m.enqueue("UpdateUserPassword", err)
+}
+
+// ReplaceUserPassword - records the call and returns the next queued response
+func (m *Password) ReplaceUserPassword(identifier string, oldPassword string, newPassword string, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("ReplaceUserPassword", identifier, oldPassword, newPassword, w)
+	if res, ok := m.dequeue("ReplaceUserPassword"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.ReplaceUserPasswordFunc != nil {
+		return m.ReplaceUserPasswordFunc(identifier, oldPassword, newPassword, w)
+	}
+	return nil, nil
+}
+
+// QueueReplaceUserPassword - queues a response to be returned by a call of ReplaceUserPassword
+func (m *Password) QueueReplaceUserPassword(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("ReplaceUserPassword", authenticationInfo, err)
+}
+
+// GetPasswordPolicy - records the call and returns the next queued response
+func (m *Password) GetPasswordPolicy() (*auth.PasswordPolicy, error) {
+	m.record("GetPasswordPolicy")
+	if res, ok := m.dequeue("GetPasswordPolicy"); ok {
+		r0, _ := res[0].(*auth.PasswordPolicy)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.GetPasswordPolicyFunc != nil {
+		return m.GetPasswordPolicyFunc()
+	}
+	return nil, nil
+}
+
+// QueueGetPasswordPolicy - queues a response to be returned by a call of GetPasswordPolicy
+func (m *Password) QueueGetPasswordPolicy(passwordPolicy *auth.PasswordPolicy, err error) {
+	m.enqueue("GetPasswordPolicy", passwordPolicy, err)
+}
+
+// OTP - a mock of auth.OTP, see the package documentation
+type OTP struct {
+	Recorder
+
+	// SignInFunc (optional, nil) - called by SignIn once its queued responses are used
+	SignInFunc func(method auth.DeliveryMethod, identifier string, r *http.Request, loginOptions *auth.LoginOptions) error
+	// SignUpFunc (optional, nil) - called by SignUp once its queued responses are used
+	SignUpFunc func(method auth.DeliveryMethod, identifier string, user *auth.User) error
+	// SignUpOrInFunc (optional, nil) - called by SignUpOrIn once its queued responses are used
+	SignUpOrInFunc func(method auth.DeliveryMethod, identifier string) error
+	// SignInEmbeddedFunc (optional, nil) - called by SignInEmbedded once its queued responses are used
+	SignInEmbeddedFunc func(identifier string, r *http.Request, loginOptions *auth.LoginOptions) (string, error)
+	// SignUpEmbeddedFunc (optional, nil) - called by SignUpEmbedded once its queued responses are used
+	SignUpEmbeddedFunc func(identifier string, user *auth.User) (string, error)
+	// SignUpOrInEmbeddedFunc (optional, nil) - called by SignUpOrInEmbedded once its queued responses are used
+	SignUpOrInEmbeddedFunc func(identifier string) (string, error)
+	// VerifyCodeFunc (optional, nil) - called by VerifyCode once its queued responses are used
+	VerifyCodeFunc func(method auth.DeliveryMethod, identifier string, code string, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// UpdateUserEmailFunc (optional, nil) - called by UpdateUserEmail once its queued responses are used
+	UpdateUserEmailFunc func(identifier string, email string, request *http.Request) error
+	// UpdateUserPhoneFunc (optional, nil) - called by UpdateUserPhone once its queued responses are used
+	UpdateUserPhoneFunc func(method auth.DeliveryMethod, identifier string, phone string, request *http.Request) error
+}
+
+// NewOTP - creates a OTP mock
+func NewOTP() *OTP {
+	return &OTP{}
+}
+
+// SignIn - records the call and returns the next queued response
+func (m *OTP) SignIn(method auth.DeliveryMethod, identifier string, r *http.Request, loginOptions *auth.LoginOptions) error {
+	m.record("SignIn", method, identifier, r, loginOptions)
+	if res, ok := m.dequeue("SignIn"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.SignInFunc != nil {
+		return m.SignInFunc(method, identifier, r, loginOptions)
+	}
+	return nil
+}
+
+// QueueSignIn - queues a response to be returned by a call of SignIn
+func (m *OTP) QueueSignIn(err error) {
+	m.enqueue("SignIn", err)
+}
+
+// SignUp - records the call and returns the next queued response
+func (m *OTP) SignUp(method auth.DeliveryMethod, identifier string, user *auth.User) error {
+	m.record("SignUp", method, identifier, user)
+	if res, ok := m.dequeue("SignUp"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.SignUpFunc != nil {
+		return m.SignUpFunc(method, identifier, user)
+	}
+	return nil
+}
+
+// QueueSignUp - queues a response to be returned by a call of SignUp
+func (m *OTP) QueueSignUp(err error) {
+	m.enqueue("SignUp", err)
+}
+
+// SignUpOrIn - records the call and returns the next queued response
+func (m *OTP) SignUpOrIn(method auth.DeliveryMethod, identifier string) error {
+	m.record("SignUpOrIn", method, identifier)
+	if res, ok := m.dequeue("SignUpOrIn"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.SignUpOrInFunc != nil {
+		return m.SignUpOrInFunc(method, identifier)
+	}
+	return nil
+}
+
+// QueueSignUpOrIn - queues a response to be returned by a call of SignUpOrIn
+func (m *OTP) QueueSignUpOrIn(err error) {
+	m.enqueue("SignUpOrIn", err)
+}
+
+// SignInEmbedded - records the call and returns the next queued response
+func (m *OTP) SignInEmbedded(identifier string, r *http.Request, loginOptions *auth.LoginOptions) (string, error) {
+	m.record("SignInEmbedded", identifier, r, loginOptions)
+	if res, ok := m.dequeue("SignInEmbedded"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignInEmbeddedFunc != nil {
+		return m.SignInEmbeddedFunc(identifier, r, loginOptions)
+	}
+	return "", nil
+}
+
+// QueueSignInEmbedded - queues a response to be returned by a call of SignInEmbedded
+func (m *OTP) QueueSignInEmbedded(code string, err error) {
+	m.enqueue("SignInEmbedded", code, err)
+}
+
+// SignUpEmbedded - records the call and returns the next queued response
+func (m *OTP) SignUpEmbedded(identifier string, user *auth.User) (string, error) {
+	m.record("SignUpEmbedded", identifier, user)
+	if res, ok := m.dequeue("SignUpEmbedded"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpEmbeddedFunc != nil {
+		return m.SignUpEmbeddedFunc(identifier, user)
+	}
+	return "", nil
+}
+
+// QueueSignUpEmbedded - queues a response to be returned by a call of SignUpEmbedded
+func (m *OTP) QueueSignUpEmbedded(code string, err error) {
+	m.enqueue("SignUpEmbedded", code, err)
+}
+
+// SignUpOrInEmbedded - records the call and returns the next queued response
+func (m *OTP) SignUpOrInEmbedded(identifier string) (string, error) {
+	m.record("SignUpOrInEmbedded", identifier)
+	if res, ok := m.dequeue("SignUpOrInEmbedded"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpOrInEmbeddedFunc != nil {
+		return m.SignUpOrInEmbeddedFunc(identifier)
+	}
+	return "", nil
+}
+
+// QueueSignUpOrInEmbedded - queues a response to be returned by a call of SignUpOrInEmbedded
+func (m *OTP) QueueSignUpOrInEmbedded(code string, err error) {
+	m.enqueue("SignUpOrInEmbedded", code, err)
+}
+
+// VerifyCode - records the call and returns the next queued response
+func (m *OTP) VerifyCode(method auth.DeliveryMethod, identifier string, code string, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("VerifyCode", method, identifier, code, w)
+	if res, ok := m.dequeue("VerifyCode"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.VerifyCodeFunc != nil {
+		return m.VerifyCodeFunc(method, identifier, code, w)
+	}
+	return nil, nil
+}
+
+// QueueVerifyCode - queues a response to be returned by a call of VerifyCode
+func (m *OTP) QueueVerifyCode(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("VerifyCode", authenticationInfo, err)
+}
+
+// UpdateUserEmail - records the call and returns the next queued response
+func (m *OTP) UpdateUserEmail(identifier string, email string, request *http.Request) error {
+	m.record("UpdateUserEmail", identifier, email, request)
+	if res, ok := m.dequeue("UpdateUserEmail"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.UpdateUserEmailFunc != nil {
+		return m.UpdateUserEmailFunc(identifier, email, request)
+	}
+	return nil
+}
+
+// QueueUpdateUserEmail - queues a response to be returned by a call of UpdateUserEmail
+func (m *OTP) QueueUpdateUserEmail(err error) {
+	m.enqueue("UpdateUserEmail", err)
+}
+
+// UpdateUserPhone - records the call and returns the next queued response
+func (m *OTP) UpdateUserPhone(method auth.DeliveryMethod, identifier string, phone string, request *http.Request) error {
+	m.record("UpdateUserPhone", method, identifier, phone, request)
+	if res, ok := m.dequeue("UpdateUserPhone"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.UpdateUserPhoneFunc != nil {
+		return m.UpdateUserPhoneFunc(method, identifier, phone, request)
+	}
+	return nil
+}
+
+// QueueUpdateUserPhone - queues a response to be returned by a call of UpdateUserPhone
+func (m *OTP) QueueUpdateUserPhone(err error) {
+	m.enqueue("UpdateUserPhone", err)
+}
+
+// TOTP - a mock of auth.TOTP, see the package documentation
+type TOTP struct {
+	Recorder
+
+	// SignUpFunc (optional, nil) - called by SignUp once its queued responses are used
+	SignUpFunc func(identifier string, user *auth.User) (*auth.TOTPResponse, error)
+	// SignInCodeFunc (optional, nil) - called by SignInCode once its queued responses are used
+	SignInCodeFunc func(identifier string, code string, r *http.Request, loginOptions *auth.LoginOptions, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// UpdateUserFunc (optional, nil) - called by UpdateUser once its queued responses are used
+	UpdateUserFunc func(identifier string, request *http.Request) (*auth.TOTPResponse, error)
+}
+
+// NewTOTP - creates a TOTP mock
+func NewTOTP() *TOTP {
+	return &TOTP{}
+}
+
+// SignUp - records the call and returns the next queued response
+func (m *TOTP) SignUp(identifier string, user *auth.User) (*auth.TOTPResponse, error) {
+	m.record("SignUp", identifier, user)
+	if res, ok := m.dequeue("SignUp"); ok {
+		r0, _ := res[0].(*auth.TOTPResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpFunc != nil {
+		return m.SignUpFunc(identifier, user)
+	}
+	return nil, nil
+}
+
+// QueueSignUp - queues a response to be returned by a call of SignUp
+func (m *TOTP) QueueSignUp(tOTPResponse *auth.TOTPResponse, err error) {
+	m.enqueue("SignUp", tOTPResponse, err)
+}
+
+// SignInCode - records the call and returns the next queued response
+func (m *TOTP) SignInCode(identifier string, code string, r *http.Request, loginOptions *auth.LoginOptions, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("SignInCode", identifier, code, r, loginOptions, w)
+	if res, ok := m.dequeue("SignInCode"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignInCodeFunc != nil {
+		return m.SignInCodeFunc(identifier, code, r, loginOptions, w)
+	}
+	return nil, nil
+}
+
+// QueueSignInCode - queues a response to be returned by a call of SignInCode
+func (m *TOTP) QueueSignInCode(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("SignInCode", authenticationInfo, err)
+}
+
+// UpdateUser - records the call and returns the next queued response
+func (m *TOTP) UpdateUser(identifier string, request *http.Request) (*auth.TOTPResponse, error) {
+	m.record("UpdateUser", identifier, request)
+	if res, ok := m.dequeue("UpdateUser"); ok {
+		r0, _ := res[0].(*auth.TOTPResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.UpdateUserFunc != nil {
+		return m.UpdateUserFunc(identifier, request)
+	}
+	return nil, nil
+}
+
+// QueueUpdateUser - queues a response to be returned by a call of UpdateUser
+func (m *TOTP) QueueUpdateUser(tOTPResponse *auth.TOTPResponse, err error) {
+	m.enqueue("UpdateUser", tOTPResponse, err)
+}
+
+// OAuth - a mock of auth.OAuth, see the package documentation
+type OAuth struct {
+	Recorder
+
+	// StartFunc (optional, nil) - called by Start once its queued responses are used
+	StartFunc func(provider auth.OAuthProvider, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, w http.ResponseWriter) (string, error)
+	// StartWithOptionsFunc (optional, nil) - called by StartWithOptions once its queued responses are used
+	StartWithOptionsFunc func(provider auth.OAuthProvider, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, oauthOptions *auth.OAuthOptions, w http.ResponseWriter) (string, error)
+	// ExchangeTokenFunc (optional, nil) - called by ExchangeToken once its queued responses are used
+	ExchangeTokenFunc func(code string, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// ExchangeTokenFromRequestFunc (optional, nil) - called by ExchangeTokenFromRequest once its queued responses are used
+	ExchangeTokenFromRequestFunc func(r *http.Request, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+}
+
+// NewOAuth - creates a OAuth mock
+func NewOAuth() *OAuth {
+	return &OAuth{}
+}
+
+// Start - records the call and returns the next queued response
+func (m *OAuth) Start(provider auth.OAuthProvider, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, w http.ResponseWriter) (string, error) {
+	m.record("Start", provider, returnURL, r, loginOptions, w)
+	if res, ok := m.dequeue("Start"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.StartFunc != nil {
+		return m.StartFunc(provider, returnURL, r, loginOptions, w)
+	}
+	return "", nil
+}
+
+// QueueStart - queues a response to be returned by a call of Start
+func (m *OAuth) QueueStart(value string, err error) {
+	m.enqueue("Start", value, err)
+}
+
+// StartWithOptions - records the call and returns the next queued response
+func (m *OAuth) StartWithOptions(provider auth.OAuthProvider, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, oauthOptions *auth.OAuthOptions, w http.ResponseWriter) (string, error) {
+	m.record("StartWithOptions", provider, returnURL, r, loginOptions, oauthOptions, w)
+	if res, ok := m.dequeue("StartWithOptions"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.StartWithOptionsFunc != nil {
+		return m.StartWithOptionsFunc(provider, returnURL, r, loginOptions, oauthOptions, w)
+	}
+	return "", nil
+}
+
+// QueueStartWithOptions - queues a response to be returned by a call of StartWithOptions
+func (m *OAuth) QueueStartWithOptions(value string, err error) {
+	m.enqueue("StartWithOptions", value, err)
+}
+
+// ExchangeToken - records the call and returns the next queued response
+func (m *OAuth) ExchangeToken(code string, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("ExchangeToken", code, w)
+	if res, ok := m.dequeue("ExchangeToken"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.ExchangeTokenFunc != nil {
+		return m.ExchangeTokenFunc(code, w)
+	}
+	return nil, nil
+}
+
+// QueueExchangeToken - queues a response to be returned by a call of ExchangeToken
+func (m *OAuth) QueueExchangeToken(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("ExchangeToken", authenticationInfo, err)
+}
+
+// ExchangeTokenFromRequest - records the call and returns the next queued response
+func (m *OAuth) ExchangeTokenFromRequest(r *http.Request, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("ExchangeTokenFromRequest", r, w)
+	if res, ok := m.dequeue("ExchangeTokenFromRequest"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.ExchangeTokenFromRequestFunc != nil {
+		return m.ExchangeTokenFromRequestFunc(r, w)
+	}
+	return nil, nil
+}
+
+// QueueExchangeTokenFromRequest - queues a response to be returned by a call of ExchangeTokenFromRequest
+func (m *OAuth) QueueExchangeTokenFromRequest(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("ExchangeTokenFromRequest", authenticationInfo, err)
+}
+
+// SAML - a mock of auth.SAML, see the package documentation
+type SAML struct {
+	Recorder
+
+	// StartFunc (optional, nil) - called by Start once its queued responses are used
+	StartFunc func(tenant string, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, w http.ResponseWriter) (string, error)
+	// StartWithOptionsFunc (optional, nil) - called by StartWithOptions once its queued responses are used
+	StartWithOptionsFunc func(tenant string, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, samlOptions *auth.SAMLOptions, w http.ResponseWriter) (string, error)
+	// StartWithEmailFunc (optional, nil) - called by StartWithEmail once its queued responses are used
+	StartWithEmailFunc func(emailOrDomain string, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, samlOptions *auth.SAMLOptions, w http.ResponseWriter) (string, error)
+	// ExchangeTokenFunc (optional, nil) - called by ExchangeToken once its queued responses are used
+	ExchangeTokenFunc func(code string, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// ExchangeTokenFromRequestFunc (optional, nil) - called by ExchangeTokenFromRequest once its queued responses are used
+	ExchangeTokenFromRequestFunc func(r *http.Request, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+}
+
+// NewSAML - creates a SAML mock
+func NewSAML() *SAML {
+	return &SAML{}
+}
+
+// Start - records the call and returns the next queued response
+func (m *SAML) Start(tenant string, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, w http.ResponseWriter) (string, error) {
+	m.record("Start", tenant, returnURL, r, loginOptions, w)
+	if res, ok := m.dequeue("Start"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.StartFunc != nil {
+		return m.StartFunc(tenant, returnURL, r, loginOptions, w)
+	}
+	return "", nil
+}
+
+// QueueStart - queues a response to be returned by a call of Start
+func (m *SAML) QueueStart(redirectURL string, err error) {
+	m.enqueue("Start", redirectURL, err)
+}
+
+// StartWithOptions - records the call and returns the next queued response
+func (m *SAML) StartWithOptions(tenant string, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, samlOptions *auth.SAMLOptions, w http.ResponseWriter) (string, error) {
+	m.record("StartWithOptions", tenant, returnURL, r, loginOptions, samlOptions, w)
+	if res, ok := m.dequeue("StartWithOptions"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.StartWithOptionsFunc != nil {
+		return m.StartWithOptionsFunc(tenant, returnURL, r, loginOptions, samlOptions, w)
+	}
+	return "", nil
+}
+
+// QueueStartWithOptions - queues a response to be returned by a call of StartWithOptions
+func (m *SAML) QueueStartWithOptions(redirectURL string, err error) {
+	m.enqueue("StartWithOptions", redirectURL, err)
+}
+
+// StartWithEmail - records the call and returns the next queued response
+func (m *SAML) StartWithEmail(emailOrDomain string, returnURL string, r *http.Request, loginOptions *auth.LoginOptions, samlOptions *auth.SAMLOptions, w http.ResponseWriter) (string, error) {
+	m.record("StartWithEmail", emailOrDomain, returnURL, r, loginOptions, samlOptions, w)
+	if res, ok := m.dequeue("StartWithEmail"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.StartWithEmailFunc != nil {
+		return m.StartWithEmailFunc(emailOrDomain, returnURL, r, loginOptions, samlOptions, w)
+	}
+	return "", nil
+}
+
+// QueueStartWithEmail - queues a response to be returned by a call of StartWithEmail
+func (m *SAML) QueueStartWithEmail(redirectURL string, err error) {
+	m.enqueue("StartWithEmail", redirectURL, err)
+}
+
+// ExchangeToken - records the call and returns the next queued response
+func (m *SAML) ExchangeToken(code string, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("ExchangeToken", code, w)
+	if res, ok := m.dequeue("ExchangeToken"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.ExchangeTokenFunc != nil {
+		return m.ExchangeTokenFunc(code, w)
+	}
+	return nil, nil
+}
+
+// QueueExchangeToken - queues a response to be returned by a call of ExchangeToken
+func (m *SAML) QueueExchangeToken(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("ExchangeToken", authenticationInfo, err)
+}
+
+// ExchangeTokenFromRequest - records the call and returns the next queued response
+func (m *SAML) ExchangeTokenFromRequest(r *http.Request, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("ExchangeTokenFromRequest", r, w)
+	if res, ok := m.dequeue("ExchangeTokenFromRequest"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.ExchangeTokenFromRequestFunc != nil {
+		return m.ExchangeTokenFromRequestFunc(r, w)
+	}
+	return nil, nil
+}
+
+// QueueExchangeTokenFromRequest - queues a response to be returned by a call of ExchangeTokenFromRequest
+func (m *SAML) QueueExchangeTokenFromRequest(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("ExchangeTokenFromRequest", authenticationInfo, err)
+}
+
+// WebAuthn - a mock of auth.WebAuthn, see the package documentation
+type WebAuthn struct {
+	Recorder
+
+	// SignUpStartFunc (optional, nil) - called by SignUpStart once its queued responses are used
+	SignUpStartFunc func(identifier string, user *auth.User, origin string) (*auth.WebAuthnTransactionResponse, error)
+	// SignUpFinishFunc (optional, nil) - called by SignUpFinish once its queued responses are used
+	SignUpFinishFunc func(finishRequest *auth.WebAuthnFinishRequest, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// SignInStartFunc (optional, nil) - called by SignInStart once its queued responses are used
+	SignInStartFunc func(identifier string, origin string, r *http.Request, loginOptions *auth.LoginOptions) (*auth.WebAuthnTransactionResponse, error)
+	// SignInFinishFunc (optional, nil) - called by SignInFinish once its queued responses are used
+	SignInFinishFunc func(finishRequest *auth.WebAuthnFinishRequest, w http.ResponseWriter) (*auth.AuthenticationInfo, error)
+	// SignUpOrInStartFunc (optional, nil) - called by SignUpOrInStart once its queued responses are used
+	SignUpOrInStartFunc func(identifier string, origin string) (*auth.WebAuthnTransactionResponse, error)
+	// UpdateUserDeviceStartFunc (optional, nil) - called by UpdateUserDeviceStart once its queued responses are used
+	UpdateUserDeviceStartFunc func(identifier string, origin string, request *http.Request) (*auth.WebAuthnTransactionResponse, error)
+	// UpdateUserDeviceFinishFunc (optional, nil) - called by UpdateUserDeviceFinish once its queued responses are used
+	UpdateUserDeviceFinishFunc func(finishRequest *auth.WebAuthnFinishRequest) error
+}
+
+// NewWebAuthn - creates a WebAuthn mock
+func NewWebAuthn() *WebAuthn {
+	return &WebAuthn{}
+}
+
+// SignUpStart - records the call and returns the next queued response
+func (m *WebAuthn) SignUpStart(identifier string, user *auth.User, origin string) (*auth.WebAuthnTransactionResponse, error) {
+	m.record("SignUpStart", identifier, user, origin)
+	if res, ok := m.dequeue("SignUpStart"); ok {
+		r0, _ := res[0].(*auth.WebAuthnTransactionResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpStartFunc != nil {
+		return m.SignUpStartFunc(identifier, user, origin)
+	}
+	return nil, nil
+}
+
+// QueueSignUpStart - queues a response to be returned by a call of SignUpStart
+func (m *WebAuthn) QueueSignUpStart(webAuthnTransactionResponse *auth.WebAuthnTransactionResponse, err error) {
+	m.enqueue("SignUpStart", webAuthnTransactionResponse, err)
+}
+
+// SignUpFinish - records the call and returns the next queued response
+func (m *WebAuthn) SignUpFinish(finishRequest *auth.WebAuthnFinishRequest, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("SignUpFinish", finishRequest, w)
+	if res, ok := m.dequeue("SignUpFinish"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpFinishFunc != nil {
+		return m.SignUpFinishFunc(finishRequest, w)
+	}
+	return nil, nil
+}
+
+// QueueSignUpFinish - queues a response to be returned by a call of SignUpFinish
+func (m *WebAuthn) QueueSignUpFinish(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("SignUpFinish", authenticationInfo, err)
+}
+
+// SignInStart - records the call and returns the next queued response
+func (m *WebAuthn) SignInStart(identifier string, origin string, r *http.Request, loginOptions *auth.LoginOptions) (*auth.WebAuthnTransactionResponse, error) {
+	m.record("SignInStart", identifier, origin, r, loginOptions)
+	if res, ok := m.dequeue("SignInStart"); ok {
+		r0, _ := res[0].(*auth.WebAuthnTransactionResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignInStartFunc != nil {
+		return m.SignInStartFunc(identifier, origin, r, loginOptions)
+	}
+	return nil, nil
+}
+
+// QueueSignInStart - queues a response to be returned by a call of SignInStart
+func (m *WebAuthn) QueueSignInStart(webAuthnTransactionResponse *auth.WebAuthnTransactionResponse, err error) {
+	m.enqueue("SignInStart", webAuthnTransactionResponse, err)
+}
+
+// SignInFinish - records the call and returns the next queued response
+func (m *WebAuthn) SignInFinish(finishRequest *auth.WebAuthnFinishRequest, w http.ResponseWriter) (*auth.AuthenticationInfo, error) {
+	m.record("SignInFinish", finishRequest, w)
+	if res, ok := m.dequeue("SignInFinish"); ok {
+		r0, _ := res[0].(*auth.AuthenticationInfo)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignInFinishFunc != nil {
+		return m.SignInFinishFunc(finishRequest, w)
+	}
+	return nil, nil
+}
+
+// QueueSignInFinish - queues a response to be returned by a call of SignInFinish
+func (m *WebAuthn) QueueSignInFinish(authenticationInfo *auth.AuthenticationInfo, err error) {
+	m.enqueue("SignInFinish", authenticationInfo, err)
+}
+
+// SignUpOrInStart - records the call and returns the next queued response
+func (m *WebAuthn) SignUpOrInStart(identifier string, origin string) (*auth.WebAuthnTransactionResponse, error) {
+	m.record("SignUpOrInStart", identifier, origin)
+	if res, ok := m.dequeue("SignUpOrInStart"); ok {
+		r0, _ := res[0].(*auth.WebAuthnTransactionResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.SignUpOrInStartFunc != nil {
+		return m.SignUpOrInStartFunc(identifier, origin)
+	}
+	return nil, nil
+}
+
+// QueueSignUpOrInStart - queues a response to be returned by a call of SignUpOrInStart
+func (m *WebAuthn) QueueSignUpOrInStart(webAuthnTransactionResponse *auth.WebAuthnTransactionResponse, err error) {
+	m.enqueue("SignUpOrInStart", webAuthnTransactionResponse, err)
+}
+
+// UpdateUserDeviceStart - records the call and returns the next queued response
+func (m *WebAuthn) UpdateUserDeviceStart(identifier string, origin string, request *http.Request) (*auth.WebAuthnTransactionResponse, error) {
+	m.record("UpdateUserDeviceStart", identifier, origin, request)
+	if res, ok := m.dequeue("UpdateUserDeviceStart"); ok {
+		r0, _ := res[0].(*auth.WebAuthnTransactionResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.UpdateUserDeviceStartFunc != nil {
+		return m.UpdateUserDeviceStartFunc(identifier, origin, request)
+	}
+	return nil, nil
+}
+
+// QueueUpdateUserDeviceStart - queues a response to be returned by a call of UpdateUserDeviceStart
+func (m *WebAuthn) QueueUpdateUserDeviceStart(webAuthnTransactionResponse *auth.WebAuthnTransactionResponse, err error) {
+	m.enqueue("UpdateUserDeviceStart", webAuthnTransactionResponse, err)
+}
+
+// UpdateUserDeviceFinish - records the call and returns the next queued response
+func (m *WebAuthn) UpdateUserDeviceFinish(finishRequest *auth.WebAuthnFinishRequest) error {
+	m.record("UpdateUserDeviceFinish", finishRequest)
+	if res, ok := m.dequeue("UpdateUserDeviceFinish"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.UpdateUserDeviceFinishFunc != nil {
+		return m.UpdateUserDeviceFinishFunc(finishRequest)
+	}
+	return nil
+}
+
+// QueueUpdateUserDeviceFinish - queues a response to be returned by a call of UpdateUserDeviceFinish
+func (m *WebAuthn) QueueUpdateUserDeviceFinish(err error) {
+	m.enqueue("UpdateUserDeviceFinish", err)
+}
+
+// Authentication - a mock of auth.Authentication, see the package documentation
+type Authentication struct {
+	Recorder
+
+	// MagicLinkMock - returned by MagicLink, set by NewAuthentication
+	MagicLinkMock *MagicLink
+	// EnchantedLinkMock - returned by EnchantedLink, set by NewAuthentication
+	EnchantedLinkMock *EnchantedLink
+	// PasswordMock - returned by Password, set by NewAuthentication
+	PasswordMock *Password
+	// OTPMock - returned by OTP, set by NewAuthentication
+	OTPMock *OTP
+	// TOTPMock - returned by TOTP, set by NewAuthentication
+	TOTPMock *TOTP
+	// OAuthMock - returned by OAuth, set by NewAuthentication
+	OAuthMock *OAuth
+	// SAMLMock - returned by SAML, set by NewAuthentication
+	SAMLMock *SAML
+	// WebAuthnMock - returned by WebAuthn, set by NewAuthentication
+	WebAuthnMock *WebAuthn
+	// ValidateSessionFunc (optional, nil) - called by ValidateSession once its queued responses are used
+	ValidateSessionFunc func(request *http.Request, w http.ResponseWriter) (bool, *auth.Token, error)
+	// ValidateSessionTokensFunc (optional, nil) - called by ValidateSessionTokens once its queued responses are used
+	ValidateSessionTokensFunc func(sessionToken string, refreshToken string) (bool, *auth.Token, error)
+	// RefreshSessionFunc (optional, nil) - called by RefreshSession once its queued responses are used
+	RefreshSessionFunc func(request *http.Request, w http.ResponseWriter) (bool, *auth.Token, error)
+	// ExchangeAccessKeyFunc (optional, nil) - called by ExchangeAccessKey once its queued responses are used
+	ExchangeAccessKeyFunc func(accessKey string) (bool, *auth.Token, error)
+	// ValidatePermissionsFunc (optional, nil) - called by ValidatePermissions once its queued responses are used
+	ValidatePermissionsFunc func(token *auth.Token, permissions []string) bool
+	// ValidateTenantPermissionsFunc (optional, nil) - called by ValidateTenantPermissions once its queued responses are used
+	ValidateTenantPermissionsFunc func(token *auth.Token, tenant string, permissions []string) bool
+	// ValidateRolesFunc (optional, nil) - called by ValidateRoles once its queued responses are used
+	ValidateRolesFunc func(token *auth.Token, roles []string) bool
+	// ValidateTenantRolesFunc (optional, nil) - called by ValidateTenantRoles once its queued responses are used
+	ValidateTenantRolesFunc func(token *auth.Token, tenant string, roles []string) bool
+	// LogoutFunc (optional, nil) - called by Logout once its queued responses are used
+	LogoutFunc func(request *http.Request, w http.ResponseWriter) error
+	// LogoutAllFunc (optional, nil) - called by LogoutAll once its queued responses are used
+	LogoutAllFunc func(request *http.Request, w http.ResponseWriter) error
+	// MeFunc (optional, nil) - called by Me once its queued responses are used
+	MeFunc func(request *http.Request) (*auth.UserResponse, error)
+}
+
+// NewAuthentication - creates a Authentication mock
+func NewAuthentication() *Authentication {
+	return &Authentication{
+		MagicLinkMock:     NewMagicLink(),
+		EnchantedLinkMock: NewEnchantedLink(),
+		PasswordMock:      NewPassword(),
+		OTPMock:           NewOTP(),
+		TOTPMock:          NewTOTP(),
+		OAuthMock:         NewOAuth(),
+		SAMLMock:          NewSAML(),
+		WebAuthnMock:      NewWebAuthn(),
+	}
+}
+
+// MagicLink - returns MagicLinkMock
+func (m *Authentication) MagicLink() auth.MagicLink {
+	return m.MagicLinkMock
+}
+
+// EnchantedLink - returns EnchantedLinkMock
+func (m *Authentication) EnchantedLink() auth.EnchantedLink {
+	return m.EnchantedLinkMock
+}
+
+// Password - returns PasswordMock
+func (m *Authentication) Password() auth.Password {
+	return m.PasswordMock
+}
+
+// OTP - returns OTPMock
+func (m *Authentication) OTP() auth.OTP {
+	return m.OTPMock
+}
+
+// TOTP - returns TOTPMock
+func (m *Authentication) TOTP() auth.TOTP {
+	return m.TOTPMock
+}
+
+// OAuth - returns OAuthMock
+func (m *Authentication) OAuth() auth.OAuth {
+	return m.OAuthMock
+}
+
+// SAML - returns SAMLMock
+func (m *Authentication) SAML() auth.SAML {
+	return m.SAMLMock
+}
+
+// WebAuthn - returns WebAuthnMock
+func (m *Authentication) WebAuthn() auth.WebAuthn {
+	return m.WebAuthnMock
+}
+
+// ValidateSession - records the call and returns the next queued response
+func (m *Authentication) ValidateSession(request *http.Request, w http.ResponseWriter) (bool, *auth.Token, error) {
+	m.record("ValidateSession", request, w)
+	if res, ok := m.dequeue("ValidateSession"); ok {
+		r0, _ := res[0].(bool)
+		r1, _ := res[1].(*auth.Token)
+		r2, _ := res[2].(error)
+		return r0, r1, r2
+	}
+	if m.ValidateSessionFunc != nil {
+		return m.ValidateSessionFunc(request, w)
+	}
+	return false, nil, nil
+}
+
+// QueueValidateSession - queues a response to be returned by a call of ValidateSession
+func (m *Authentication) QueueValidateSession(ok bool, token *auth.Token, err error) {
+	m.enqueue("ValidateSession", ok, token, err)
+}
+
+// ValidateSessionTokens - records the call and returns the next queued response
+func (m *Authentication) ValidateSessionTokens(sessionToken string, refreshToken string) (bool, *auth.Token, error) {
+	m.record("ValidateSessionTokens", sessionToken, refreshToken)
+	if res, ok := m.dequeue("ValidateSessionTokens"); ok {
+		r0, _ := res[0].(bool)
+		r1, _ := res[1].(*auth.Token)
+		r2, _ := res[2].(error)
+		return r0, r1, r2
+	}
+	if m.ValidateSessionTokensFunc != nil {
+		return m.ValidateSessionTokensFunc(sessionToken, refreshToken)
+	}
+	return false, nil, nil
+}
+
+// QueueValidateSessionTokens - queues a response to be returned by a call of ValidateSessionTokens
+func (m *Authentication) QueueValidateSessionTokens(ok bool, token *auth.Token, err error) {
+	m.enqueue("ValidateSessionTokens", ok, token, err)
+}
+
+// RefreshSession - records the call and returns the next queued response
+func (m *Authentication) RefreshSession(request *http.Request, w http.ResponseWriter) (bool, *auth.Token, error) {
+	m.record("RefreshSession", request, w)
+	if res, ok := m.dequeue("RefreshSession"); ok {
+		r0, _ := res[0].(bool)
+		r1, _ := res[1].(*auth.Token)
+		r2, _ := res[2].(error)
+		return r0, r1, r2
+	}
+	if m.RefreshSessionFunc != nil {
+		return m.RefreshSessionFunc(request, w)
+	}
+	return false, nil, nil
+}
+
+// QueueRefreshSession - queues a response to be returned by a call of RefreshSession
+func (m *Authentication) QueueRefreshSession(ok bool, token *auth.Token, err error) {
+	m.enqueue("RefreshSession", ok, token, err)
+}
+
+// ExchangeAccessKey - records the call and returns the next queued response
+func (m *Authentication) ExchangeAccessKey(accessKey string) (bool, *auth.Token, error) {
+	m.record("ExchangeAccessKey", accessKey)
+	if res, ok := m.dequeue("ExchangeAccessKey"); ok {
+		r0, _ := res[0].(bool)
+		r1, _ := res[1].(*auth.Token)
+		r2, _ := res[2].(error)
+		return r0, r1, r2
+	}
+	if m.ExchangeAccessKeyFunc != nil {
+		return m.ExchangeAccessKeyFunc(accessKey)
+	}
+	return false, nil, nil
+}
+
+// QueueExchangeAccessKey - queues a response to be returned by a call of ExchangeAccessKey
+func (m *Authentication) QueueExchangeAccessKey(ok bool, token *auth.Token, err error) {
+	m.enqueue("ExchangeAccessKey", ok, token, err)
+}
+
+// ValidatePermissions - records the call and returns the next queued response
+func (m *Authentication) ValidatePermissions(token *auth.Token, permissions []string) bool {
+	m.record("ValidatePermissions", token, permissions)
+	if res, ok := m.dequeue("ValidatePermissions"); ok {
+		r0, _ := res[0].(bool)
+		return r0
+	}
+	if m.ValidatePermissionsFunc != nil {
+		return m.ValidatePermissionsFunc(token, permissions)
+	}
+	return false
+}
+
+// QueueValidatePermissions - queues a response to be returned by a call of ValidatePermissions
+func (m *Authentication) QueueValidatePermissions(ok bool) {
+	m.enqueue("ValidatePermissions", ok)
+}
+
+// ValidateTenantPermissions - records the call and returns the next queued response
+func (m *Authentication) ValidateTenantPermissions(token *auth.Token, tenant string, permissions []string) bool {
+	m.record("ValidateTenantPermissions", token, tenant, permissions)
+	if res, ok := m.dequeue("ValidateTenantPermissions"); ok {
+		r0, _ := res[0].(bool)
+		return r0
+	}
+	if m.ValidateTenantPermissionsFunc != nil {
+		return m.ValidateTenantPermissionsFunc(token, tenant, permissions)
+	}
+	return false
+}
+
+// QueueValidateTenantPermissions - queues a response to be returned by a call of ValidateTenantPermissions
+func (m *Authentication) QueueValidateTenantPermissions(ok bool) {
+	m.enqueue("ValidateTenantPermissions", ok)
+}
+
+// ValidateRoles - records the call and returns the next queued response
+func (m *Authentication) ValidateRoles(token *auth.Token, roles []string) bool {
+	m.record("ValidateRoles", token, roles)
+	if res, ok := m.dequeue("ValidateRoles"); ok {
+		r0, _ := res[0].(bool)
+		return r0
+	}
+	if m.ValidateRolesFunc != nil {
+		return m.ValidateRolesFunc(token, roles)
+	}
+	return false
+}
+
+// QueueValidateRoles - queues a response to be returned by a call of ValidateRoles
+func (m *Authentication) QueueValidateRoles(ok bool) {
+	m.enqueue("ValidateRoles", ok)
+}
+
+// ValidateTenantRoles - records the call and returns the next queued response
+func (m *Authentication) ValidateTenantRoles(token *auth.Token, tenant string, roles []string) bool {
+	m.record("ValidateTenantRoles", token, tenant, roles)
+	if res, ok := m.dequeue("ValidateTenantRoles"); ok {
+		r0, _ := res[0].(bool)
+		return r0
+	}
+	if m.ValidateTenantRolesFunc != nil {
+		return m.ValidateTenantRolesFunc(token, tenant, roles)
+	}
+	return false
+}
+
+// QueueValidateTenantRoles - queues a response to be returned by a call of ValidateTenantRoles
+func (m *Authentication) QueueValidateTenantRoles(ok bool) {
+	m.enqueue("ValidateTenantRoles", ok)
+}
+
+// Logout - records the call and returns the next queued response
+func (m *Authentication) Logout(request *http.Request, w http.ResponseWriter) error {
+	m.record("Logout", request, w)
+	if res, ok := m.dequeue("Logout"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.LogoutFunc != nil {
+		return m.LogoutFunc(request, w)
+	}
+	return nil
+}
+
+// QueueLogout - queues a response to be returned by a call of Logout
+func (m *Authentication) QueueLogout(err error) {
+	m.enqueue("Logout", err)
+}
+
+// LogoutAll - records the call and returns the next queued response
+func (m *Authentication) LogoutAll(request *http.Request, w http.ResponseWriter) error {
+	m.record("LogoutAll", request, w)
+	if res, ok := m.dequeue("LogoutAll"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.LogoutAllFunc != nil {
+		return m.LogoutAllFunc(request, w)
+	}
+	return nil
+}
+
+// QueueLogoutAll - queues a response to be returned by a call of LogoutAll
+func (m *Authentication) QueueLogoutAll(err error) {
+	m.enqueue("LogoutAll", err)
+}
+
+// Me - records the call and returns the next queued response
+func (m *Authentication) Me(request *http.Request) (*auth.UserResponse, error) {
+	m.record("Me", request)
+	if res, ok := m.dequeue("Me"); ok {
+		r0, _ := res[0].(*auth.UserResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.MeFunc != nil {
+		return m.MeFunc(request)
+	}
+	return nil, nil
+}
+
+// QueueMe - queues a response to be returned by a call of Me
+func (m *Authentication) QueueMe(userResponse *auth.UserResponse, err error) {
+	m.enqueue("Me", userResponse, err)
+}
+
+// Tenant - a mock of mgmt.Tenant, see the package documentation
+type Tenant struct {
+	Recorder
+
+	// CreateFunc (optional, nil) - called by Create once its queued responses are used
+	CreateFunc func(managementKey string, name string, selfProvisioningDomains []string) (string, error)
+	// CreateWithIDFunc (optional, nil) - called by CreateWithID once its queued responses are used
+	CreateWithIDFunc func(managementKey string, id string, name string, selfProvisioningDomains []string) error
+	// UpdateFunc (optional, nil) - called by Update once its queued responses are used
+	UpdateFunc func(managementKey string, id string, name string, selfProvisioningDomains []string) error
+	// DeleteFunc (optional, nil) - called by Delete once its queued responses are used
+	DeleteFunc func(managementKey string, id string) error
+	// LoadAllFunc (optional, nil) - called by LoadAll once its queued responses are used
+	LoadAllFunc func(managementKey string) ([]*mgmt.TenantResponse, error)
+}
+
+// NewTenant - creates a Tenant mock
+func NewTenant() *Tenant {
+	return &Tenant{}
+}
+
+// Create - records the call and returns the next queued response
+func (m *Tenant) Create(managementKey string, name string, selfProvisioningDomains []string) (string, error) {
+	m.record("Create", managementKey, name, selfProvisioningDomains)
+	if res, ok := m.dequeue("Create"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.CreateFunc != nil {
+		return m.CreateFunc(managementKey, name, selfProvisioningDomains)
+	}
+	return "", nil
+}
+
+// QueueCreate - queues a response to be returned by a call of Create
+func (m *Tenant) QueueCreate(id string, err error) {
+	m.enqueue("Create", id, err)
+}
+
+// CreateWithID - records the call and returns the next queued response
+func (m *Tenant) CreateWithID(managementKey string, id string, name string, selfProvisioningDomains []string) error {
+	m.record("CreateWithID", managementKey, id, name, selfProvisioningDomains)
+	if res, ok := m.dequeue("CreateWithID"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.CreateWithIDFunc != nil {
+		return m.CreateWithIDFunc(managementKey, id, name, selfProvisioningDomains)
+	}
+	return nil
+}
+
+// QueueCreateWithID - queues a response to be returned by a call of CreateWithID
+func (m *Tenant) QueueCreateWithID(err error) {
+	m.enqueue("CreateWithID", err)
+}
+
+// Update - records the call and returns the next queued response
+func (m *Tenant) Update(managementKey string, id string, name string, selfProvisioningDomains []string) error {
+	m.record("Update", managementKey, id, name, selfProvisioningDomains)
+	if res, ok := m.dequeue("Update"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(managementKey, id, name, selfProvisioningDomains)
+	}
+	return nil
+}
+
+// QueueUpdate - queues a response to be returned by a call of Update
+func (m *Tenant) QueueUpdate(err error) {
+	m.enqueue("Update", err)
+}
+
+// Delete - records the call and returns the next queued response
+func (m *Tenant) Delete(managementKey string, id string) error {
+	m.record("Delete", managementKey, id)
+	if res, ok := m.dequeue("Delete"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(managementKey, id)
+	}
+	return nil
+}
+
+// QueueDelete - queues a response to be returned by a call of Delete
+func (m *Tenant) QueueDelete(err error) {
+	m.enqueue("Delete", err)
+}
+
+// LoadAll - records the call and returns the next queued response
+func (m *Tenant) LoadAll(managementKey string) ([]*mgmt.TenantResponse, error) {
+	m.record("LoadAll", managementKey)
+	if res, ok := m.dequeue("LoadAll"); ok {
+		r0, _ := res[0].([]*mgmt.TenantResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.LoadAllFunc != nil {
+		return m.LoadAllFunc(managementKey)
+	}
+	return nil, nil
+}
+
+// QueueLoadAll - queues a response to be returned by a call of LoadAll
+func (m *Tenant) QueueLoadAll(tenantResponse []*mgmt.TenantResponse, err error) {
+	m.enqueue("LoadAll", tenantResponse, err)
+}
+
+// User - a mock of mgmt.User, see the package documentation
+type User struct {
+	Recorder
+
+	// CreateFunc (optional, nil) - called by Create once its queued responses are used
+	CreateFunc func(managementKey string, identifier string, email string, phone string, displayName string, roles []string, tenants []mgmt.UserTenants) error
+	// CreateTestUserFunc (optional, nil) - called by CreateTestUser once its queued responses are used
+	CreateTestUserFunc func(managementKey string, identifier string, email string, phone string, displayName string, roles []string, tenants []mgmt.UserTenants) error
+	// UpdateFunc (optional, nil) - called by Update once its queued responses are used
+	UpdateFunc func(managementKey string, identifier string, email string, phone string, displayName string, roles []string, tenants []mgmt.UserTenants) error
+	// DeleteFunc (optional, nil) - called by Delete once its queued responses are used
+	DeleteFunc func(managementKey string, identifier string) error
+	// DeleteAllTestUsersFunc (optional, nil) - called by DeleteAllTestUsers once its queued responses are used
+	DeleteAllTestUsersFunc func(managementKey string) error
+	// GenerateEmbeddedLinkFunc (optional, nil) - called by GenerateEmbeddedLink once its queued responses are used
+	GenerateEmbeddedLinkFunc func(managementKey string, identifier string, customClaims map[string]any) (string, error)
+	// GenerateOTPForTestUserFunc (optional, nil) - called by GenerateOTPForTestUser once its queued responses are used
+	GenerateOTPForTestUserFunc func(managementKey string, method auth.DeliveryMethod, identifier string) (string, error)
+	// GenerateMagicLinkForTestUserFunc (optional, nil) - called by GenerateMagicLinkForTestUser once its queued responses are used
+	GenerateMagicLinkForTestUserFunc func(managementKey string, method auth.DeliveryMethod, identifier string, URI string) (string, error)
+}
+
+// NewUser - creates a User mock
+func NewUser() *User {
+	return &User{}
+}
+
+// Create - records the call and returns the next queued response
+func (m *User) Create(managementKey string, identifier string, email string, phone string, displayName string, roles []string, tenants []mgmt.UserTenants) error {
+	m.record("Create", managementKey, identifier, email, phone, displayName, roles, tenants)
+	if res, ok := m.dequeue("Create"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.CreateFunc != nil {
+		return m.CreateFunc(managementKey, identifier, email, phone, displayName, roles, tenants)
+	}
+	return nil
+}
+
+// QueueCreate - queues a response to be returned by a call of Create
+func (m *User) QueueCreate(err error) {
+	m.enqueue("Create", err)
+}
+
+// CreateTestUser - records the call and returns the next queued response
+func (m *User) CreateTestUser(managementKey string, identifier string, email string, phone string, displayName string, roles []string, tenants []mgmt.UserTenants) error {
+	m.record("CreateTestUser", managementKey, identifier, email, phone, displayName, roles, tenants)
+	if res, ok := m.dequeue("CreateTestUser"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.CreateTestUserFunc != nil {
+		return m.CreateTestUserFunc(managementKey, identifier, email, phone, displayName, roles, tenants)
+	}
+	return nil
+}
+
+// QueueCreateTestUser - queues a response to be returned by a call of CreateTestUser
+func (m *User) QueueCreateTestUser(err error) {
+	m.enqueue("CreateTestUser", err)
+}
+
+// Update - records the call and returns the next queued response
+func (m *User) Update(managementKey string, identifier string, email string, phone string, displayName string, roles []string, tenants []mgmt.UserTenants) error {
+	m.record("Update", managementKey, identifier, email, phone, displayName, roles, tenants)
+	if res, ok := m.dequeue("Update"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(managementKey, identifier, email, phone, displayName, roles, tenants)
+	}
+	return nil
+}
+
+// QueueUpdate - queues a response to be returned by a call of Update
+func (m *User) QueueUpdate(err error) {
+	m.enqueue("Update", err)
+}
+
+// Delete - records the call and returns the next queued response
+func (m *User) Delete(managementKey string, identifier string) error {
+	m.record("Delete", managementKey, identifier)
+	if res, ok := m.dequeue("Delete"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(managementKey, identifier)
+	}
+	return nil
+}
+
+// QueueDelete - queues a response to be returned by a call of Delete
+func (m *User) QueueDelete(err error) {
+	m.enqueue("Delete", err)
+}
+
+// DeleteAllTestUsers - records the call and returns the next queued response
+func (m *User) DeleteAllTestUsers(managementKey string) error {
+	m.record("DeleteAllTestUsers", managementKey)
+	if res, ok := m.dequeue("DeleteAllTestUsers"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.DeleteAllTestUsersFunc != nil {
+		return m.DeleteAllTestUsersFunc(managementKey)
+	}
+	return nil
+}
+
+// QueueDeleteAllTestUsers - queues a response to be returned by a call of DeleteAllTestUsers
+func (m *User) QueueDeleteAllTestUsers(err error) {
+	m.enqueue("DeleteAllTestUsers", err)
+}
+
+// GenerateEmbeddedLink - records the call and returns the next queued response
+func (m *User) GenerateEmbeddedLink(managementKey string, identifier string, customClaims map[string]any) (string, error) {
+	m.record("GenerateEmbeddedLink", managementKey, identifier, customClaims)
+	if res, ok := m.dequeue("GenerateEmbeddedLink"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.GenerateEmbeddedLinkFunc != nil {
+		return m.GenerateEmbeddedLinkFunc(managementKey, identifier, customClaims)
+	}
+	return "", nil
+}
+
+// QueueGenerateEmbeddedLink - queues a response to be returned by a call of GenerateEmbeddedLink
+func (m *User) QueueGenerateEmbeddedLink(token string, err error) {
+	m.enqueue("GenerateEmbeddedLink", token, err)
+}
+
+// GenerateOTPForTestUser - records the call and returns the next queued response
+func (m *User) GenerateOTPForTestUser(managementKey string, method auth.DeliveryMethod, identifier string) (string, error) {
+	m.record("GenerateOTPForTestUser", managementKey, method, identifier)
+	if res, ok := m.dequeue("GenerateOTPForTestUser"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.GenerateOTPForTestUserFunc != nil {
+		return m.GenerateOTPForTestUserFunc(managementKey, method, identifier)
+	}
+	return "", nil
+}
+
+// QueueGenerateOTPForTestUser - queues a response to be returned by a call of GenerateOTPForTestUser
+func (m *User) QueueGenerateOTPForTestUser(code string, err error) {
+	m.enqueue("GenerateOTPForTestUser", code, err)
+}
+
+// GenerateMagicLinkForTestUser - records the call and returns the next queued response
+func (m *User) GenerateMagicLinkForTestUser(managementKey string, method auth.DeliveryMethod, identifier string, URI string) (string, error) {
+	m.record("GenerateMagicLinkForTestUser", managementKey, method, identifier, URI)
+	if res, ok := m.dequeue("GenerateMagicLinkForTestUser"); ok {
+		r0, _ := res[0].(string)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.GenerateMagicLinkForTestUserFunc != nil {
+		return m.GenerateMagicLinkForTestUserFunc(managementKey, method, identifier, URI)
+	}
+	return "", nil
+}
+
+// QueueGenerateMagicLinkForTestUser - queues a response to be returned by a call of GenerateMagicLinkForTestUser
+func (m *User) QueueGenerateMagicLinkForTestUser(link string, err error) {
+	m.enqueue("GenerateMagicLinkForTestUser", link, err)
+}
+
+// SSO - a mock of mgmt.SSO, see the package documentation
+type SSO struct {
+	Recorder
+
+	// ConfigureSettingsFunc (optional, nil) - called by ConfigureSettings once its queued responses are used
+	ConfigureSettingsFunc func(managementKey string, tenantID string, enabled bool, idpURL string, idpCert string, entityID string, redirectURL string) error
+	// LoadSettingsFunc (optional, nil) - called by LoadSettings once its queued responses are used
+	LoadSettingsFunc func(managementKey string, tenantID string) (*mgmt.SSOSettingsResponse, error)
+	// ConfigureMetadataFunc (optional, nil) - called by ConfigureMetadata once its queued responses are used
+	ConfigureMetadataFunc func(managementKey string, tenantID string, enabled bool, idpMetadataURL string) error
+	// ConfigureRoleMappingFunc (optional, nil) - called by ConfigureRoleMapping once its queued responses are used
+	ConfigureRoleMappingFunc func(managementKey string, tenantID string, roleMappings []mgmt.RoleMapping) error
+}
+
+// NewSSO - creates a SSO mock
+func NewSSO() *SSO {
+	return &SSO{}
+}
+
+// ConfigureSettings - records the call and returns the next queued response
+func (m *SSO) ConfigureSettings(managementKey string, tenantID string, enabled bool, idpURL string, idpCert string, entityID string, redirectURL string) error {
+	m.record("ConfigureSettings", managementKey, tenantID, enabled, idpURL, idpCert, entityID, redirectURL)
+	if res, ok := m.dequeue("ConfigureSettings"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.ConfigureSettingsFunc != nil {
+		return m.ConfigureSettingsFunc(managementKey, tenantID, enabled, idpURL, idpCert, entityID, redirectURL)
+	}
+	return nil
+}
+
+// QueueConfigureSettings - queues a response to be returned by a call of ConfigureSettings
+func (m *SSO) QueueConfigureSettings(err error) {
+	m.enqueue("ConfigureSettings", err)
+}
+
+// LoadSettings - records the call and returns the next queued response
+func (m *SSO) LoadSettings(managementKey string, tenantID string) (*mgmt.SSOSettingsResponse, error) {
+	m.record("LoadSettings", managementKey, tenantID)
+	if res, ok := m.dequeue("LoadSettings"); ok {
+		r0, _ := res[0].(*mgmt.SSOSettingsResponse)
+		r1, _ := res[1].(error)
+		return r0, r1
+	}
+	if m.LoadSettingsFunc != nil {
+		return m.LoadSettingsFunc(managementKey, tenantID)
+	}
+	return nil, nil
+}
+
+// QueueLoadSettings - queues a response to be returned by a call of LoadSettings
+func (m *SSO) QueueLoadSettings(sSOSettingsResponse *mgmt.SSOSettingsResponse, err error) {
+	m.enqueue("LoadSettings", sSOSettingsResponse, err)
+}
+
+// ConfigureMetadata - records the call and returns the next queued response
+func (m *SSO) ConfigureMetadata(managementKey string, tenantID string, enabled bool, idpMetadataURL string) error {
+	m.record("ConfigureMetadata", managementKey, tenantID, enabled, idpMetadataURL)
+	if res, ok := m.dequeue("ConfigureMetadata"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.ConfigureMetadataFunc != nil {
+		return m.ConfigureMetadataFunc(managementKey, tenantID, enabled, idpMetadataURL)
+	}
+	return nil
+}
+
+// QueueConfigureMetadata - queues a response to be returned by a call of ConfigureMetadata
+func (m *SSO) QueueConfigureMetadata(err error) {
+	m.enqueue("ConfigureMetadata", err)
+}
+
+// ConfigureRoleMapping - records the call and returns the next queued response
+func (m *SSO) ConfigureRoleMapping(managementKey string, tenantID string, roleMappings []mgmt.RoleMapping) error {
+	m.record("ConfigureRoleMapping", managementKey, tenantID, roleMappings)
+	if res, ok := m.dequeue("ConfigureRoleMapping"); ok {
+		r0, _ := res[0].(error)
+		return r0
+	}
+	if m.ConfigureRoleMappingFunc != nil {
+		return m.ConfigureRoleMappingFunc(managementKey, tenantID, roleMappings)
+	}
+	return nil
+}
+
+// QueueConfigureRoleMapping - queues a response to be returned by a call of ConfigureRoleMapping
+func (m *SSO) QueueConfigureRoleMapping(err error) {
+	m.enqueue("ConfigureRoleMapping", err)
+}
+
+// Management - a mock of mgmt.Management, see the package documentation
+type Management struct {
+	Recorder
+
+	// TenantMock - returned by Tenant, set by NewManagement
+	TenantMock *Tenant
+	// UserMock - returned by User, set by NewManagement
+	UserMock *User
+	// SSOMock - returned by SSO, set by NewManagement
+	SSOMock *SSO
+}
+
+// NewManagement - creates a Management mock
+func NewManagement() *Management {
+	return &Management{
+		TenantMock: NewTenant(),
+		UserMock:   NewUser(),
+		SSOMock:    NewSSO(),
+	}
+}
+
+// Tenant - returns TenantMock
+func (m *Management) Tenant() mgmt.Tenant {
+	return m.TenantMock
+}
+
+// User - returns UserMock
+func (m *Management) User() mgmt.User {
+	return m.UserMock
+}
+
+// SSO - returns SSOMock
+func (m *Management) SSO() mgmt.SSO {
+	return m.SSOMock
+}
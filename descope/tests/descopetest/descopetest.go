@@ -0,0 +1,199 @@
+// Package descopetest provides utilities for unit testing code that validates Descope sessions, such as handlers
+// behind the AuthenticationMiddleware, without mocking the Authentication interface.
+//
+// A Minter generates a signing key pair and mints session and refresh JWTs with arbitrary claims. The clients it
+// creates are configured with the matching public key, so ValidateSession runs the real verification code on the minted
+// tokens. These clients never send requests to Descope, any call that requires one fails with an error.
+package descopetest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwt"
+
+	"github.com/descope/go-sdk/descope"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/internal/testkeys"
+)
+
+const (
+	defaultProjectID       = "P2descopetest"
+	defaultSessionTokenTTL = 10 * time.Minute
+	defaultRefreshTokenTTL = 24 * time.Hour
+
+	sessionTokenDRN = auth.SessionCookieName
+	refreshTokenDRN = auth.RefreshCookieName
+)
+
+// OfflineError - returned by clients created by a Minter for any call that requires sending a request
+var OfflineError = errors.NewError("offline", "descopetest clients do not send requests")
+
+// TenantClaims - the roles and permissions of the subject in a tenant
+type TenantClaims struct {
+	Roles       []string
+	Permissions []string
+}
+
+// TokenOptions - the claims of a minted token
+type TokenOptions struct {
+	// Subject (optional, "U2descopetest") - the user ID of the token.
+	Subject string
+	// Roles (optional, nil) - the project level roles of the subject.
+	Roles []string
+	// Permissions (optional, nil) - the project level permissions of the subject.
+	Permissions []string
+	// Tenants (optional, nil) - the roles and permissions of the subject in each tenant, by tenant ID.
+	Tenants map[string]TenantClaims
+	// AuthFactors (optional, nil) - the factors the subject authenticated with, more than one means MFA.
+	AuthFactors []auth.AuthFactor
+	// Expiration (optional, 10 minutes for session tokens, 24 hours for refresh tokens) - when the token expires,
+	// a time in the past mints an expired token.
+	Expiration time.Time
+	// CustomClaims (optional, nil) - additional claims added to the token.
+	CustomClaims map[string]any
+}
+
+// Minter - mints tokens that are valid for the clients it creates, see the package documentation.
+type Minter struct {
+	// ProjectID - the project of the minted tokens and of the created clients
+	ProjectID string
+
+	keys *testkeys.KeyPair
+}
+
+// NewMinter - creates a minter with a newly generated key pair, for the given project or for a test project when empty.
+func NewMinter(projectID string) (*Minter, error) {
+	if projectID == "" {
+		projectID = defaultProjectID
+	}
+	keys, err := testkeys.Generate()
+	if err != nil {
+		return nil, err
+	}
+	return &Minter{ProjectID: projectID, keys: keys}, nil
+}
+
+// PublicKey - the public key of the minted tokens, as JSON, to be used as the PublicKey in the client configuration
+func (m *Minter) PublicKey() string {
+	return m.keys.PublicKeyJSON()
+}
+
+// Config - a client configuration that validates the minted tokens and never sends requests
+func (m *Minter) Config() *descope.Config {
+	return &descope.Config{ProjectID: m.ProjectID, PublicKey: m.PublicKey(), DefaultClient: offlineClient{}}
+}
+
+// NewClient - creates a client with the configuration returned by Config
+func (m *Minter) NewClient() (*descope.DescopeClient, error) {
+	return descope.NewDescopeClientWithConfig(m.Config())
+}
+
+// SessionToken - mints a session JWT with the given claims
+func (m *Minter) SessionToken(options TokenOptions) (string, error) {
+	return m.mint(sessionTokenDRN, defaultSessionTokenTTL, options)
+}
+
+// RefreshToken - mints a refresh JWT with the given claims
+func (m *Minter) RefreshToken(options TokenOptions) (string, error) {
+	return m.mint(refreshTokenDRN, defaultRefreshTokenTTL, options)
+}
+
+// Resign - signs the claims of the given JWT again, whose signature may have been removed, such as the JWTs replayed by
+// the replay package. The issue and expiration times are moved to keep the lifetime of the token starting now.
+func (m *Minter) Resign(jwtString string) (string, error) {
+	parts := strings.Split(jwtString, ".")
+	if len(parts) != 3 {
+		return "", errors.NewInvalidArgumentError("jwt")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", err
+	}
+	claims := map[string]any{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	iat, _ := claims[jwt.IssuedAtKey].(float64)
+	exp, _ := claims[jwt.ExpirationKey].(float64)
+	ttl := defaultSessionTokenTTL
+	if exp > iat && iat > 0 {
+		ttl = time.Duration(exp-iat) * time.Second
+	}
+	claims[jwt.IssuedAtKey] = now
+	claims[jwt.ExpirationKey] = now.Add(ttl)
+	return m.keys.Sign(claims)
+}
+
+// AddCookies - adds the given tokens to the request the way the browser sends them, empty tokens are not added
+func AddCookies(r *http.Request, sessionJwt, refreshJwt string) {
+	if sessionJwt != "" {
+		r.AddCookie(&http.Cookie{Name: auth.SessionCookieName, Value: sessionJwt})
+	}
+	if refreshJwt != "" {
+		r.AddCookie(&http.Cookie{Name: auth.RefreshCookieName, Value: refreshJwt})
+	}
+}
+
+func (m *Minter) mint(drn string, ttl time.Duration, options TokenOptions) (string, error) {
+	now := time.Now()
+	expiration := options.Expiration
+	if expiration.IsZero() {
+		expiration = now.Add(ttl)
+	}
+	issuedAt := now
+	if expiration.Before(issuedAt) {
+		issuedAt = expiration.Add(-ttl)
+	}
+	subject := options.Subject
+	if subject == "" {
+		subject = "U2descopetest"
+	}
+
+	claims := map[string]any{}
+	for k, v := range options.CustomClaims {
+		claims[k] = v
+	}
+	claims[jwt.JwtIDKey] = testkeys.RandomID()
+	claims[jwt.SubjectKey] = subject
+	claims[jwt.IssuerKey] = m.ProjectID
+	claims[jwt.AudienceKey] = []string{m.ProjectID}
+	claims[jwt.IssuedAtKey] = issuedAt
+	claims[jwt.ExpirationKey] = expiration
+	claims["drn"] = drn
+	if options.Roles != nil {
+		claims["roles"] = options.Roles
+	}
+	if options.Permissions != nil {
+		claims["permissions"] = options.Permissions
+	}
+	if options.Tenants != nil {
+		tenants := map[string]any{}
+		for id, t := range options.Tenants {
+			tenants[id] = map[string]any{"roles": emptyIfNil(t.Roles), "permissions": emptyIfNil(t.Permissions)}
+		}
+		claims[auth.ClaimAuthorizedTenants] = tenants
+	}
+	if options.AuthFactors != nil {
+		claims["amr"] = options.AuthFactors
+	}
+	return m.keys.Sign(claims)
+}
+
+type offlineClient struct{}
+
+func (offlineClient) Do(*http.Request) (*http.Response, error) {
+	return nil, OfflineError
+}
+
+func emptyIfNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,101 @@
+package descopetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionTokenClaims(t *testing.T) {
+	m, err := NewMinter("")
+	require.NoError(t, err)
+	client, err := m.NewClient()
+	require.NoError(t, err)
+
+	jwt, err := m.SessionToken(TokenOptions{
+		Subject:      "U1",
+		Roles:        []string{"admin"},
+		Permissions:  []string{"users.write"},
+		Tenants:      map[string]TenantClaims{"T1": {Roles: []string{"viewer"}}},
+		AuthFactors:  []auth.AuthFactor{auth.AuthFactorEmail, auth.AuthFactorTOTP},
+		CustomClaims: map[string]any{"plan": "pro"},
+	})
+	require.NoError(t, err)
+	ok, token, err := client.Auth.ValidateSessionTokens(jwt, "")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.EqualValues(t, "U1", token.ID)
+	assert.EqualValues(t, m.ProjectID, token.ProjectID)
+	assert.True(t, client.Auth.ValidateRoles(token, []string{"admin"}))
+	assert.True(t, client.Auth.ValidatePermissions(token, []string{"users.write"}))
+	assert.True(t, client.Auth.ValidateTenantRoles(token, "T1", []string{"viewer"}))
+	assert.False(t, client.Auth.ValidateTenantPermissions(token, "T1", []string{"users.write"}))
+	assert.True(t, token.IsMFA())
+	assert.EqualValues(t, "pro", token.CustomClaim("plan"))
+}
+
+func TestExpiredSessionToken(t *testing.T) {
+	m, err := NewMinter("P2expired")
+	require.NoError(t, err)
+	client, err := m.NewClient()
+	require.NoError(t, err)
+
+	expired, err := m.SessionToken(TokenOptions{Expiration: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+	ok, _, err := client.Auth.ValidateSessionTokens(expired, "")
+	assert.False(t, ok)
+	assert.Error(t, err)
+
+	// refreshing requires a request, which the client never sends
+	refresh, err := m.RefreshToken(TokenOptions{})
+	require.NoError(t, err)
+	ok, _, err = client.Auth.ValidateSessionTokens(expired, refresh)
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, errors.FailedToRefreshTokenError)
+}
+
+func TestTokensFromOtherMinter(t *testing.T) {
+	m, err := NewMinter("")
+	require.NoError(t, err)
+	other, err := NewMinter("")
+	require.NoError(t, err)
+	client, err := m.NewClient()
+	require.NoError(t, err)
+
+	jwt, err := other.SessionToken(TokenOptions{})
+	require.NoError(t, err)
+	ok, _, err := client.Auth.ValidateSessionTokens(jwt, "")
+	assert.False(t, ok)
+	assert.Error(t, err)
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	m, err := NewMinter("")
+	require.NoError(t, err)
+	client, err := m.NewClient()
+	require.NoError(t, err)
+	handler := auth.AuthenticationMiddleware(client.Auth, nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "U1", r.Context().Value(auth.ContextUserIDPropertyKey))
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	session, err := m.SessionToken(TokenOptions{Subject: "U1"})
+	require.NoError(t, err)
+	refresh, err := m.RefreshToken(TokenOptions{Subject: "U1"})
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	AddCookies(r, session, refresh)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.EqualValues(t, http.StatusTeapot, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.EqualValues(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,646 @@
+package fakeserver
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/internal/testkeys"
+)
+
+type challengeKind int
+
+const (
+	otpChallenge challengeKind = iota
+	magicLinkChallenge
+	enchantedLinkChallenge
+)
+
+var (
+	userNotFoundError      = errors.NewError(errors.BadRequestErrorCode, "user not found")
+	userAlreadyExistsError = errors.NewError(errors.BadRequestErrorCode, "user already exists")
+	passwordPolicyError    = errors.NewError(errors.BadRequestErrorCode, "password does not meet the password policy")
+)
+
+type loginOptions struct {
+	Stepup       bool           `json:"stepup,omitempty"`
+	MFA          bool           `json:"mfa,omitempty"`
+	CustomClaims map[string]any `json:"customClaims,omitempty"`
+}
+
+type userBody struct {
+	ExternalID string `json:"externalId,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+	Email      string `json:"email,omitempty"`
+}
+
+// authBody - the union of the request bodies of the auth routes
+type authBody struct {
+	ExternalID    string        `json:"externalId"`
+	Email         string        `json:"email"`
+	Phone         string        `json:"phone"`
+	WhatsApp      string        `json:"whatsapp"`
+	User          *userBody     `json:"user"`
+	LoginOptions  *loginOptions `json:"loginOptions"`
+	URI           string        `json:"URI"`
+	CrossDevice   bool          `json:"crossDevice"`
+	Code          string        `json:"code"`
+	CodeVerifier  string        `json:"codeVerifier"`
+	Token         string        `json:"token"`
+	PendingRef    string        `json:"pendingRef"`
+	Password      string        `json:"password"`
+	OldPassword   string        `json:"oldPassword"`
+	NewPassword   string        `json:"newPassword"`
+	RedirectURL   string        `json:"redirectUrl"`
+	Origin        string        `json:"origin"`
+	TransactionID string        `json:"transactionID"`
+	Response      string        `json:"response"`
+}
+
+type userResponse struct {
+	UserID        string   `json:"userId,omitempty"`
+	ExternalIDs   []string `json:"externalIds,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Email         string   `json:"email,omitempty"`
+	Phone         string   `json:"phone,omitempty"`
+	VerifiedEmail bool     `json:"verifiedEmail,omitempty"`
+	VerifiedPhone bool     `json:"verifiedPhone,omitempty"`
+}
+
+type jwtResponse struct {
+	SessionJwt       string        `json:"sessionJwt,omitempty"`
+	RefreshJwt       string        `json:"refreshJwt,omitempty"`
+	CookiePath       string        `json:"cookiePath,omitempty"`
+	CookieMaxAge     int32         `json:"cookieMaxAge,omitempty"`
+	CookieExpiration int32         `json:"cookieExpiration,omitempty"`
+	User             *userResponse `json:"user,omitempty"`
+	FirstSeen        bool          `json:"firstSeen,omitempty"`
+}
+
+// pendingSession - the session of a verified pending reference, until it is fetched
+type pendingSession struct {
+	userID       string
+	firstSeen    bool
+	customClaims map[string]any
+}
+
+func (s *Server) authRoutes(handle, handleSubtree func(string, func(http.ResponseWriter, *http.Request))) {
+	handleSubtree(api.Routes.SignInOTP(), s.authHandler(s.signIn(otpChallenge)))
+	handleSubtree(api.Routes.SignUpOTP(), s.authHandler(s.signUp(otpChallenge)))
+	handleSubtree(api.Routes.SignUpOrInOTP(), s.authHandler(s.signUpOrIn(otpChallenge)))
+	handleSubtree(api.Routes.VerifyCode(), s.authHandler(s.verifyCode))
+	handle(api.Routes.UpdateUserEmailOTP(), s.authHandler(s.updateEmail(otpChallenge)))
+	handleSubtree(api.Routes.UpdateUserPhoneOTP(), s.authHandler(s.updatePhone(otpChallenge)))
+
+	handleSubtree(api.Routes.SignInMagicLink(), s.authHandler(s.signIn(magicLinkChallenge)))
+	handleSubtree(api.Routes.SignUpMagicLink(), s.authHandler(s.signUp(magicLinkChallenge)))
+	handleSubtree(api.Routes.SignUpOrInMagicLink(), s.authHandler(s.signUpOrIn(magicLinkChallenge)))
+	handle(api.Routes.VerifyMagicLink(), s.authHandler(s.verifyLink(true)))
+	handle(api.Routes.GetMagicLinkSession(), s.authHandler(s.pendingSession))
+	handle(api.Routes.UpdateUserEmailMagiclink(), s.authHandler(s.updateEmail(magicLinkChallenge)))
+	handleSubtree(api.Routes.UpdateUserPhoneMagicLink(), s.authHandler(s.updatePhone(magicLinkChallenge)))
+
+	handleSubtree(api.Routes.SignInEnchantedLink(), s.authHandler(s.signIn(enchantedLinkChallenge)))
+	handleSubtree(api.Routes.SignUpEnchantedLink(), s.authHandler(s.signUp(enchantedLinkChallenge)))
+	handleSubtree(api.Routes.SignUpOrInEnchantedLink(), s.authHandler(s.signUpOrIn(enchantedLinkChallenge)))
+	handle(api.Routes.VerifyEnchantedLink(), s.authHandler(s.verifyLink(false)))
+	handle(api.Routes.GetEnchantedLinkSession(), s.authHandler(s.pendingSession))
+	handle(api.Routes.UpdateUserEmailEnchantedLink(), s.authHandler(s.updateEmail(enchantedLinkChallenge)))
+
+	handle(api.Routes.SignUpPassword(), s.authHandler(s.signUpPassword))
+	handle(api.Routes.SignInPassword(), s.authHandler(s.signInPassword))
+	handle(api.Routes.SendPasswordReset(), s.authHandler(s.sendPasswordReset))
+	handle(api.Routes.UpdateUserPassword(), s.authHandler(s.updatePassword))
+	handle(api.Routes.ReplaceUserPassword(), s.authHandler(s.replacePassword))
+	handle(api.Routes.PasswordPolicy(), s.authHandler(s.passwordPolicy))
+
+	handle(api.Routes.OAuthStart(), s.authHandler(s.oauthStart))
+	handle(api.Routes.ExchangeTokenOAuth(), s.authHandler(s.exchange))
+	handle(api.Routes.SAMLStart(), s.authHandler(s.samlStart))
+	handle(api.Routes.ExchangeTokenSAML(), s.authHandler(s.exchange))
+
+	handle(api.Routes.RefreshToken(), s.authHandler(s.refresh))
+	handle(api.Routes.Logout(), s.authHandler(s.logout(false)))
+	handle(api.Routes.LogoutAll(), s.authHandler(s.logout(true)))
+	handle(api.Routes.Me(), s.authHandler(s.me))
+	handle(api.Routes.ExchangeAccessKey(), s.authHandler(s.exchangeAccessKey))
+
+	s.totpRoutes(handle)
+	s.webAuthnRoutes(handle)
+}
+
+type authHandlerFunc func(w http.ResponseWriter, r *http.Request, body *authBody)
+
+// authHandler locks the server and decodes the request body for the given handler
+func (s *Server) authHandler(handler authHandlerFunc) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.bearer(r); !ok {
+			writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+			return
+		}
+		body := &authBody{}
+		if err := readBody(r, body); err != nil {
+			writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("body"))
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		handler(w, r, body)
+	}
+}
+
+// refreshUser returns the user of the refresh token in the request, or writes an unauthorized error
+func (s *Server) refreshUser(w http.ResponseWriter, r *http.Request) (*User, string, bool) {
+	refreshJwt, _ := s.bearer(r)
+	u, tokenID, ok := s.validateRefreshToken(refreshJwt)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+	}
+	return u, tokenID, ok
+}
+
+func (s *Server) signIn(kind challengeKind) authHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, body *authBody) {
+		s.startSignIn(kind, w, r, body)
+	}
+}
+
+func (s *Server) startSignIn(kind challengeKind, w http.ResponseWriter, r *http.Request, body *authBody) {
+	if s.findUser(body.ExternalID) == nil {
+		writeError(w, http.StatusBadRequest, userNotFoundError)
+		return
+	}
+	ch := &challenge{loginID: body.ExternalID, verifies: verifiedContact(path.Base(r.URL.Path))}
+	if body.LoginOptions != nil {
+		if body.LoginOptions.Stepup || body.LoginOptions.MFA {
+			if _, _, ok := s.refreshUser(w, r); !ok {
+				return
+			}
+		}
+		ch.customClaims = body.LoginOptions.CustomClaims
+	}
+	s.sendChallenge(kind, ch, body.CrossDevice, w, r)
+}
+
+func (s *Server) signUp(kind challengeKind) authHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, body *authBody) {
+		s.startSignUp(kind, w, r, body)
+	}
+}
+
+func (s *Server) startSignUp(kind challengeKind, w http.ResponseWriter, r *http.Request, body *authBody) {
+	if body.ExternalID == "" {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("externalId"))
+		return
+	}
+	if s.findUser(body.ExternalID) != nil {
+		writeError(w, http.StatusBadRequest, userAlreadyExistsError)
+		return
+	}
+	u := &User{LoginIDs: []string{body.ExternalID}}
+	if body.User != nil {
+		u.Name, u.Email, u.Phone = body.User.Name, body.User.Email, body.User.Phone
+	}
+	u.Email = firstNonEmpty(body.Email, u.Email)
+	u.Phone = firstNonEmpty(body.Phone, body.WhatsApp, u.Phone)
+	ch := &challenge{loginID: body.ExternalID, signUp: u, verifies: verifiedContact(path.Base(r.URL.Path))}
+	s.sendChallenge(kind, ch, body.CrossDevice, w, r)
+}
+
+func (s *Server) signUpOrIn(kind challengeKind) authHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, body *authBody) {
+		if s.findUser(body.ExternalID) != nil {
+			s.startSignIn(kind, w, r, body)
+			return
+		}
+		switch verifiedContact(path.Base(r.URL.Path)) {
+		case "email":
+			body.Email = body.ExternalID
+		case "phone":
+			body.Phone = body.ExternalID
+		}
+		s.startSignUp(kind, w, r, body)
+	}
+}
+
+// sendChallenge stores the challenge instead of delivering it, so it can be read with OTPCode or MagicLinkToken,
+// and returns the OTP code in the response when it is embedded
+func (s *Server) sendChallenge(kind challengeKind, ch *challenge, crossDevice bool, w http.ResponseWriter, r *http.Request) {
+	if kind == otpChallenge {
+		ch.code = randomCode()
+		s.codes[ch.loginID] = ch
+		if path.Base(r.URL.Path) == "embedded" {
+			writeJSON(w, map[string]any{"code": ch.code})
+			return
+		}
+		writeJSON(w, map[string]any{})
+		return
+	}
+
+	token := testkeys.RandomID()
+	s.links[token] = ch
+	s.lastLinks[ch.loginID] = token
+	if kind == magicLinkChallenge && !crossDevice {
+		writeJSON(w, map[string]any{})
+		return
+	}
+	ch.pendingRef = testkeys.RandomID()
+	s.pending[ch.pendingRef] = nil
+	res := map[string]any{"pendingRef": ch.pendingRef}
+	if kind == enchantedLinkChallenge {
+		res["linkId"] = "1"
+	}
+	writeJSON(w, res)
+}
+
+// complete applies a verified challenge, and returns the user it authenticated
+func (s *Server) complete(ch *challenge) (u *User, firstSeen bool, err *errors.WebError) {
+	if u = s.findUser(ch.loginID); u == nil {
+		if ch.signUp == nil {
+			return nil, false, userNotFoundError
+		}
+		u, firstSeen = s.addUser(ch.signUp), true
+	}
+	if ch.update != nil {
+		ch.update(u)
+	}
+	switch ch.verifies {
+	case "email":
+		u.VerifiedEmail = true
+	case "phone":
+		u.VerifiedPhone = true
+	}
+	if ch.tenantID != "" && !hasTenant(u, ch.tenantID) {
+		u.Tenants = append(u.Tenants, UserTenant{TenantID: ch.tenantID})
+	}
+	if ch.pendingRef != "" {
+		s.pending[ch.pendingRef] = &pendingSession{userID: u.UserID, firstSeen: firstSeen, customClaims: ch.customClaims}
+	}
+	return u, firstSeen, nil
+}
+
+func (s *Server) writeSession(w http.ResponseWriter, u *User, firstSeen bool, customClaims map[string]any, refresh bool) {
+	res := &jwtResponse{CookiePath: "/", User: newUserResponse(u), FirstSeen: firstSeen}
+	var err error
+	if res.SessionJwt, _, err = s.signToken(u, sessionTokenDRN, s.SessionTokenTTL, customClaims); err != nil {
+		writeError(w, http.StatusInternalServerError, errors.NewError("500", err.Error()))
+		return
+	}
+	if refresh {
+		var tokenID string
+		if res.RefreshJwt, tokenID, err = s.signToken(u, refreshTokenDRN, s.RefreshTokenTTL, nil); err != nil {
+			writeError(w, http.StatusInternalServerError, errors.NewError("500", err.Error()))
+			return
+		}
+		s.refreshTokens[tokenID] = u.UserID
+		res.CookieMaxAge = int32(s.RefreshTokenTTL.Seconds())
+		res.CookieExpiration = int32(time.Now().Add(s.RefreshTokenTTL).Unix())
+	}
+	writeJSON(w, res)
+}
+
+func (s *Server) completeAndWriteSession(w http.ResponseWriter, ch *challenge) {
+	u, firstSeen, err := s.complete(ch)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.writeSession(w, u, firstSeen, ch.customClaims, true)
+}
+
+func (s *Server) verifyCode(w http.ResponseWriter, r *http.Request, body *authBody) {
+	ch, ok := s.codes[body.ExternalID]
+	if !ok || subtle.ConstantTimeCompare([]byte(ch.code), []byte(body.Code)) != 1 {
+		writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+		return
+	}
+	delete(s.codes, body.ExternalID)
+	s.completeAndWriteSession(w, ch)
+}
+
+// verifyLink verifies magic links (which return a session) and enchanted links (which only complete the pending reference)
+func (s *Server) verifyLink(session bool) authHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, body *authBody) {
+		ch, ok := s.links[body.Token]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+			return
+		}
+		delete(s.links, body.Token)
+		if session {
+			s.completeAndWriteSession(w, ch)
+			return
+		}
+		if _, _, err := s.complete(ch); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, map[string]any{})
+	}
+}
+
+func (s *Server) pendingSession(w http.ResponseWriter, r *http.Request, body *authBody) {
+	session, ok := s.pending[body.PendingRef]
+	if !ok {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("pendingRef"))
+		return
+	}
+	if session == nil {
+		writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+		return
+	}
+	delete(s.pending, body.PendingRef)
+	u, ok := s.users[session.userID]
+	if !ok {
+		writeError(w, http.StatusBadRequest, userNotFoundError)
+		return
+	}
+	s.writeSession(w, u, session.firstSeen, session.customClaims, true)
+}
+
+func (s *Server) updateEmail(kind challengeKind) authHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, body *authBody) {
+		if _, ok := s.updatedUser(w, r, body); !ok {
+			return
+		}
+		email := body.Email
+		ch := &challenge{loginID: body.ExternalID, verifies: "email", update: func(u *User) { u.Email = email }}
+		s.sendChallenge(kind, ch, body.CrossDevice, w, r)
+	}
+}
+
+func (s *Server) updatePhone(kind challengeKind) authHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, body *authBody) {
+		if _, ok := s.updatedUser(w, r, body); !ok {
+			return
+		}
+		phone := body.Phone
+		ch := &challenge{loginID: body.ExternalID, verifies: "phone", update: func(u *User) { u.Phone = phone }}
+		s.sendChallenge(kind, ch, body.CrossDevice, w, r)
+	}
+}
+
+// updatedUser returns the user of the refresh token in the request, after making sure the login ID in the body is its own
+func (s *Server) updatedUser(w http.ResponseWriter, r *http.Request, body *authBody) (*User, bool) {
+	u, _, ok := s.refreshUser(w, r)
+	if !ok {
+		return nil, false
+	}
+	if s.findUser(body.ExternalID) != u {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("externalId"))
+		return nil, false
+	}
+	return u, true
+}
+
+func (s *Server) signUpPassword(w http.ResponseWriter, r *http.Request, body *authBody) {
+	if body.ExternalID == "" {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("externalId"))
+		return
+	}
+	if s.findUser(body.ExternalID) != nil {
+		writeError(w, http.StatusBadRequest, userAlreadyExistsError)
+		return
+	}
+	if !s.PasswordPolicy.allows(body.Password) {
+		writeError(w, http.StatusBadRequest, passwordPolicyError)
+		return
+	}
+	u := &User{LoginIDs: []string{body.ExternalID}, Password: body.Password}
+	if body.User != nil {
+		u.Name, u.Email, u.Phone = body.User.Name, body.User.Email, body.User.Phone
+	}
+	s.writeSession(w, s.addUser(u), true, nil, true)
+}
+
+func (s *Server) signInPassword(w http.ResponseWriter, r *http.Request, body *authBody) {
+	u := s.findUser(body.ExternalID)
+	if u == nil || u.Password == "" || subtle.ConstantTimeCompare([]byte(u.Password), []byte(body.Password)) != 1 {
+		writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+		return
+	}
+	var customClaims map[string]any
+	if body.LoginOptions != nil {
+		customClaims = body.LoginOptions.CustomClaims
+	}
+	s.writeSession(w, u, false, customClaims, true)
+}
+
+func (s *Server) sendPasswordReset(w http.ResponseWriter, r *http.Request, body *authBody) {
+	if s.findUser(body.ExternalID) == nil {
+		writeError(w, http.StatusBadRequest, userNotFoundError)
+		return
+	}
+	s.sendChallenge(magicLinkChallenge, &challenge{loginID: body.ExternalID}, false, w, r)
+}
+
+func (s *Server) updatePassword(w http.ResponseWriter, r *http.Request, body *authBody) {
+	u, ok := s.updatedUser(w, r, body)
+	if !ok {
+		return
+	}
+	if !s.PasswordPolicy.allows(body.NewPassword) {
+		writeError(w, http.StatusBadRequest, passwordPolicyError)
+		return
+	}
+	u.Password = body.NewPassword
+	writeJSON(w, map[string]any{})
+}
+
+func (s *Server) replacePassword(w http.ResponseWriter, r *http.Request, body *authBody) {
+	u := s.findUser(body.ExternalID)
+	if u == nil || u.Password == "" || subtle.ConstantTimeCompare([]byte(u.Password), []byte(body.OldPassword)) != 1 {
+		writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+		return
+	}
+	if !s.PasswordPolicy.allows(body.NewPassword) {
+		writeError(w, http.StatusBadRequest, passwordPolicyError)
+		return
+	}
+	u.Password = body.NewPassword
+	s.writeSession(w, u, false, nil, true)
+}
+
+func (s *Server) passwordPolicy(w http.ResponseWriter, r *http.Request, body *authBody) {
+	writeJSON(w, s.PasswordPolicy)
+}
+
+// oauthStart skips the provider, and returns a redirect straight back to the redirect URL with an exchange code
+// for the user with the login ID in the loginHint, or a user named after the provider
+func (s *Server) oauthStart(w http.ResponseWriter, r *http.Request, body *authBody) {
+	query := r.URL.Query()
+	provider := query.Get("provider")
+	if provider == "" {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("provider"))
+		return
+	}
+	loginID := firstNonEmpty(query.Get("loginHint"), provider+"-user@example.com")
+	ch := &challenge{loginID: loginID, signUp: &User{LoginIDs: []string{loginID}, Email: loginID}, verifies: "email"}
+	s.startRedirect(w, r, ch)
+}
+
+// samlStart finds the tenant by its ID or name, and returns a redirect straight back to the redirect URL with an
+// exchange code for an SSO user of the tenant
+func (s *Server) samlStart(w http.ResponseWriter, r *http.Request, body *authBody) {
+	query := r.URL.Query()
+	var tenant *Tenant
+	for _, t := range s.tenants {
+		if t.ID == query.Get("tenant") || t.Name == query.Get("tenant") {
+			tenant = t
+		}
+	}
+	if tenant == nil || !tenant.SSOConfigured {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("tenant"))
+		return
+	}
+	domain := tenant.ID + ".example.com"
+	if len(tenant.SelfProvisioningDomains) > 0 {
+		domain = tenant.SelfProvisioningDomains[0]
+	}
+	loginID := "sso-user@" + domain
+	ch := &challenge{loginID: loginID, signUp: &User{LoginIDs: []string{loginID}, Email: loginID}, tenantID: tenant.ID}
+	s.startRedirect(w, r, ch)
+}
+
+func (s *Server) startRedirect(w http.ResponseWriter, r *http.Request, ch *challenge) {
+	query := r.URL.Query()
+	redirectURL, err := url.Parse(query.Get("redirectURL"))
+	if err != nil || query.Get("redirectURL") == "" {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("redirectURL"))
+		return
+	}
+	ch.codeChallenge = query.Get("codeChallenge")
+	code := testkeys.RandomID()
+	s.exchangeCodes[code] = ch
+	params := redirectURL.Query()
+	params.Set("code", code)
+	redirectURL.RawQuery = params.Encode()
+	writeJSON(w, map[string]string{"url": redirectURL.String()})
+}
+
+func (s *Server) exchange(w http.ResponseWriter, r *http.Request, body *authBody) {
+	ch, ok := s.exchangeCodes[body.Code]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+		return
+	}
+	delete(s.exchangeCodes, body.Code)
+	if ch.codeChallenge != "" {
+		sum := sha256.Sum256([]byte(body.CodeVerifier))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != ch.codeChallenge {
+			writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+			return
+		}
+	}
+	s.completeAndWriteSession(w, ch)
+}
+
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request, body *authBody) {
+	if u, _, ok := s.refreshUser(w, r); ok {
+		s.writeSession(w, u, false, nil, false)
+	}
+}
+
+func (s *Server) logout(all bool) authHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, body *authBody) {
+		u, tokenID, ok := s.refreshUser(w, r)
+		if !ok {
+			return
+		}
+		delete(s.refreshTokens, tokenID)
+		if all {
+			for id, userID := range s.refreshTokens {
+				if userID == u.UserID {
+					delete(s.refreshTokens, id)
+				}
+			}
+		}
+		writeJSON(w, map[string]any{})
+	}
+}
+
+func (s *Server) me(w http.ResponseWriter, r *http.Request, body *authBody) {
+	if u, _, ok := s.refreshUser(w, r); ok {
+		writeJSON(w, newUserResponse(u))
+	}
+}
+
+func (s *Server) exchangeAccessKey(w http.ResponseWriter, r *http.Request, body *authBody) {
+	accessKey, _ := s.bearer(r)
+	u, ok := s.users[s.accessKeys[accessKey]]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+		return
+	}
+	s.writeSession(w, u, false, nil, false)
+}
+
+func (p PasswordPolicy) allows(password string) bool {
+	if int32(len(password)) < p.MinLength {
+		return false
+	}
+	has := func(f func(rune) bool) bool { return strings.IndexFunc(password, f) >= 0 }
+	return (!p.Lowercase || has(unicode.IsLower)) &&
+		(!p.Uppercase || has(unicode.IsUpper)) &&
+		(!p.Number || has(unicode.IsDigit)) &&
+		(!p.NonAlphanumeric || has(func(c rune) bool { return !unicode.IsLetter(c) && !unicode.IsDigit(c) }))
+}
+
+// verifiedContact returns the user contact detail a delivery method verifies
+func verifiedContact(method string) string {
+	switch method {
+	case "email":
+		return "email"
+	case "sms", "whatsapp", "voice":
+		return "phone"
+	}
+	return ""
+}
+
+func newUserResponse(u *User) *userResponse {
+	return &userResponse{
+		UserID:        u.UserID,
+		ExternalIDs:   u.LoginIDs,
+		Name:          u.Name,
+		Email:         u.Email,
+		Phone:         u.Phone,
+		VerifiedEmail: u.VerifiedEmail,
+		VerifiedPhone: u.VerifiedPhone,
+	}
+}
+
+func hasTenant(u *User, tenantID string) bool {
+	for _, t := range u.Tenants {
+		if t.TenantID == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
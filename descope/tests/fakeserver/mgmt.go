@@ -0,0 +1,265 @@
+package fakeserver
+
+import (
+	"net/http"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/internal/testkeys"
+)
+
+var tenantNotFoundError = errors.NewError(errors.BadRequestErrorCode, "tenant not found")
+
+type userTenantBody struct {
+	TenantID  string   `json:"tenantId"`
+	RoleNames []string `json:"roleNames"`
+}
+
+// mgmtBody - the union of the request bodies of the management routes
+type mgmtBody struct {
+	ID                      string           `json:"id"`
+	Name                    string           `json:"name"`
+	SelfProvisioningDomains []string         `json:"selfProvisioningDomains"`
+	Identifier              string           `json:"identifier"`
+	Email                   string           `json:"email"`
+	PhoneNumber             string           `json:"phoneNumber"`
+	DisplayName             string           `json:"displayName"`
+	RoleNames               []string         `json:"roleNames"`
+	UserTenants             []userTenantBody `json:"userTenants"`
+	Test                    bool             `json:"test"`
+	CustomClaims            map[string]any   `json:"customClaims"`
+	DeliveryMethod          string           `json:"deliveryMethod"`
+	URI                     string           `json:"URI"`
+	TenantID                string           `json:"tenantId"`
+	Enabled                 bool             `json:"enabled"`
+}
+
+type mgmtHandlerFunc func(w http.ResponseWriter, r *http.Request, body *mgmtBody)
+
+func (s *Server) mgmtRoutes(handle func(string, func(http.ResponseWriter, *http.Request))) {
+	handle(api.Routes.ManagementTenantCreate(), s.mgmtHandler(s.createTenant))
+	handle(api.Routes.ManagementTenantUpdate(), s.mgmtHandler(s.updateTenant))
+	handle(api.Routes.ManagementTenantDelete(), s.mgmtHandler(s.deleteTenant))
+	handle(api.Routes.ManagementTenantLoadAll(), s.mgmtHandler(s.loadAllTenants))
+
+	handle(api.Routes.ManagementUserCreate(), s.mgmtHandler(s.createUser))
+	handle(api.Routes.ManagementUserUpdate(), s.mgmtHandler(s.updateUser))
+	handle(api.Routes.ManagementUserDelete(), s.mgmtHandler(s.deleteUser))
+	handle(api.Routes.ManagementUserDeleteAllTestUsers(), s.mgmtHandler(s.deleteAllTestUsers))
+	handle(api.Routes.ManagementUserGenerateEmbeddedLink(), s.mgmtHandler(s.generateEmbeddedLink))
+	handle(api.Routes.ManagementUserGenerateOTPForTest(), s.mgmtHandler(s.generateOTPForTestUser))
+	handle(api.Routes.ManagementUserGenerateMagicLinkForTest(), s.mgmtHandler(s.generateMagicLinkForTestUser))
+
+	// the SSO settings are configured and loaded with the same route
+	handle(api.Routes.ManagementSSOConfigure(), s.mgmtHandler(s.ssoSettings))
+	handle(api.Routes.ManagementSSOMetadata(), s.mgmtHandler(s.configureSSO))
+	handle(api.Routes.ManagementSSORoleMapping(), s.mgmtHandler(s.configureSSORoleMapping))
+}
+
+// mgmtHandler validates the management key, locks the server and decodes the request body for the given handler
+func (s *Server) mgmtHandler(handler mgmtHandlerFunc) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if key, ok := s.bearer(r); !ok || key != s.ManagementKey {
+			writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+			return
+		}
+		body := &mgmtBody{}
+		if err := readBody(r, body); err != nil {
+			writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("body"))
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		handler(w, r, body)
+	}
+}
+
+func (s *Server) createTenant(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	if body.Name == "" {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("name"))
+		return
+	}
+	id := body.ID
+	if id == "" {
+		id = "T" + testkeys.RandomID()
+	}
+	if _, ok := s.tenants[id]; ok {
+		writeError(w, http.StatusBadRequest, errors.NewError(errors.BadRequestErrorCode, "tenant already exists"))
+		return
+	}
+	s.tenants[id] = &Tenant{ID: id, Name: body.Name, SelfProvisioningDomains: body.SelfProvisioningDomains}
+	writeJSON(w, map[string]string{"id": id})
+}
+
+func (s *Server) updateTenant(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	t, ok := s.tenants[body.ID]
+	if !ok {
+		writeError(w, http.StatusBadRequest, tenantNotFoundError)
+		return
+	}
+	t.Name, t.SelfProvisioningDomains = body.Name, body.SelfProvisioningDomains
+	writeJSON(w, map[string]any{})
+}
+
+func (s *Server) deleteTenant(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	if _, ok := s.tenants[body.ID]; !ok {
+		writeError(w, http.StatusBadRequest, tenantNotFoundError)
+		return
+	}
+	delete(s.tenants, body.ID)
+	for _, u := range s.users {
+		tenants := u.Tenants[:0]
+		for _, t := range u.Tenants {
+			if t.TenantID != body.ID {
+				tenants = append(tenants, t)
+			}
+		}
+		u.Tenants = tenants
+	}
+	writeJSON(w, map[string]any{})
+}
+
+func (s *Server) loadAllTenants(w http.ResponseWriter, _ *http.Request, _ *mgmtBody) {
+	tenants := []map[string]any{}
+	for _, t := range s.tenants {
+		tenants = append(tenants, map[string]any{"id": t.ID, "name": t.Name, "selfProvisioningDomains": t.SelfProvisioningDomains})
+	}
+	writeJSON(w, map[string]any{"tenants": tenants})
+}
+
+func (s *Server) createUser(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	if body.Identifier == "" {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("identifier"))
+		return
+	}
+	if s.findUser(body.Identifier) != nil {
+		writeError(w, http.StatusBadRequest, userAlreadyExistsError)
+		return
+	}
+	u := &User{LoginIDs: []string{body.Identifier}, Test: body.Test}
+	if !s.setUser(w, u, body) {
+		return
+	}
+	s.addUser(u)
+	writeJSON(w, map[string]any{"user": newUserResponse(u)})
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	u := s.findUser(body.Identifier)
+	if u == nil {
+		writeError(w, http.StatusBadRequest, userNotFoundError)
+		return
+	}
+	if s.setUser(w, u, body) {
+		writeJSON(w, map[string]any{"user": newUserResponse(u)})
+	}
+}
+
+// setUser applies the user details in the body, or writes an error when one of its tenants does not exist
+func (s *Server) setUser(w http.ResponseWriter, u *User, body *mgmtBody) bool {
+	tenants := []UserTenant{}
+	for _, t := range body.UserTenants {
+		if _, ok := s.tenants[t.TenantID]; !ok {
+			writeError(w, http.StatusBadRequest, tenantNotFoundError)
+			return false
+		}
+		tenants = append(tenants, UserTenant{TenantID: t.TenantID, Roles: t.RoleNames})
+	}
+	u.Name, u.Email, u.Phone, u.Roles, u.Tenants = body.DisplayName, body.Email, body.PhoneNumber, body.RoleNames, tenants
+	return true
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	u := s.findUser(body.Identifier)
+	if u == nil {
+		writeError(w, http.StatusBadRequest, userNotFoundError)
+		return
+	}
+	delete(s.users, u.UserID)
+	writeJSON(w, map[string]any{})
+}
+
+func (s *Server) deleteAllTestUsers(w http.ResponseWriter, r *http.Request, _ *mgmtBody) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, errors.NewError(errors.BadRequestErrorCode, "method not allowed"))
+		return
+	}
+	for id, u := range s.users {
+		if u.Test {
+			delete(s.users, id)
+		}
+	}
+	writeJSON(w, map[string]any{})
+}
+
+func (s *Server) generateEmbeddedLink(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	if s.findUser(body.Identifier) == nil {
+		writeError(w, http.StatusBadRequest, userNotFoundError)
+		return
+	}
+	token := testkeys.RandomID()
+	s.links[token] = &challenge{loginID: body.Identifier, customClaims: body.CustomClaims}
+	writeJSON(w, map[string]string{"token": token})
+}
+
+func (s *Server) generateOTPForTestUser(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	if !s.isTestUser(w, body.Identifier) {
+		return
+	}
+	ch := &challenge{loginID: body.Identifier, code: randomCode(), verifies: verifiedContact(body.DeliveryMethod)}
+	s.codes[body.Identifier] = ch
+	writeJSON(w, map[string]string{"code": ch.code})
+}
+
+func (s *Server) generateMagicLinkForTestUser(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	if !s.isTestUser(w, body.Identifier) {
+		return
+	}
+	token := testkeys.RandomID()
+	s.links[token] = &challenge{loginID: body.Identifier, verifies: verifiedContact(body.DeliveryMethod)}
+	writeJSON(w, map[string]string{"link": body.URI + "?t=" + token})
+}
+
+func (s *Server) isTestUser(w http.ResponseWriter, identifier string) bool {
+	u := s.findUser(identifier)
+	if u == nil || !u.Test {
+		writeError(w, http.StatusBadRequest, errors.NewError(errors.BadRequestErrorCode, "test user not found"))
+		return false
+	}
+	return true
+}
+
+func (s *Server) ssoSettings(w http.ResponseWriter, r *http.Request, body *mgmtBody) {
+	if r.Method == http.MethodGet {
+		s.loadSSOSettings(w, r)
+		return
+	}
+	s.configureSSO(w, r, body)
+}
+
+func (s *Server) loadSSOSettings(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.tenants[r.URL.Query().Get("tenantId")]
+	if !ok {
+		writeError(w, http.StatusBadRequest, tenantNotFoundError)
+		return
+	}
+	writeJSON(w, map[string]any{"tenantId": t.ID, "enabled": t.SSOConfigured})
+}
+
+func (s *Server) configureSSO(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	t, ok := s.tenants[body.TenantID]
+	if !ok {
+		writeError(w, http.StatusBadRequest, tenantNotFoundError)
+		return
+	}
+	t.SSOConfigured = body.Enabled
+	writeJSON(w, map[string]any{})
+}
+
+func (s *Server) configureSSORoleMapping(w http.ResponseWriter, _ *http.Request, body *mgmtBody) {
+	if _, ok := s.tenants[body.TenantID]; !ok {
+		writeError(w, http.StatusBadRequest, tenantNotFoundError)
+		return
+	}
+	writeJSON(w, map[string]any{})
+}
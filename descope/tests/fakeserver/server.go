@@ -0,0 +1,370 @@
+// Package fakeserver provides an in-process fake of the Descope API, to be used by integration tests that exercise
+// full authentication and management flows without network access.
+//
+// The server implements the routes in api.Routes, keeps users and tenants in memory, signs real session and refresh
+// JWTs with a key generated on start (and served from the keys route), and exposes the OTP codes and magic link tokens
+// it "sent" and the current TOTP codes of the users, so tests can complete flows the way a user would. WebAuthn
+// ceremonies are completed with any authenticator response, which the server does not verify. Use it by setting its URL as the DescopeBaseURL in the client
+// configuration, with the server ProjectID as the project ID.
+package fakeserver
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/internal/testkeys"
+)
+
+const (
+	defaultManagementKey   = "fake-management-key"
+	defaultSessionTokenTTL = 10 * time.Minute
+	defaultRefreshTokenTTL = 24 * time.Hour
+
+	sessionTokenDRN = "DS"
+	refreshTokenDRN = "DSR"
+)
+
+// User - a user stored by the fake server
+type User struct {
+	UserID          string
+	LoginIDs        []string
+	Name            string
+	Email           string
+	Phone           string
+	VerifiedEmail   bool
+	VerifiedPhone   bool
+	Roles           []string
+	Tenants         []UserTenant
+	Test            bool
+	Password        string
+	TOTPKey         string // the base32 key of the authenticator app, see Server.TOTPCode
+	WebAuthnDevices int    // the number of devices registered with WebAuthn
+}
+
+// UserTenant - the roles of a user in a tenant
+type UserTenant struct {
+	TenantID string
+	Roles    []string
+}
+
+// Tenant - a tenant stored by the fake server
+type Tenant struct {
+	ID                      string
+	Name                    string
+	SelfProvisioningDomains []string
+	SSOConfigured           bool
+}
+
+// Server - an in-process fake Descope server, see the package documentation.
+type Server struct {
+	// ProjectID - the project the server accepts requests for and issues tokens for
+	ProjectID string
+	// ManagementKey - the management key the server accepts on management requests
+	ManagementKey string
+	// SessionTokenTTL - the lifetime of issued session tokens
+	SessionTokenTTL time.Duration
+	// RefreshTokenTTL - the lifetime of issued refresh tokens
+	RefreshTokenTTL time.Duration
+	// RolePermissions (optional, nil) - the permissions added to the tokens of users with each role
+	RolePermissions map[string][]string
+	// PasswordPolicy - returned by the password policy route, and enforced on new passwords
+	PasswordPolicy PasswordPolicy
+
+	server *httptest.Server
+	keys   *testkeys.KeyPair
+
+	mu                   sync.Mutex
+	users                map[string]*User // by user ID
+	tenants              map[string]*Tenant
+	codes                map[string]*challenge           // pending OTP codes by login ID
+	links                map[string]*challenge           // pending magic link, enchanted link and embedded link tokens
+	lastLinks            map[string]string               // the last link token sent to each login ID
+	exchangeCodes        map[string]*challenge           // pending OAuth and SAML exchange codes
+	pending              map[string]*pendingSession      // the session of each verified pending reference, or nil while still pending
+	refreshTokens        map[string]string               // the user ID of each refresh token ID that was not logged out
+	accessKeys           map[string]string               // the user ID of each access key
+	webAuthnTransactions map[string]*webAuthnTransaction // started WebAuthn ceremonies by transaction ID
+}
+
+// PasswordPolicy - the password requirements of the fake server project
+type PasswordPolicy struct {
+	MinLength       int32 `json:"minLength,omitempty"`
+	Lowercase       bool  `json:"lowercase,omitempty"`
+	Uppercase       bool  `json:"uppercase,omitempty"`
+	Number          bool  `json:"number,omitempty"`
+	NonAlphanumeric bool  `json:"nonAlphanumeric,omitempty"`
+}
+
+// challenge - a pending verification, completing it signs in the user with the login ID, or signs up the given user
+type challenge struct {
+	loginID       string
+	code          string
+	signUp        *User
+	update        func(user *User)
+	verifies      string // "email" or "phone", marked as verified once completed
+	pendingRef    string
+	customClaims  map[string]any
+	codeChallenge string
+	tenantID      string
+}
+
+// New - starts a new fake server for the given project, call Close to shut it down.
+func New(projectID string) (*Server, error) {
+	if projectID == "" {
+		return nil, errors.NewInvalidArgumentError("projectID")
+	}
+	keys, err := testkeys.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		ProjectID:            projectID,
+		ManagementKey:        defaultManagementKey,
+		SessionTokenTTL:      defaultSessionTokenTTL,
+		RefreshTokenTTL:      defaultRefreshTokenTTL,
+		PasswordPolicy:       PasswordPolicy{MinLength: 6},
+		keys:                 keys,
+		users:                map[string]*User{},
+		tenants:              map[string]*Tenant{},
+		codes:                map[string]*challenge{},
+		links:                map[string]*challenge{},
+		lastLinks:            map[string]string{},
+		exchangeCodes:        map[string]*challenge{},
+		pending:              map[string]*pendingSession{},
+		refreshTokens:        map[string]string{},
+		accessKeys:           map[string]string{},
+		webAuthnTransactions: map[string]*webAuthnTransaction{},
+	}
+	s.server = httptest.NewServer(s.routes())
+	return s, nil
+}
+
+// URL - the base URL of the server, to be used as the DescopeBaseURL
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close - shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// PublicKey - the public key the server signs tokens with, as JSON, which can be used as a provided public key
+func (s *Server) PublicKey() string {
+	return s.keys.PublicKeyJSON()
+}
+
+// OTPCode - returns the last OTP code sent to the given login ID that was not verified yet
+func (s *Server) OTPCode(loginID string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.codes[loginID]; ok {
+		return c.code, true
+	}
+	return "", false
+}
+
+// MagicLinkToken - returns the token of the last magic link, enchanted link or password reset link sent to the given
+// login ID that was not verified yet
+func (s *Server) MagicLinkToken(loginID string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.lastLinks[loginID]
+	if _, pending := s.links[token]; !ok || !pending {
+		return "", false
+	}
+	return token, true
+}
+
+// User - returns a copy of the user with the given login ID
+func (s *Server) User(loginID string) (User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.findUser(loginID); u != nil {
+		return *u, true
+	}
+	return User{}, false
+}
+
+// Tenant - returns a copy of the tenant with the given ID
+func (s *Server) Tenant(id string) (Tenant, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.tenants[id]; ok {
+		return *t, true
+	}
+	return Tenant{}, false
+}
+
+// CreateAccessKey - creates an access key for the user with the given login ID, which can be exchanged for a session token
+func (s *Server) CreateAccessKey(loginID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.findUser(loginID)
+	if u == nil {
+		return "", errors.NewInvalidArgumentError("loginID")
+	}
+	key := testkeys.RandomID()
+	s.accessKeys[key] = u.UserID
+	return key, nil
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	handle := func(route string, handler func(w http.ResponseWriter, r *http.Request)) {
+		mux.HandleFunc(route, handler)
+	}
+	// routes that end with a delivery method or a project ID
+	handleSubtree := func(route string, handler func(w http.ResponseWriter, r *http.Request)) {
+		mux.HandleFunc(route+"/", handler)
+	}
+
+	handleSubtree(api.Routes.GetKeys(), s.handleKeys)
+	s.authRoutes(handle, handleSubtree)
+	s.mgmtRoutes(handle)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern == "" {
+			writeError(w, http.StatusNotFound, errors.NewError("404", fmt.Sprintf("route [%s] is not implemented by the fake server", r.URL.Path)))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleKeys(w http.ResponseWriter, r *http.Request) {
+	if path.Base(r.URL.Path) != s.ProjectID {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("project"))
+		return
+	}
+	writeJSON(w, []jwk.Key{s.keys.Public})
+}
+
+// bearer returns the part of the authorization header that follows the project ID, or false
+// when the project ID does not match the server project.
+func (s *Server) bearer(r *http.Request) (string, bool) {
+	value := strings.TrimPrefix(r.Header.Get(api.AuthorizationHeaderName), api.BearerAuthorizationPrefix)
+	projectID, pswd, _ := strings.Cut(value, ":")
+	return pswd, projectID == s.ProjectID
+}
+
+// findUser must be called with the lock held
+func (s *Server) findUser(loginID string) *User {
+	for _, u := range s.users {
+		for _, id := range u.LoginIDs {
+			if id == loginID {
+				return u
+			}
+		}
+	}
+	return nil
+}
+
+// addUser must be called with the lock held
+func (s *Server) addUser(u *User) *User {
+	if u.UserID == "" {
+		u.UserID = "U" + testkeys.RandomID()
+	}
+	s.users[u.UserID] = u
+	return u
+}
+
+// signToken must be called with the lock held
+func (s *Server) signToken(u *User, drn string, ttl time.Duration, customClaims map[string]any) (string, string, error) {
+	now := time.Now()
+	tokenID := testkeys.RandomID()
+	claims := map[string]any{
+		jwt.JwtIDKey:      tokenID,
+		jwt.SubjectKey:    u.UserID,
+		jwt.IssuerKey:     s.ProjectID,
+		jwt.AudienceKey:   []string{s.ProjectID},
+		jwt.IssuedAtKey:   now,
+		jwt.ExpirationKey: now.Add(ttl),
+		"drn":             drn,
+	}
+	if drn == sessionTokenDRN {
+		claims["roles"] = u.Roles
+		claims["permissions"] = s.permissions(u.Roles)
+		tenants := map[string]any{}
+		for _, t := range u.Tenants {
+			tenants[t.TenantID] = map[string]any{"roles": t.Roles, "permissions": s.permissions(t.Roles)}
+		}
+		claims["tenants"] = tenants
+		for k, v := range customClaims {
+			claims[k] = v
+		}
+	}
+	signed, err := s.keys.Sign(claims)
+	if err != nil {
+		return "", "", err
+	}
+	return signed, tokenID, nil
+}
+
+func (s *Server) permissions(roles []string) []string {
+	permissions := []string{}
+	for _, role := range roles {
+		permissions = append(permissions, s.RolePermissions[role]...)
+	}
+	return permissions
+}
+
+// validateRefreshToken must be called with the lock held
+func (s *Server) validateRefreshToken(refreshJwt string) (*User, string, bool) {
+	token, err := jwt.Parse([]byte(refreshJwt), jwt.WithKey(jwa.ES256, s.keys.Public), jwt.WithValidate(true))
+	if err != nil {
+		return nil, "", false
+	}
+	if drn, _ := token.Get("drn"); drn != refreshTokenDRN {
+		return nil, "", false
+	}
+	userID, ok := s.refreshTokens[token.JwtID()]
+	if !ok || userID != token.Subject() {
+		return nil, "", false
+	}
+	u, ok := s.users[userID]
+	return u, token.JwtID(), ok
+}
+
+func readBody(r *http.Request, v any) error {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err *errors.WebError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(err)
+}
+
+func randomCode() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%06d", (int(b[0])<<16|int(b[1])<<8|int(b[2]))%1000000)
+}
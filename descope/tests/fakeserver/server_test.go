@@ -0,0 +1,356 @@
+package fakeserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/descope/go-sdk/descope"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/mgmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*Server, *descope.DescopeClient) {
+	s, err := New("P2fake")
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+	return newTestClient(t, s)
+}
+
+func newTestClient(t *testing.T, s *Server) (*Server, *descope.DescopeClient) {
+	client, err := descope.NewDescopeClientWithConfig(&descope.Config{ProjectID: s.ProjectID, DescopeBaseURL: s.URL(), ManagementKey: s.ManagementKey})
+	require.NoError(t, err)
+	return s, client
+}
+
+// requestWithCookies returns a request carrying the cookies the recorder received
+func requestWithCookies(w *httptest.ResponseRecorder) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range w.Result().Cookies() {
+		r.AddCookie(c)
+	}
+	return r
+}
+
+func TestOTPFlow(t *testing.T) {
+	s, client := newTestServer(t)
+	email := "dude@example.com"
+	require.NoError(t, client.Auth.OTP().SignUp(auth.MethodEmail, email, &auth.User{Name: "Dude"}))
+	_, ok := s.User(email)
+	assert.False(t, ok, "users are created once verified")
+
+	code, ok := s.OTPCode(email)
+	require.True(t, ok)
+	_, err := client.Auth.OTP().VerifyCode(auth.MethodEmail, email, "wrong", nil)
+	require.Error(t, err)
+	w := httptest.NewRecorder()
+	info, err := client.Auth.OTP().VerifyCode("", email, code, w)
+	require.NoError(t, err)
+	assert.True(t, info.FirstSeen)
+	assert.EqualValues(t, "Dude", info.User.Name)
+	assert.True(t, info.User.VerifiedEmail)
+	_, ok = s.OTPCode(email)
+	assert.False(t, ok)
+
+	r := requestWithCookies(w)
+	ok, token, err := client.Auth.ValidateSession(r, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.EqualValues(t, info.User.UserID, token.ID)
+
+	ok, refreshed, err := client.Auth.RefreshSession(r, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.EqualValues(t, token.ID, refreshed.ID)
+
+	me, err := client.Auth.Me(r)
+	require.NoError(t, err)
+	assert.EqualValues(t, email, me.Email)
+
+	require.NoError(t, client.Auth.Logout(r, nil))
+	_, err = client.Auth.Me(r)
+	require.Error(t, err)
+
+	require.NoError(t, client.Auth.OTP().SignIn(auth.MethodEmail, email, nil, nil))
+	code, _ = s.OTPCode(email)
+	info, err = client.Auth.OTP().VerifyCode(auth.MethodEmail, email, code, nil)
+	require.NoError(t, err)
+	assert.False(t, info.FirstSeen)
+
+	require.Error(t, client.Auth.OTP().SignIn(auth.MethodEmail, "nobody@example.com", nil, nil))
+	require.Error(t, client.Auth.OTP().SignUp(auth.MethodEmail, email, nil))
+}
+
+func TestOTPEmbeddedFlow(t *testing.T) {
+	_, client := newTestServer(t)
+	code, err := client.Auth.OTP().SignUpOrInEmbedded("dude")
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	info, err := client.Auth.OTP().VerifyCode(auth.MethodEmbedded, "dude", code, nil)
+	require.NoError(t, err)
+	assert.True(t, info.FirstSeen)
+
+	code, err = client.Auth.OTP().SignInEmbedded("dude", nil, nil)
+	require.NoError(t, err)
+	info, err = client.Auth.OTP().VerifyCode(auth.MethodEmbedded, "dude", code, nil)
+	require.NoError(t, err)
+	assert.False(t, info.FirstSeen)
+}
+
+func TestMagicLinkCrossDeviceFlow(t *testing.T) {
+	s, client := newTestServer(t)
+	phone := "+14155550100"
+	res, err := client.Auth.MagicLink().SignUpOrInCrossDevice(auth.MethodSMS, phone, "https://example.com/verify")
+	require.NoError(t, err)
+	_, err = client.Auth.MagicLink().GetSession(res.PendingRef, nil)
+	assert.ErrorIs(t, err, errors.MagicLinkUnauthorized)
+
+	token, ok := s.MagicLinkToken(phone)
+	require.True(t, ok)
+	_, err = client.Auth.MagicLink().Verify(token, nil)
+	require.NoError(t, err)
+	_, ok = s.MagicLinkToken(phone)
+	assert.False(t, ok)
+
+	info, err := client.Auth.MagicLink().WaitForSession(context.Background(), res.PendingRef, &auth.WaitForSessionOptions{Interval: time.Millisecond})
+	require.NoError(t, err)
+	assert.True(t, info.FirstSeen)
+	assert.True(t, info.User.VerifiedPhone)
+	assert.EqualValues(t, phone, info.User.Phone)
+}
+
+func TestEnchantedLinkFlow(t *testing.T) {
+	s, client := newTestServer(t)
+	email := "dude@example.com"
+	res, err := client.Auth.EnchantedLink().SignUp(email, "https://example.com/verify", nil)
+	require.NoError(t, err)
+	token, ok := s.MagicLinkToken(email)
+	require.True(t, ok)
+	require.NoError(t, client.Auth.EnchantedLink().Verify(token))
+	info, err := client.Auth.EnchantedLink().GetSession(res.PendingRef, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, email, info.User.Email)
+}
+
+func TestPasswordFlow(t *testing.T) {
+	s, client := newTestServer(t)
+	s.PasswordPolicy = PasswordPolicy{MinLength: 8, Number: true}
+	policy, err := client.Auth.Password().GetPasswordPolicy()
+	require.NoError(t, err)
+	assert.EqualValues(t, 8, policy.MinLength)
+	assert.True(t, policy.Number)
+
+	_, err = client.Auth.Password().SignUp("dude", nil, "short1", nil)
+	require.Error(t, err)
+	_, err = client.Auth.Password().SignUp("dude", &auth.User{Email: "dude@example.com"}, "password1", nil)
+	require.NoError(t, err)
+	_, err = client.Auth.Password().SignIn("dude", "password2", nil, nil, nil)
+	require.Error(t, err)
+	_, err = client.Auth.Password().ReplaceUserPassword("dude", "password1", "password2", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	_, err = client.Auth.Password().SignIn("dude", "password2", nil, nil, w)
+	require.NoError(t, err)
+	require.NoError(t, client.Auth.Password().UpdateUserPassword("dude", "password3", requestWithCookies(w)))
+	u, _ := s.User("dude")
+	assert.EqualValues(t, "password3", u.Password)
+
+	require.NoError(t, client.Auth.Password().SendPasswordReset("dude", "https://example.com/reset"))
+	token, ok := s.MagicLinkToken("dude")
+	require.True(t, ok)
+	_, err = client.Auth.MagicLink().Verify(token, nil)
+	require.NoError(t, err)
+}
+
+func TestOAuthFlow(t *testing.T) {
+	_, client := newTestServer(t)
+	w := httptest.NewRecorder()
+	location, err := client.Auth.OAuth().StartWithOptions(auth.OAuthGoogle, "https://example.com/callback", nil, nil, &auth.OAuthOptions{LoginHint: "dude@gmail.com", BindState: true}, w)
+	require.NoError(t, err)
+	parsed, err := url.Parse(location)
+	require.NoError(t, err)
+	// the code of a bound flow cannot be exchanged without its verifier
+	_, err = client.Auth.OAuth().ExchangeToken(parsed.Query().Get("code"), nil)
+	require.Error(t, err)
+
+	w = httptest.NewRecorder()
+	location, err = client.Auth.OAuth().StartWithOptions(auth.OAuthGoogle, "https://example.com/callback", nil, nil, &auth.OAuthOptions{LoginHint: "dude@gmail.com", BindState: true}, w)
+	require.NoError(t, err)
+	callback := requestWithCookies(w)
+	callback.URL, err = url.Parse(location)
+	require.NoError(t, err)
+	info, err := client.Auth.OAuth().ExchangeTokenFromRequest(callback, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, "dude@gmail.com", info.User.Email)
+
+	// flows that are not bound are exchanged with the code alone
+	location, err = client.Auth.OAuth().Start(auth.OAuthGoogle, "https://example.com/callback", nil, nil, nil)
+	require.NoError(t, err)
+	parsed, err = url.Parse(location)
+	require.NoError(t, err)
+	_, err = client.Auth.OAuth().ExchangeToken(parsed.Query().Get("code"), nil)
+	require.NoError(t, err)
+}
+
+func TestManagementFlow(t *testing.T) {
+	s, client := newTestServer(t)
+	s.RolePermissions = map[string][]string{"admin": {"users.write"}}
+	key := s.ManagementKey
+	m := client.Management
+
+	tenantID, err := m.Tenant().Create(key, "Acme", []string{"acme.com"})
+	require.NoError(t, err)
+	_, ok := s.Tenant(tenantID)
+	require.True(t, ok)
+	require.Error(t, m.Tenant().CreateWithID(key, tenantID, "Acme", nil))
+
+	require.NoError(t, m.User().CreateTestUser(key, "dude@acme.com", "dude@acme.com", "", "Dude", []string{"admin"}, []mgmt.UserTenants{{TenantID: tenantID, Roles: []string{"admin"}}}))
+	require.Error(t, m.User().Create(key, "other", "", "", "", nil, []mgmt.UserTenants{{TenantID: "missing"}}))
+
+	code, err := m.User().GenerateOTPForTestUser(key, auth.MethodEmail, "dude@acme.com")
+	require.NoError(t, err)
+	info, err := client.Auth.OTP().VerifyCode(auth.MethodEmail, "dude@acme.com", code, nil)
+	require.NoError(t, err)
+	assert.True(t, client.Auth.ValidatePermissions(info.SessionToken, []string{"users.write"}))
+	assert.True(t, client.Auth.ValidateTenantRoles(info.SessionToken, tenantID, []string{"admin"}))
+
+	link, err := m.User().GenerateMagicLinkForTestUser(key, auth.MethodEmail, "dude@acme.com", "https://example.com/verify")
+	require.NoError(t, err)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	_, err = client.Auth.MagicLink().Verify(parsed.Query().Get("t"), nil)
+	require.NoError(t, err)
+
+	token, err := m.User().GenerateEmbeddedLink(key, "dude@acme.com", map[string]any{"plan": "pro"})
+	require.NoError(t, err)
+	info, err = client.Auth.MagicLink().Verify(token, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, "pro", info.SessionToken.Claims["plan"])
+
+	_, err = client.Auth.SAML().StartWithEmail("dude@unknown.com", "https://example.com/callback", nil, nil, nil, nil)
+	assert.ErrorIs(t, err, errors.SSOTenantNotFoundError)
+	// the tenant of the domain does not have SSO configured
+	_, err = client.Auth.SAML().StartWithEmail("dude@acme.com", "https://example.com/callback", nil, nil, nil, nil)
+	assert.ErrorIs(t, err, errors.SSOTenantNotFoundError)
+	require.NoError(t, m.SSO().ConfigureMetadata(key, tenantID, true, "https://idp.example.com/metadata"))
+	settings, err := m.SSO().LoadSettings(key, tenantID)
+	require.NoError(t, err)
+	assert.True(t, settings.Enabled)
+	// the SSO-enabled tenants are cached by the client, a new client loads them again
+	_, client = newTestClient(t, s)
+	location, err := client.Auth.SAML().StartWithEmail("dude@acme.com", "https://example.com/callback", nil, nil, nil, nil)
+	require.NoError(t, err)
+	parsed, err = url.Parse(location)
+	require.NoError(t, err)
+	info, err = client.Auth.SAML().ExchangeToken(parsed.Query().Get("code"), nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{tenantID}, info.SessionToken.GetTenants())
+
+	require.NoError(t, m.User().DeleteAllTestUsers(key))
+	_, ok = s.User("dude@acme.com")
+	assert.False(t, ok)
+
+	require.NoError(t, m.Tenant().Delete(key, tenantID))
+	require.Error(t, m.Tenant().Delete(key, tenantID))
+	require.Error(t, m.Tenant().Delete("wrong-key", tenantID))
+}
+
+func TestAccessKeyAndProvidedPublicKey(t *testing.T) {
+	s, _ := newTestServer(t)
+	client, err := descope.NewDescopeClientWithConfig(&descope.Config{ProjectID: s.ProjectID, DescopeBaseURL: s.URL(), PublicKey: s.PublicKey()})
+	require.NoError(t, err)
+	require.NoError(t, client.Management.User().Create(s.ManagementKey, "service", "", "", "", nil, nil))
+	accessKey, err := s.CreateAccessKey("service")
+	require.NoError(t, err)
+
+	ok, token, err := client.Auth.ExchangeAccessKey(accessKey)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, _, err = client.Auth.ValidateSessionTokens(token.JWT, "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, _, _ = client.Auth.ExchangeAccessKey("wrong")
+	assert.False(t, ok)
+}
+
+func TestTOTPFlow(t *testing.T) {
+	s, client := newTestServer(t)
+	res, err := client.Auth.TOTP().SignUp("dude", &auth.User{Name: "Dude"})
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Key)
+	assert.Contains(t, res.ProvisioningURL, res.Key)
+	_, err = client.Auth.TOTP().SignUp("dude", nil)
+	require.Error(t, err)
+
+	_, err = client.Auth.TOTP().SignInCode("dude", "000000x", nil, nil, nil)
+	require.Error(t, err)
+	code, ok := s.TOTPCode("dude")
+	require.True(t, ok)
+	w := httptest.NewRecorder()
+	info, err := client.Auth.TOTP().SignInCode("dude", code, nil, nil, w)
+	require.NoError(t, err)
+	assert.EqualValues(t, "Dude", info.User.Name)
+
+	updated, err := client.Auth.TOTP().UpdateUser("dude", requestWithCookies(w))
+	require.NoError(t, err)
+	assert.NotEqual(t, res.Key, updated.Key)
+	u, _ := s.User("dude")
+	assert.EqualValues(t, updated.Key, u.TOTPKey)
+}
+
+func TestTOTPCode(t *testing.T) {
+	// the test vector of RFC 6238 for SHA1, truncated to 6 digits
+	key := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890"
+	assert.EqualValues(t, "287082", totpCode(key, time.Unix(59, 0)))
+	assert.EqualValues(t, "081804", totpCode(key, time.Unix(1111111109, 0)))
+}
+
+func TestWebAuthnFlow(t *testing.T) {
+	s, client := newTestServer(t)
+	_, err := client.Auth.WebAuthn().SignInStart("dude", "https://example.com", nil, nil)
+	require.Error(t, err)
+
+	tx, err := client.Auth.WebAuthn().SignUpOrInStart("dude", "https://example.com")
+	require.NoError(t, err)
+	assert.True(t, tx.Create)
+	assert.Contains(t, tx.Options, "https://example.com")
+	_, err = client.Auth.WebAuthn().SignInFinish(&auth.WebAuthnFinishRequest{TransactionID: tx.TransactionID, Response: "{}"}, nil)
+	require.Error(t, err, "a sign up transaction cannot be finished as a sign in")
+	w := httptest.NewRecorder()
+	info, err := client.Auth.WebAuthn().SignUpFinish(&auth.WebAuthnFinishRequest{TransactionID: tx.TransactionID, Response: "{}"}, w)
+	require.NoError(t, err)
+	assert.True(t, info.FirstSeen)
+
+	tx, err = client.Auth.WebAuthn().UpdateUserDeviceStart("dude", "https://example.com", requestWithCookies(w))
+	require.NoError(t, err)
+	require.NoError(t, client.Auth.WebAuthn().UpdateUserDeviceFinish(&auth.WebAuthnFinishRequest{TransactionID: tx.TransactionID, Response: "{}"}))
+	u, _ := s.User("dude")
+	assert.EqualValues(t, 2, u.WebAuthnDevices)
+
+	tx, err = client.Auth.WebAuthn().SignInStart("dude", "https://example.com", nil, nil)
+	require.NoError(t, err)
+	assert.False(t, tx.Create)
+	_, err = client.Auth.WebAuthn().SignInFinish(&auth.WebAuthnFinishRequest{TransactionID: tx.TransactionID}, nil)
+	require.Error(t, err, "the response is required")
+	info, err = client.Auth.WebAuthn().SignInFinish(&auth.WebAuthnFinishRequest{TransactionID: tx.TransactionID, Response: "{}"}, nil)
+	require.NoError(t, err)
+	assert.False(t, info.FirstSeen)
+
+	_, err = client.Auth.WebAuthn().SignUpStart("dude", nil, "https://example.com")
+	require.Error(t, err)
+}
+
+func TestUnknownRoute(t *testing.T) {
+	s, _ := newTestServer(t)
+	res, err := http.Post(s.URL()+"/v1/auth/unknown", "application/json", nil)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.EqualValues(t, http.StatusNotFound, res.StatusCode)
+}
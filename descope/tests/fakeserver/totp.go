@@ -0,0 +1,122 @@
+package fakeserver
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // TOTP authenticator apps use HMAC-SHA1 (RFC 6238)
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/errors"
+)
+
+const totpPeriod = 30 * time.Second
+
+func (s *Server) totpRoutes(handle func(string, func(http.ResponseWriter, *http.Request))) {
+	handle(api.Routes.SignUpTOTP(), s.authHandler(s.signUpTOTP))
+	handle(api.Routes.UpdateTOTP(), s.authHandler(s.updateTOTP))
+	handle(api.Routes.VerifyTOTPCode(), s.authHandler(s.verifyTOTPCode))
+}
+
+// TOTPCode - returns the current code of the authenticator app of the user with the given login ID, as an app that
+// scanned the key returned by the TOTP sign up or update would show it
+func (s *Server) TOTPCode(loginID string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.findUser(loginID)
+	if u == nil || u.TOTPKey == "" {
+		return "", false
+	}
+	return totpCode(u.TOTPKey, time.Now()), true
+}
+
+// signUpTOTP creates the user right away with a new TOTP key, the user signs in by verifying a code of the key
+func (s *Server) signUpTOTP(w http.ResponseWriter, _ *http.Request, body *authBody) {
+	if body.ExternalID == "" {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("externalId"))
+		return
+	}
+	if s.findUser(body.ExternalID) != nil {
+		writeError(w, http.StatusBadRequest, userAlreadyExistsError)
+		return
+	}
+	u := &User{LoginIDs: []string{body.ExternalID}, TOTPKey: newTOTPKey()}
+	if body.User != nil {
+		u.Name, u.Email, u.Phone = body.User.Name, body.User.Email, body.User.Phone
+	}
+	s.addUser(u)
+	s.writeTOTPKey(w, u)
+}
+
+func (s *Server) updateTOTP(w http.ResponseWriter, r *http.Request, body *authBody) {
+	u, ok := s.updatedUser(w, r, body)
+	if !ok {
+		return
+	}
+	u.TOTPKey = newTOTPKey()
+	s.writeTOTPKey(w, u)
+}
+
+func (s *Server) writeTOTPKey(w http.ResponseWriter, u *User) {
+	provisioningURL := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + s.ProjectID + ":" + u.LoginIDs[0]}
+	provisioningURL.RawQuery = url.Values{"secret": {u.TOTPKey}, "issuer": {s.ProjectID}}.Encode()
+	writeJSON(w, map[string]string{"provisioningURL": provisioningURL.String(), "key": u.TOTPKey})
+}
+
+// verifyTOTPCode accepts the code of the current period and of the adjacent ones, to allow for clock drift
+func (s *Server) verifyTOTPCode(w http.ResponseWriter, r *http.Request, body *authBody) {
+	u := s.findUser(body.ExternalID)
+	if u == nil || u.TOTPKey == "" {
+		writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+		return
+	}
+	valid := false
+	now := time.Now()
+	for _, drift := range []time.Duration{-totpPeriod, 0, totpPeriod} {
+		if subtle.ConstantTimeCompare([]byte(totpCode(u.TOTPKey, now.Add(drift))), []byte(body.Code)) == 1 {
+			valid = true
+		}
+	}
+	if !valid {
+		writeError(w, http.StatusUnauthorized, errors.UnauthorizedError)
+		return
+	}
+	var customClaims map[string]any
+	if body.LoginOptions != nil {
+		if body.LoginOptions.Stepup || body.LoginOptions.MFA {
+			if _, _, ok := s.refreshUser(w, r); !ok {
+				return
+			}
+		}
+		customClaims = body.LoginOptions.CustomClaims
+	}
+	s.writeSession(w, u, false, customClaims, true)
+}
+
+func newTOTPKey() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+}
+
+// totpCode returns the 6 digits code of the given base32 key at the given time, as defined by RFC 6238
+func totpCode(key string, t time.Time) string {
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(key)
+	if err != nil {
+		return ""
+	}
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(t.Unix()/int64(totpPeriod.Seconds())))
+	mac := hmac.New(sha1.New, secret)
+	_, _ = mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
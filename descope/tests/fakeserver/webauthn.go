@@ -0,0 +1,124 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/errors"
+	"github.com/descope/go-sdk/descope/tests/internal/testkeys"
+)
+
+// webAuthnTransaction - a started WebAuthn ceremony, which is finished with the route that matches its kind
+type webAuthnTransaction struct {
+	ch     *challenge
+	create bool // registers a new device, finished by the sign up or update routes
+	update bool // adds a device to a signed in user, without creating a session
+}
+
+func (s *Server) webAuthnRoutes(handle func(string, func(http.ResponseWriter, *http.Request))) {
+	handle(api.Routes.WebAuthnSignUpStart(), s.authHandler(s.webAuthnSignUpStart))
+	handle(api.Routes.WebAuthnSignUpFinish(), s.authHandler(s.webAuthnFinish(func(tx *webAuthnTransaction) bool { return tx.create && !tx.update })))
+	handle(api.Routes.WebAuthnSignInStart(), s.authHandler(s.webAuthnSignInStart))
+	handle(api.Routes.WebAuthnSignInFinish(), s.authHandler(s.webAuthnFinish(func(tx *webAuthnTransaction) bool { return !tx.create })))
+	handle(api.Routes.WebAuthnSignUpOrInStart(), s.authHandler(s.webAuthnSignUpOrInStart))
+	handle(api.Routes.WebAuthnUpdateUserDeviceStart(), s.authHandler(s.webAuthnUpdateStart))
+	handle(api.Routes.WebAuthnUpdateUserDeviceFinish(), s.authHandler(s.webAuthnFinish(func(tx *webAuthnTransaction) bool { return tx.update })))
+}
+
+func (s *Server) webAuthnSignUpStart(w http.ResponseWriter, _ *http.Request, body *authBody) {
+	if body.User == nil || body.User.ExternalID == "" {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("user.externalId"))
+		return
+	}
+	if s.findUser(body.User.ExternalID) != nil {
+		writeError(w, http.StatusBadRequest, userAlreadyExistsError)
+		return
+	}
+	s.startWebAuthnSignUp(w, body.User.ExternalID, body.User.Name, body.Origin)
+}
+
+func (s *Server) startWebAuthnSignUp(w http.ResponseWriter, loginID, name, origin string) {
+	ch := &challenge{loginID: loginID, signUp: &User{LoginIDs: []string{loginID}, Name: name}, update: addWebAuthnDevice}
+	s.startWebAuthn(w, &webAuthnTransaction{ch: ch, create: true}, origin)
+}
+
+func (s *Server) webAuthnSignInStart(w http.ResponseWriter, r *http.Request, body *authBody) {
+	u := s.findUser(body.ExternalID)
+	if u == nil || u.WebAuthnDevices == 0 {
+		writeError(w, http.StatusBadRequest, userNotFoundError)
+		return
+	}
+	ch := &challenge{loginID: body.ExternalID}
+	if body.LoginOptions != nil {
+		if body.LoginOptions.Stepup || body.LoginOptions.MFA {
+			if _, _, ok := s.refreshUser(w, r); !ok {
+				return
+			}
+		}
+		ch.customClaims = body.LoginOptions.CustomClaims
+	}
+	s.startWebAuthn(w, &webAuthnTransaction{ch: ch}, body.Origin)
+}
+
+// webAuthnSignUpOrInStart signs in users that have a device, and signs up the others
+func (s *Server) webAuthnSignUpOrInStart(w http.ResponseWriter, _ *http.Request, body *authBody) {
+	if body.ExternalID == "" {
+		writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("externalId"))
+		return
+	}
+	if u := s.findUser(body.ExternalID); u != nil {
+		if u.WebAuthnDevices == 0 {
+			writeError(w, http.StatusBadRequest, userAlreadyExistsError)
+			return
+		}
+		s.startWebAuthn(w, &webAuthnTransaction{ch: &challenge{loginID: body.ExternalID}}, body.Origin)
+		return
+	}
+	s.startWebAuthnSignUp(w, body.ExternalID, "", body.Origin)
+}
+
+func (s *Server) webAuthnUpdateStart(w http.ResponseWriter, r *http.Request, body *authBody) {
+	if _, ok := s.updatedUser(w, r, body); !ok {
+		return
+	}
+	ch := &challenge{loginID: body.ExternalID, update: addWebAuthnDevice}
+	s.startWebAuthn(w, &webAuthnTransaction{ch: ch, create: true, update: true}, body.Origin)
+}
+
+func (s *Server) startWebAuthn(w http.ResponseWriter, tx *webAuthnTransaction, origin string) {
+	transactionID := testkeys.RandomID()
+	s.webAuthnTransactions[transactionID] = tx
+	options, _ := json.Marshal(map[string]any{"publicKey": map[string]any{"challenge": testkeys.RandomID(), "rp": map[string]string{"id": origin}}})
+	writeJSON(w, map[string]any{"transactionId": transactionID, "options": string(options), "create": tx.create})
+}
+
+// webAuthnFinish completes a transaction of the kind the route accepts. The fake server does not verify the response
+// of the authenticator, it only requires one.
+func (s *Server) webAuthnFinish(accepts func(tx *webAuthnTransaction) bool) authHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, body *authBody) {
+		tx, ok := s.webAuthnTransactions[body.TransactionID]
+		if !ok || !accepts(tx) {
+			writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("transactionId"))
+			return
+		}
+		if body.Response == "" {
+			writeError(w, http.StatusBadRequest, errors.NewInvalidArgumentError("response"))
+			return
+		}
+		delete(s.webAuthnTransactions, body.TransactionID)
+		if !tx.update {
+			s.completeAndWriteSession(w, tx.ch)
+			return
+		}
+		if _, _, err := s.complete(tx.ch); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, map[string]any{})
+	}
+}
+
+func addWebAuthnDevice(u *User) {
+	u.WebAuthnDevices++
+}
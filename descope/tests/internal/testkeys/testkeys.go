@@ -0,0 +1,73 @@
+// Package testkeys provides the key pair and the random IDs shared by the test packages that issue tokens, such as
+// descopetest and fakeserver.
+package testkeys
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+// KeyPair - a generated ES256 key pair that signs JWTs
+type KeyPair struct {
+	// Private - the signing key, with a key ID that is set in the header of the signed JWTs
+	Private jwk.Key
+	// Public - the public key that validates the signed JWTs
+	Public jwk.Key
+}
+
+// Generate - generates a new key pair
+func Generate() (*KeyPair, error) {
+	raw, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := jwk.FromRaw(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err = jwk.AssignKeyID(privateKey); err != nil {
+		return nil, err
+	}
+	_ = privateKey.Set(jwk.AlgorithmKey, jwa.ES256)
+	_ = privateKey.Set(jwk.KeyUsageKey, jwk.ForSignature)
+	publicKey, err := privateKey.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{Private: privateKey, Public: publicKey}, nil
+}
+
+// PublicKeyJSON - the public key as a JWK in JSON, which can be used as the PublicKey in the client configuration
+func (k *KeyPair) PublicKeyJSON() string {
+	b, _ := json.Marshal(k.Public)
+	return string(b)
+}
+
+// Sign - returns a JWT with the given claims signed with the private key
+func (k *KeyPair) Sign(claims map[string]any) (string, error) {
+	token := jwt.New()
+	for key, v := range claims {
+		if err := token.Set(key, v); err != nil {
+			return "", err
+		}
+	}
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.ES256, k.Private))
+	if err != nil {
+		return "", err
+	}
+	return string(signed), nil
+}
+
+// RandomID - returns a random hex string, such as for token and user IDs
+func RandomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
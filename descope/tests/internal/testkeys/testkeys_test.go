@@ -0,0 +1,33 @@
+package testkeys
+
+import (
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	keys, err := Generate()
+	require.NoError(t, err)
+	assert.NotEmpty(t, keys.Private.KeyID())
+	assert.EqualValues(t, keys.Private.KeyID(), keys.Public.KeyID())
+
+	signed, err := keys.Sign(map[string]any{jwt.SubjectKey: "dude", "drn": "DS"})
+	require.NoError(t, err)
+	token, err := jwt.Parse([]byte(signed), jwt.WithKey(jwa.ES256, keys.Public))
+	require.NoError(t, err)
+	assert.EqualValues(t, "dude", token.Subject())
+
+	public, err := jwk.ParseKey([]byte(keys.PublicKeyJSON()))
+	require.NoError(t, err)
+	assert.EqualValues(t, keys.Public.KeyID(), public.KeyID())
+}
+
+func TestRandomID(t *testing.T) {
+	assert.Len(t, RandomID(), 32)
+	assert.NotEqual(t, RandomID(), RandomID())
+}
@@ -0,0 +1,356 @@
+// Package replay provides an api.IHttpClient that records the interactions of the SDK with Descope to golden files,
+// and replays them in tests, to be set as the DefaultClient in the client configuration.
+//
+// Recorded interactions are redacted before they are written: the value of the Authorization header, the passwords and
+// the session and refresh tokens sent in request bodies and cookies are replaced, and the signature of every other JWT is
+// removed, so the golden files hold no usable credentials. The claims of the tokens in responses stay readable, since
+// replayed tokens cannot be verified, set ReplaceJWT to sign them again with a key the tested client trusts, such as
+// with descopetest.Minter.Resign.
+//
+// Tests usually create clients with New, which records when the DESCOPE_RECORD environment variable is set, and
+// replays otherwise:
+//
+//	replayer := replay.New(t, "testdata/otp.json", nil)
+//	descopeClient, _ := descope.NewDescopeClientWithConfig(&descope.Config{ProjectID: projectID, DefaultClient: replayer})
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/descope/go-sdk/descope/api"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/errors"
+)
+
+const (
+	// RecordEnvVariable - when set, New creates clients that record interactions instead of replaying them
+	RecordEnvVariable = "DESCOPE_RECORD"
+
+	redactedValue     = "REDACTED"
+	redactedBearer    = api.BearerAuthorizationPrefix + redactedValue
+	redactedSignature = redactedValue
+)
+
+// redactedFields - the JSON fields of request bodies whose values are replaced, at any depth
+var redactedFields = map[string]bool{
+	"password":    true,
+	"oldPassword": true,
+	"newPassword": true,
+	"sessionJwt":  true,
+	"refreshJwt":  true,
+}
+
+// cookieRegex matches the session and refresh cookies in a Cookie header, capturing everything but their values
+var cookieRegex = regexp.MustCompile(`(^|;\s*)(` + auth.SessionCookieName + `|` + auth.RefreshCookieName + `)=[^;]*`)
+
+// jwtRegex matches a JWT, capturing its header and payload
+var jwtRegex = regexp.MustCompile(`(eyJ[A-Za-z0-9_-]*\.eyJ[A-Za-z0-9_-]*)\.[A-Za-z0-9_-]+`)
+
+// UnexpectedRequestError - returned by a replaying client for a request that does not match the next recorded interaction
+var UnexpectedRequestError = errors.NewError("replay", "unexpected request")
+
+// Request - a recorded request
+type Request struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+}
+
+// Response - a recorded response
+type Response struct {
+	StatusCode int                 `json:"statusCode"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
+}
+
+// Interaction - a recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Client - records or replays interactions, see the package documentation.
+type Client struct {
+	// ReplaceJWT (optional, nil) - called with every redacted JWT in a replayed response, returns the JWT to replay instead.
+	ReplaceJWT func(redacted string) (string, error)
+	// Match (optional, same method, path, query and body) - reports whether a request matches a recorded one, both redacted.
+	Match func(recorded, actual Request) bool
+
+	goldenFile   string
+	next         api.IHttpClient
+	recording    bool
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     int
+}
+
+// NewRecorder - creates a client that sends requests with the given client (http.DefaultClient when nil), and records
+// them until Save is called.
+func NewRecorder(goldenFile string, next api.IHttpClient) *Client {
+	if next == nil {
+		next = http.DefaultClient
+	}
+	return &Client{goldenFile: goldenFile, next: next, recording: true}
+}
+
+// NewReplayer - creates a client that replays the interactions recorded in the given golden file, in order.
+func NewReplayer(goldenFile string) (*Client, error) {
+	b, err := os.ReadFile(goldenFile)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{goldenFile: goldenFile}
+	if err := json.Unmarshal(b, &c.interactions); err != nil {
+		return nil, fmt.Errorf("invalid golden file %s: %w", goldenFile, err)
+	}
+	return c, nil
+}
+
+// New - creates a recording client when the DESCOPE_RECORD environment variable is set, which saves the golden file
+// when the test ends, or a replaying client otherwise, which fails the test when not all interactions were replayed.
+// The given client is only used when recording.
+func New(t testing.TB, goldenFile string, next api.IHttpClient) *Client {
+	t.Helper()
+	if os.Getenv(RecordEnvVariable) != "" {
+		c := NewRecorder(goldenFile, next)
+		t.Cleanup(func() {
+			if err := c.Save(); err != nil {
+				t.Errorf("failed to save golden file: %s", err)
+			}
+		})
+		return c
+	}
+	c, err := NewReplayer(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to load golden file, set %s to record it: %s", RecordEnvVariable, err)
+	}
+	t.Cleanup(func() {
+		if err := c.Done(); err != nil {
+			t.Error(err)
+		}
+	})
+	return c
+}
+
+// Recording - reports whether the client records interactions
+func (c *Client) Recording() bool {
+	return c.recording
+}
+
+// Do - records or replays the given request
+func (c *Client) Do(r *http.Request) (*http.Response, error) {
+	req, err := newRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if c.recording {
+		return c.record(r, req)
+	}
+	return c.replay(r, req)
+}
+
+// Save - writes the recorded interactions to the golden file
+func (c *Client) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.goldenFile), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.goldenFile, append(b, '\n'), 0o644)
+}
+
+// Done - returns an error when a replaying client did not replay all the recorded interactions
+func (c *Client) Done() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.recording && c.replayed < len(c.interactions) {
+		next := c.interactions[c.replayed].Request
+		return fmt.Errorf("%d of %d interactions in %s were not replayed, next is %s %s", len(c.interactions)-c.replayed, len(c.interactions), c.goldenFile, next.Method, next.URL)
+	}
+	return nil
+}
+
+func (c *Client) record(r *http.Request, req Request) (*http.Response, error) {
+	res, err := c.next.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	body := []byte{}
+	if res.Body != nil {
+		body, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, Interaction{
+		Request:  req,
+		Response: Response{StatusCode: res.StatusCode, Headers: redactHeaders(res.Header), Body: redact(string(body))},
+	})
+	return res, nil
+}
+
+func (c *Client) replay(r *http.Request, req Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.replayed >= len(c.interactions) {
+		return nil, fmt.Errorf("%w %s %s, all interactions were replayed", UnexpectedRequestError, req.Method, req.URL)
+	}
+	interaction := c.interactions[c.replayed]
+	match := c.Match
+	if match == nil {
+		match = defaultMatch
+	}
+	if !match(interaction.Request, req) {
+		return nil, fmt.Errorf("%w %s %s, expected %s %s", UnexpectedRequestError, req.Method, req.URL, interaction.Request.Method, interaction.Request.URL)
+	}
+	c.replayed++
+
+	body, err := c.replaceJWTs(interaction.Response.Body)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	for k, values := range interaction.Response.Headers {
+		for _, v := range values {
+			if v, err = c.replaceJWTs(v); err != nil {
+				return nil, err
+			}
+			header.Add(k, v)
+		}
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode: interaction.Response.StatusCode,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}, nil
+}
+
+func (c *Client) replaceJWTs(s string) (string, error) {
+	if c.ReplaceJWT == nil {
+		return s, nil
+	}
+	var err error
+	res := jwtRegex.ReplaceAllStringFunc(s, func(redacted string) string {
+		replaced, replaceErr := c.ReplaceJWT(redacted)
+		if replaceErr != nil && err == nil {
+			err = replaceErr
+		}
+		return replaced
+	})
+	return res, err
+}
+
+func defaultMatch(recorded, actual Request) bool {
+	return recorded.Method == actual.Method && pathAndQuery(recorded.URL) == pathAndQuery(actual.URL) && recorded.Body == actual.Body
+}
+
+func pathAndQuery(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+		if j := strings.Index(url, "/"); j >= 0 {
+			return url[j:]
+		}
+		return "/"
+	}
+	return url
+}
+
+// newRequest returns the redacted form of the given request, keeping its body readable
+func newRequest(r *http.Request) (Request, error) {
+	req := Request{Method: r.Method, URL: redact(r.URL.String()), Headers: redactHeaders(r.Header)}
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return Request{}, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		req.Body = redactBody(string(body))
+	}
+	return req, nil
+}
+
+func redactHeaders(header http.Header) map[string][]string {
+	if len(header) == 0 {
+		return nil
+	}
+	headers := map[string][]string{}
+	for k, values := range header {
+		for _, v := range values {
+			switch http.CanonicalHeaderKey(k) {
+			case api.AuthorizationHeaderName:
+				if strings.HasPrefix(v, api.BearerAuthorizationPrefix) {
+					v = redactedBearer
+				}
+			case "Cookie":
+				v = cookieRegex.ReplaceAllString(v, "$1$2="+redactedValue)
+			}
+			headers[k] = append(headers[k], redact(v))
+		}
+	}
+	return headers
+}
+
+// redact removes the signature of every JWT in the given string
+func redact(s string) string {
+	return jwtRegex.ReplaceAllString(s, "$1."+redactedSignature)
+}
+
+// redactBody replaces the values of the redacted fields of a JSON request body, and removes the signature of every JWT.
+// Bodies without redacted fields are kept as they were sent.
+func redactBody(body string) string {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err == nil && redactFields(v) {
+		if b, err := json.Marshal(v); err == nil {
+			body = string(b)
+		}
+	}
+	return redact(body)
+}
+
+// redactFields replaces the values of the redacted fields in the given decoded JSON, and reports whether it replaced any
+func redactFields(v any) bool {
+	redacted := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			if redactedFields[k] {
+				if s, ok := value.(string); ok && s != "" {
+					v[k] = redactedValue
+					redacted = true
+				}
+				continue
+			}
+			redacted = redactFields(value) || redacted
+		}
+	case []any:
+		for _, value := range v {
+			redacted = redactFields(value) || redacted
+		}
+	}
+	return redacted
+}
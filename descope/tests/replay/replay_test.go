@@ -0,0 +1,198 @@
+package replay
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/descope/go-sdk/descope"
+	"github.com/descope/go-sdk/descope/auth"
+	"github.com/descope/go-sdk/descope/tests/descopetest"
+	"github.com/descope/go-sdk/descope/tests/fakeserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const email = "dude@example.com"
+
+// recordOTPFlow records an OTP sign up against a fake server, and returns the session JWT it received
+func recordOTPFlow(t *testing.T, goldenFile string) string {
+	srv, err := fakeserver.New("P2replay")
+	require.NoError(t, err)
+	defer srv.Close()
+	recorder := NewRecorder(goldenFile, nil)
+	assert.True(t, recorder.Recording())
+	client, err := descope.NewDescopeClientWithConfig(&descope.Config{ProjectID: srv.ProjectID, DescopeBaseURL: srv.URL(), PublicKey: srv.PublicKey(), DefaultClient: recorder})
+	require.NoError(t, err)
+
+	require.NoError(t, client.Auth.OTP().SignUp(auth.MethodEmail, email, nil))
+	code, ok := srv.OTPCode(email)
+	require.True(t, ok)
+	info, err := client.Auth.OTP().VerifyCode(auth.MethodEmail, email, code, nil)
+	require.NoError(t, err)
+	require.NoError(t, client.Management.User().Delete(srv.ManagementKey, email))
+	require.NoError(t, recorder.Save())
+	return info.SessionToken.JWT
+}
+
+// replayOTPFlow replays the recorded OTP sign up, since the code is random the golden file is read to find it.
+// The replayed JWTs are signed again by a minter unless resign is false.
+func replayOTPFlow(t *testing.T, goldenFile string, replayer *Client, resign bool) (*auth.AuthenticationInfo, error) {
+	interactions := []Interaction{}
+	b, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &interactions))
+	body := map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(interactions[1].Request.Body), &body))
+
+	minter, err := descopetest.NewMinter("P2replay")
+	require.NoError(t, err)
+	if resign {
+		replayer.ReplaceJWT = minter.Resign
+	}
+	client, err := descope.NewDescopeClientWithConfig(&descope.Config{ProjectID: minter.ProjectID, PublicKey: minter.PublicKey(), DefaultClient: replayer})
+	require.NoError(t, err)
+
+	require.NoError(t, client.Auth.OTP().SignUp(auth.MethodEmail, email, nil))
+	return client.Auth.OTP().VerifyCode(auth.MethodEmail, email, body["code"].(string), nil)
+}
+
+func TestRecordRedacts(t *testing.T) {
+	goldenFile := filepath.Join(t.TempDir(), "testdata", "otp.json")
+	sessionJwt := recordOTPFlow(t, goldenFile)
+
+	b, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	golden := string(b)
+	assert.NotContains(t, golden, "P2replay:")
+	assert.NotContains(t, golden, "fake-management-key")
+	assert.NotContains(t, golden, sessionJwt)
+	parts := strings.Split(sessionJwt, ".")
+	assert.Contains(t, golden, parts[0]+"."+parts[1]+"."+redactedSignature)
+	assert.Contains(t, golden, redactedBearer)
+}
+
+func TestRecordRedactsPasswordsAndTokens(t *testing.T) {
+	srv, err := fakeserver.New("P2replay")
+	require.NoError(t, err)
+	defer srv.Close()
+	goldenFile := filepath.Join(t.TempDir(), "password.json")
+	recorder := NewRecorder(goldenFile, nil)
+	client, err := descope.NewDescopeClientWithConfig(&descope.Config{ProjectID: srv.ProjectID, DescopeBaseURL: srv.URL(), PublicKey: srv.PublicKey(), DefaultClient: recorder})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	info, err := client.Auth.Password().SignUp(email, nil, "Secret1!signup", w)
+	require.NoError(t, err)
+	refreshJwt := ""
+	for _, c := range w.Result().Cookies() {
+		if c.Name == auth.RefreshCookieName {
+			refreshJwt = c.Value
+		}
+	}
+	require.NotEmpty(t, refreshJwt)
+	_, err = client.Auth.Password().ReplaceUserPassword(email, "Secret1!signup", "Secret1!replace", nil)
+	require.NoError(t, err)
+	r := mustRequest(t, http.MethodPost, "https://example.com")
+	descopetest.AddCookies(r, info.SessionToken.JWT, refreshJwt)
+	require.NoError(t, client.Auth.Password().UpdateUserPassword(email, "Secret1!update", r))
+	require.NoError(t, recorder.Save())
+
+	b, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	golden := string(b)
+	assert.NotContains(t, golden, "Secret1!")
+	assert.NotContains(t, golden, refreshJwt)
+	assert.NotContains(t, golden, info.SessionToken.JWT)
+	assert.Contains(t, golden, `\"password\":\"REDACTED\"`)
+	assert.Contains(t, golden, `\"oldPassword\":\"REDACTED\"`)
+	assert.Contains(t, golden, `\"newPassword\":\"REDACTED\"`)
+}
+
+func TestRedactRequest(t *testing.T) {
+	headers := redactHeaders(http.Header{"Cookie": {"DS=s.e.s; other=1; DSR=r.e.s"}})
+	assert.EqualValues(t, []string{"DS=REDACTED; other=1; DSR=REDACTED"}, headers["Cookie"])
+
+	assert.EqualValues(t, `{"count":10000000000000001,"loginId":"a","user":{"refreshJwt":"REDACTED"}}`,
+		redactBody(`{"user":{"refreshJwt":"token"},"count":10000000000000001,"loginId":"a"}`))
+	// bodies without redacted fields are kept as sent
+	assert.EqualValues(t, `{"loginId": "a"}`, redactBody(`{"loginId": "a"}`))
+	assert.EqualValues(t, "not json", redactBody("not json"))
+}
+
+func TestReplay(t *testing.T) {
+	goldenFile := filepath.Join(t.TempDir(), "otp.json")
+	recordOTPFlow(t, goldenFile)
+
+	replayer, err := NewReplayer(goldenFile)
+	require.NoError(t, err)
+	assert.False(t, replayer.Recording())
+	info, err := replayOTPFlow(t, goldenFile, replayer, true)
+	require.NoError(t, err)
+	assert.EqualValues(t, email, info.User.Email)
+	assert.NotEmpty(t, info.SessionToken.ID)
+	assert.ErrorContains(t, replayer.Done(), "1 of 3 interactions")
+
+	// unexpected request
+	_, err = replayer.Do(mustRequest(t, http.MethodPost, "https://api.descope.com/v1/auth/logout"))
+	assert.ErrorIs(t, err, UnexpectedRequestError)
+	assert.ErrorContains(t, err, "expected POST")
+}
+
+func TestReplayWithoutReplacedJWTs(t *testing.T) {
+	goldenFile := filepath.Join(t.TempDir(), "otp.json")
+	recordOTPFlow(t, goldenFile)
+
+	replayer, err := NewReplayer(goldenFile)
+	require.NoError(t, err)
+	// the redacted tokens fail validation
+	_, err = replayOTPFlow(t, goldenFile, replayer, false)
+	assert.Error(t, err)
+}
+
+func TestReplayAllRequests(t *testing.T) {
+	goldenFile := filepath.Join(t.TempDir(), "otp.json")
+	recordOTPFlow(t, goldenFile)
+	replayer, err := NewReplayer(goldenFile)
+	require.NoError(t, err)
+	replayer.Match = func(recorded, actual Request) bool { return recorded.Method == actual.Method }
+	for i := 0; i < 3; i++ {
+		res, err := replayer.Do(mustRequest(t, http.MethodPost, "https://api.descope.com/anything"))
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+	assert.NoError(t, replayer.Done())
+	_, err = replayer.Do(mustRequest(t, http.MethodPost, "https://api.descope.com/anything"))
+	assert.ErrorIs(t, err, UnexpectedRequestError)
+}
+
+func TestMissingGoldenFile(t *testing.T) {
+	_, err := NewReplayer(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	goldenFile := filepath.Join(t.TempDir(), "otp.json")
+	t.Run("record", func(t *testing.T) {
+		t.Setenv(RecordEnvVariable, "1")
+		c := New(t, goldenFile, nil)
+		assert.True(t, c.Recording())
+	})
+	// saved when the recording test ended
+	_, err := os.Stat(goldenFile)
+	require.NoError(t, err)
+	t.Run("replay", func(t *testing.T) {
+		c := New(t, goldenFile, nil)
+		assert.False(t, c.Recording())
+	})
+}
+
+func mustRequest(t *testing.T, method, url string) *http.Request {
+	r, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	return r
+}
@@ -57,8 +57,18 @@ func main() {
 	router.HandleFunc("/otp/signup", handleSignUp).Methods(http.MethodGet)
 	router.HandleFunc("/otp/verify", handleVerify).Methods(http.MethodGet)
 
-	router.HandleFunc("/oauth", handleOAuth).Methods(http.MethodGet)
-	router.HandleFunc("/oauth/exchange", finalizeOAuth).Methods(http.MethodGet)
+	oauthStart, oauthCallback := auth.NewOAuthHandlers(client.Auth.OAuth(), auth.OAuthHandlerOptions{
+		RedirectHandlerOptions: auth.RedirectHandlerOptions{
+			CallbackURL:  "https://localhost:8085/oauth/exchange",
+			PostLoginURL: "/private",
+			OnError: func(w http.ResponseWriter, r *http.Request, err error) {
+				setError(w, err.Error())
+			},
+		},
+		Provider: auth.OAuthFacebook,
+	})
+	router.Handle("/oauth", oauthStart).Methods(http.MethodGet)
+	router.Handle("/oauth/exchange", oauthCallback).Methods(http.MethodGet)
 
 	router.HandleFunc("/magiclink/signin", handleMagicLinkSignIn).Methods(http.MethodGet)
 	router.HandleFunc("/magiclink/signup", handleMagicLinkSignUp).Methods(http.MethodGet)
@@ -181,34 +191,6 @@ func handleVerify(w http.ResponseWriter, r *http.Request) {
 	sendSuccessAuthResponse(w, authInfo)
 }
 
-func handleOAuth(w http.ResponseWriter, r *http.Request) {
-	provider := auth.OAuthFacebook
-	if p, ok := r.URL.Query()["provider"]; ok {
-		provider = auth.OAuthProvider(p[0])
-	}
-	_, err := client.Auth.OAuth().Start(provider, "https://localhost:8085/oauth/exchange", nil, nil, w)
-	if err != nil {
-		setError(w, err.Error())
-	}
-}
-
-func finalizeOAuth(w http.ResponseWriter, r *http.Request) {
-	var code string
-	if codes, ok := r.URL.Query()["code"]; ok {
-		code = codes[0]
-	}
-	if code == "" {
-		setError(w, "code is empty")
-		return
-	}
-	authInfo, err := client.Auth.OAuth().ExchangeToken(code, w)
-	if err != nil {
-		setError(w, err.Error())
-		return
-	}
-	sendSuccessAuthResponse(w, authInfo)
-}
-
 func sendSuccessAuthResponse(w http.ResponseWriter, authInfo *auth.AuthenticationInfo) {
 	helpTxt := "You have properly authenticated, you can check for your JWT in the cookie\n"
 	mr, _ := json.MarshalIndent(authInfo, "", "")
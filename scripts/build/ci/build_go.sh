@@ -4,6 +4,18 @@ echo 'Building main package..'
 go mod tidy && go mod vendor && go build -v ./...
 echo 'Building gin package..'
 (cd descope/gin && go mod tidy && go mod vendor && go build)
+echo 'Building echo package..'
+(cd descope/echo && go mod tidy && go mod vendor && go build)
+echo 'Building chi package..'
+(cd descope/chi && go mod tidy && go mod vendor && go build)
+echo 'Building fiber package..'
+(cd descope/fiber && go mod tidy && go mod vendor && go build)
+echo 'Building grpc package..'
+(cd descope/grpc && go mod tidy && go mod vendor && go build)
+echo 'Building otel package..'
+(cd descope/otel && go mod tidy && go mod vendor && go build)
+echo 'Building prometheus package..'
+(cd descope/prometheus && go mod tidy && go mod vendor && go build)
 echo 'Building mux web app example..'
 (cd examples/webapp && go mod tidy && go mod vendor && go build)
 echo 'Building gin web app example..'